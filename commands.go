@@ -0,0 +1,1853 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"bsky_follower/internal/api"
+	"bsky_follower/internal/backup"
+	"bsky_follower/internal/control"
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/digest"
+	"bsky_follower/internal/dryrun"
+	"bsky_follower/internal/logger"
+	"bsky_follower/internal/metrics"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/notify"
+	"bsky_follower/internal/report"
+	"bsky_follower/internal/review"
+	"bsky_follower/internal/schedule"
+	"bsky_follower/internal/service"
+	"bsky_follower/internal/stats"
+	"bsky_follower/internal/ui"
+)
+
+// statsInterval is how often the daemon refreshes today's stats snapshot
+const statsInterval = time.Hour
+
+// command is a non-interactive subcommand
+type command struct {
+	usage string
+	run   func(cfg *models.Config, args []string) error
+}
+
+var commands = map[string]command{
+	"blocklist": {
+		usage: "Manage the blocklist: add, remove, list, import",
+		run:   manageBlocklist,
+	},
+	"campaign": {
+		usage: "Manage follow campaigns with a target and dates: create, list, report, stop",
+		run:   manageCampaigns,
+	},
+	"capacity": {
+		usage: "Estimate weekly candidate yield per source and when the queue runs dry",
+		run:   planCapacity,
+	},
+	"daemon": {
+		usage: "Run the follow queue processor without the UI",
+		run:   runDaemon,
+	},
+	"digest": {
+		usage: "Render an activity digest from a template",
+		run:   renderDigest,
+	},
+	"export-candidates": {
+		usage: "Export pending candidates for external review",
+		run:   exportCandidates,
+	},
+	"export-state": {
+		usage: "Back up users, queue and account lists as one JSON document",
+		run:   exportState,
+	},
+	"followers-of": {
+		usage: "Discover and queue the followers of an account like mine",
+		run:   discoverFollowersOf,
+	},
+	"history": {
+		usage: "Show or export the audit trail of every action, filtered by handle, action, actor or age",
+		run:   showHistory,
+	},
+	"import-decisions": {
+		usage: "Apply reviewer decisions from an exported candidate file",
+		run:   importDecisions,
+	},
+	"import-state": {
+		usage: "Restore a state document, resolving conflicts by skip, overwrite or merge",
+		run:   importState,
+	},
+	"import-targets": {
+		usage: "Resolve and queue my own targets from a text or CSV file",
+		run:   importTargets,
+	},
+	"maintain": {
+		usage: "Analyze the database and vacuum it if much of it is free space",
+		run:   maintainDatabase,
+	},
+	"pause": {
+		usage: "Halt all writes for the account until resumed or for a while",
+		run:   pauseAccount,
+	},
+	"prune": {
+		usage: "Delete or archive never-followed users that have gone stale",
+		run:   pruneUsers,
+	},
+	"protect": {
+		usage: "Manage accounts cleanup never touches: add, remove, list, import",
+		run:   manageProtected,
+	},
+	"queue": {
+		usage: "Pause or resume follow queue processing: pause, resume, status",
+		run:   manageQueue,
+	},
+	"rejected": {
+		usage: "List candidates the targeting filters keep out of the queue, with the reason",
+		run:   showRejected,
+	},
+	"report": {
+		usage: "Write a growth report as Markdown or HTML, or send it as a notification",
+		run:   writeReport,
+	},
+	"resume": {
+		usage: "Lift a pause on the account",
+		run:   resumeAccount,
+	},
+	"rising": {
+		usage: "List pending targets whose follower count grows fastest",
+		run:   showRising,
+	},
+	"schedule": {
+		usage: "Preview planned follows per hour, optionally as an iCal file",
+		run:   previewSchedule,
+	},
+	"search": {
+		usage: "Discover and queue the authors of recent posts about keywords or hashtags",
+		run:   discoverByKeywords,
+	},
+	"second-degree": {
+		usage: "Discover and queue the accounts many of my followers follow",
+		run:   discoverSecondDegree,
+	},
+	"stats": {
+		usage: "Show daily growth snapshots, optionally recording one now",
+		run:   showStats,
+	},
+	"strategies": {
+		usage: "List the discovery strategies the daemon runs, or run one now",
+		run:   manageStrategies,
+	},
+	"sync-follows": {
+		usage: "Mark every account I already follow as followed, with its follow record",
+		run:   syncFollows,
+	},
+	"sync-followers": {
+		usage: "Refresh the snapshot of accounts that follow me and who followed back",
+		run:   syncFollowers,
+	},
+	"unfollow": {
+		usage: "Manage resumable unfollow campaigns: start, run, list, auto",
+		run:   manageUnfollows,
+	},
+}
+
+// runCommand dispatches a subcommand by name
+func runCommand(cfg *models.Config, name string, args []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage()
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	return cmd.run(cfg, args)
+}
+
+// printUsage lists the available subcommands
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Usage: bsky_follower [--ephemeral] [--dry-run] [--no-tui] [command] [flags]")
+	fmt.Println("Run without a command to start the interactive UI.")
+	fmt.Println("With --no-tui, or when stdout isn't a terminal or TERM is dumb, it instead")
+	fmt.Println("authenticates, fetches top users and processes the queue with plain output.")
+	fmt.Println("With --ephemeral, all state is kept in memory and nothing is written to disk.")
+	fmt.Println("With --dry-run, follows and unfollows are only simulated and reported.")
+	fmt.Println("\nCommands:")
+	for _, name := range names {
+		fmt.Printf("  %-20s %s\n", name, commands[name].usage)
+	}
+}
+
+// openStore opens the configured database
+func openStore(cfg *models.Config) (db.Store, error) {
+	return db.NewStore(cfg, logger.GetAPILogger())
+}
+
+// newService opens the store and builds a service around it
+func newService(cfg *models.Config) (*service.Service, error) {
+	store, err := openStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewService(cfg, api.NewClient(cfg.Timeout, logger.GetAPILogger()), store, logger.GetAPILogger()), nil
+}
+
+func runDaemon(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	pprofAddr := fs.String("pprof", cfg.PprofAddr, "serve pprof endpoints on this address (e.g. localhost:6060)")
+	controlAddr := fs.String("control", cfg.ControlAddr, "serve the control API on this address (e.g. localhost:6061)")
+	fs.Parse(args)
+
+	if !cfg.Ephemeral {
+		logger.InitLogger()
+		defer logger.Close()
+	}
+	log := logger.GetAPILogger()
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	// The first SIGINT or SIGTERM stops the service gracefully; a second one
+	// kills the process as usual
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+		log.Info("Shutting down, finishing the current follow")
+		svc.Stop()
+	}()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	// On the first run, learn who I already follow so they are never re-followed
+	if _, err := svc.BackfillFollowsOnce(session); err != nil {
+		log.Error("Failed to backfill my follows: %v", err)
+	}
+	if _, err := svc.ReconcileFollowBacks(session); err != nil {
+		log.Error("Failed to sync followers, using stored snapshot: %v", err)
+	} else if _, err := svc.QueueAutoUnfollows(session); err != nil {
+		log.Error("Failed to queue auto-unfollows: %v", err)
+	}
+
+	// Carry on from where the last run stopped, crash or not
+	if err := svc.ResumeCheckpoint(session); err != nil {
+		log.Error("Failed to resume the processor checkpoint: %v", err)
+	}
+	if err := svc.LoadQueue(); err != nil {
+		return err
+	}
+
+	if *pprofAddr != "" {
+		metrics.StartPprof(*pprofAddr, log)
+	}
+	if *controlAddr != "" {
+		control.Serve(*controlAddr, svc, log)
+	}
+	if cfg.MetricsInterval > 0 {
+		monitor := metrics.NewMonitor(cfg.MetricsInterval, log)
+		monitor.Track("follow_queue", svc.QueueLen)
+		monitor.Track("follow_queue_ready", func() int { return svc.QueueStats().Ready })
+		monitor.Track("follow_queue_per_hour", func() int { return svc.QueueStats().PerHour })
+		monitor.Track("follow_queue_dead_letters", func() int { return svc.QueueStats().DeadLetters })
+		monitor.Track("followed_set", svc.FollowedCount)
+		go monitor.Run()
+	}
+
+	var jobs sync.WaitGroup
+	run := func(job func()) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job()
+		}()
+	}
+	run(func() { svc.RunDailyStats(session, statsInterval) })
+	run(func() { svc.RunAccountHealth(session) })
+	if svc.Notifies() {
+		run(svc.RunDailySummary)
+	}
+	if svc.Notifies() || cfg.ReportDir != "" {
+		run(svc.RunWeeklyReport)
+	}
+	run(func() { svc.ProcessUnfollowCampaigns(session) })
+	run(func() { svc.RunFollowerRefresh(session) })
+	run(func() { svc.RunFollowBackReconciliation(session) })
+	run(svc.RunMaintenance)
+	if cfg.PruneAge > 0 {
+		run(svc.RunPruning)
+	}
+	run(func() { svc.RunStrategies(session) })
+	run(func() { svc.RunCampaigns(session) })
+
+	svc.ProcessFollowQueue(session)
+	jobs.Wait()
+
+	count, err := svc.PersistQueue()
+	if err != nil {
+		return fmt.Errorf("failed to persist the follow queue: %w", err)
+	}
+	log.Info("Saved retry state of %d queued users, stopped", count)
+	return printDryRun(svc)
+}
+
+// printDryRun prints what a dry run would have done, if it was one, and
+// saves the report to the report directory when one is configured
+func printDryRun(svc *service.Service) error {
+	if !svc.DryRun() {
+		return nil
+	}
+	if err := dryrun.Table(os.Stdout, svc.Simulated()); err != nil {
+		return err
+	}
+	paths, err := svc.SaveDryRunReport()
+	for _, path := range paths {
+		fmt.Printf("Saved the dry-run report to %s\n", path)
+	}
+	return err
+}
+
+func planCapacity(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("capacity", flag.ExitOnError)
+	weeks := fs.Int("weeks", 4, "how many recent weeks of discoveries to measure")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if err := printPause(svc); err != nil {
+		return err
+	}
+
+	report, err := svc.CapacityReport(time.Duration(*weeks) * 7 * 24 * time.Hour)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New qualifying candidates over the last %d weeks:\n", *weeks)
+	for _, source := range report.Sources {
+		fmt.Printf("  %-30s %6d  %8.1f/week\n", source.Source, source.Candidates, source.PerWeek)
+	}
+	fmt.Printf("\nInflow:   %8.1f candidates/week\n", report.InflowPerWeek)
+	fmt.Printf("Capacity: %8.1f follows/week\n", report.FollowsPerWeek)
+	fmt.Printf("Pending:  %8d candidates\n", report.Pending)
+
+	if report.RunsDry {
+		days := report.RunsDryIn.Hours() / 24
+		fmt.Printf("\nWarning: the queue runs dry in %.1f days (%s) at this rate; add more sources\n",
+			days, time.Now().Add(report.RunsDryIn).Format("2006-01-02"))
+	} else {
+		fmt.Println("\nSources keep up with the follow rate")
+	}
+	return nil
+}
+
+func exportCandidates(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("export-candidates", flag.ExitOnError)
+	out := fs.String("out", "candidates.csv", "output file (.csv or .json)")
+	format := fs.String("format", "", "output format: csv or json (default: from file extension)")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = review.FormatFromPath(*out)
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	pending := false
+	users, err := store.QueryUsers(context.Background(), db.UserFilter{Followed: &pending}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer f.Close()
+
+	if err := review.ExportCandidates(f, users, *format); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported candidates to %s\n", *out)
+	return nil
+}
+
+func importDecisions(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("import-decisions", flag.ExitOnError)
+	in := fs.String("in", "candidates.csv", "reviewed candidate file (.csv or .json)")
+	format := fs.String("format", "", "input format: csv or json (default: from file extension)")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = review.FormatFromPath(*in)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *in, err)
+	}
+	defer f.Close()
+
+	decisions, err := review.ReadDecisions(f, *format)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	result, err := svc.ApplyReview(decisions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied %d decisions: %d approved, %d rejected, %d blocked, %d unknown handles\n",
+		result.Applied(), result.Approved, result.Rejected, result.Blocked, result.Unknown)
+	if result.Mutuals > 0 {
+		fmt.Printf("Kept %d mutuals out of the blocklist\n", result.Mutuals)
+	}
+	return nil
+}
+
+func exportState(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("export-state", flag.ExitOnError)
+	out := fs.String("out", "state.json", "output file")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	state, err := svc.ExportState()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer f.Close()
+
+	if err := backup.Write(f, state); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d users, %d queued, %d blocklisted and %d protected to %s\n",
+		len(state.Users), len(state.Queue), len(state.Blocklist), len(state.Protected), *out)
+	return nil
+}
+
+func importState(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("import-state", flag.ExitOnError)
+	in := fs.String("in", "state.json", "state document to import")
+	conflict := fs.String("conflict", backup.ConflictMerge, "what to do with existing records: skip, overwrite or merge")
+	fs.Parse(args)
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *in, err)
+	}
+	defer f.Close()
+
+	state, err := backup.Read(f)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	report, err := svc.ImportState(state, *conflict)
+	if err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		name   string
+		result backup.ImportResult
+	}{
+		{"users", report.Users},
+		{"blocklist", report.Blocklist},
+		{"protected", report.Protected},
+	} {
+		fmt.Printf("%-10s %d added, %d updated, %d skipped\n",
+			section.name, section.result.Added, section.result.Updated, section.result.Skipped)
+	}
+	return nil
+}
+
+func discoverSecondDegree(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("second-degree", flag.ExitOnError)
+	sample := fs.Int("sample", 20, "how many of my followers to look through, 0 for all")
+	minOverlap := fs.Int("min-overlap", 2, "only accounts followed by at least this many of them")
+	limit := fs.Int("limit", 50, "stop after queuing this many accounts, 0 for all")
+	priority := fs.Int("priority", service.DefaultTargetPriority, "base priority of the queued accounts")
+	minFollowers := fs.Int("min-followers", cfg.MinFollowers, "skip accounts with fewer followers")
+	maxFollowers := fs.Int("max-followers", cfg.MaxFollowers, "skip accounts with more followers, 0 for no limit")
+	minPosts := fs.Int("min-posts", cfg.MinPosts, "skip accounts with fewer posts")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	filter := service.CandidateFilter{MinFollowers: *minFollowers, MaxFollowers: *maxFollowers, MinPosts: *minPosts}
+	result, err := svc.DiscoverSecondDegree(session, *sample, *minOverlap, filter, *priority, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Queued %d of %d followers of my followers (%d already known, %d filtered out)\n",
+		result.Added, result.Scanned, result.Skipped, result.Filtered)
+	return nil
+}
+
+func discoverFollowersOf(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("followers-of", flag.ExitOnError)
+	limit := fs.Int("limit", 100, "stop after queuing this many accounts, 0 for all")
+	priority := fs.Int("priority", service.DefaultTargetPriority, "priority of the queued accounts")
+	minFollowers := fs.Int("min-followers", cfg.MinFollowers, "skip accounts with fewer followers")
+	maxFollowers := fs.Int("max-followers", cfg.MaxFollowers, "skip accounts with more followers, 0 for no limit")
+	minPosts := fs.Int("min-posts", cfg.MinPosts, "skip accounts with fewer posts")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: followers-of [-limit n] [-priority n] [-min-followers n] [-max-followers n] [-min-posts n] <handle>")
+	}
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	filter := service.CandidateFilter{MinFollowers: *minFollowers, MaxFollowers: *maxFollowers, MinPosts: *minPosts}
+	result, err := svc.DiscoverFollowersOf(session, fs.Arg(0), filter, *priority, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Queued %d of %d followers of %s (%d already known, %d filtered out)\n",
+		result.Added, result.Scanned, fs.Arg(0), result.Skipped, result.Filtered)
+	return nil
+}
+
+func discoverByKeywords(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "queue at most this many authors per keyword, 0 for all")
+	priority := fs.Int("priority", service.DefaultTargetPriority, "base priority the author score is added to")
+	minFollowers := fs.Int("min-followers", cfg.MinFollowers, "skip authors with fewer followers")
+	maxFollowers := fs.Int("max-followers", cfg.MaxFollowers, "skip authors with more followers, 0 for no limit")
+	minPosts := fs.Int("min-posts", cfg.MinPosts, "skip authors with fewer posts")
+	fs.Parse(args)
+
+	keywords := fs.Args()
+	if len(keywords) == 0 {
+		keywords = cfg.Keywords
+	}
+	if len(keywords) == 0 {
+		return fmt.Errorf("usage: search [-limit n] [-priority n] [-min-followers n] [-max-followers n] [-min-posts n] <keyword|#hashtag>... (or set BSKY_KEYWORDS)")
+	}
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	filter := service.CandidateFilter{MinFollowers: *minFollowers, MaxFollowers: *maxFollowers, MinPosts: *minPosts}
+	result, err := svc.DiscoverByKeywords(session, keywords, filter, *priority, *limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Queued %d of %d authors posting about %s (%d already known, %d filtered out)\n",
+		result.Added, result.Scanned, strings.Join(keywords, ", "), result.Skipped, result.Filtered)
+	return nil
+}
+
+// manageStrategies lists the registered discovery strategies, or runs the
+// named ones once
+func manageStrategies(cfg *models.Config, args []string) error {
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	strategies := svc.Strategies()
+	if len(args) == 0 {
+		for _, strategy := range strategies {
+			state := "enabled"
+			if !svc.StrategyEnabled(strategy) {
+				state = "disabled"
+			}
+			fmt.Printf("%-40s every %-8s %s\n", strategy.Name(), strategy.Schedule(), state)
+		}
+		fmt.Printf("%d strategies\n", len(strategies))
+		return nil
+	}
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		found := false
+		for _, strategy := range strategies {
+			if strategy.Name() != name {
+				continue
+			}
+			found = true
+			result, err := svc.Discover(context.Background(), session, strategy)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: queued %d new accounts, skipped %d, filtered out %d\n", name, result.Added, result.Skipped, result.Filtered)
+		}
+		if !found {
+			return fmt.Errorf("no strategy named %s; run `strategies` to list them", name)
+		}
+	}
+	return nil
+}
+
+func importTargets(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("import-targets", flag.ExitOnError)
+	file := fs.String("file", "", "file with one handle or DID per line, or a CSV with handle and priority columns")
+	priority := fs.Int("priority", service.DefaultTargetPriority, "priority for targets without one")
+	at := fs.String("at", "", "don't follow new targets before this time: +duration, HH:MM, YYYY-MM-DD [HH:MM] or RFC 3339")
+	yes := fs.Bool("yes", false, "don't ask before importing")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("usage: import-targets -file <path> [-priority n] [-at time]")
+	}
+
+	var notBefore time.Time
+	if *at != "" {
+		t, err := schedule.ParseTime(*at, time.Now())
+		if err != nil {
+			return err
+		}
+		notBefore = t
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *file, err)
+	}
+	defer f.Close()
+
+	targets, err := service.ReadTargets(f)
+	if err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("About to resolve and queue %d targets from %s.", len(targets), *file)
+	if !notBefore.IsZero() {
+		summary = fmt.Sprintf("About to resolve %d targets from %s and queue them from %s.", len(targets), *file, notBefore.Format("2006-01-02 15:04"))
+	}
+	rows := make([]ui.PreviewRow, len(targets))
+	for i, target := range targets {
+		rows[i] = ui.PreviewRow{Subject: target.Subject, Detail: fmt.Sprintf("priority %d", *priority)}
+		if target.Priority != 0 {
+			rows[i].Detail = fmt.Sprintf("priority %d", target.Priority)
+		}
+	}
+	accepted, ok, err := preview(cfg, "Import targets", summary, rows, *yes || cfg.DryRun)
+	if err != nil || !ok {
+		return err
+	}
+	var kept []service.TargetSpec
+	for i, target := range targets {
+		if accepted[i] {
+			kept = append(kept, target)
+		}
+	}
+	if len(kept) == 0 {
+		fmt.Println("Every target was denied, nothing to import")
+		return nil
+	}
+	targets = kept
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	result, err := svc.ImportTargets(session, targets, *priority, notBefore)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d targets, skipped %d already stored or blocklisted\n", result.Added, result.Skipped)
+	if !notBefore.IsZero() && result.Added > 0 {
+		fmt.Printf("New targets will not be followed before %s\n", notBefore.Format("2006-01-02 15:04"))
+	}
+	if result.Merged > 0 {
+		fmt.Printf("Merged %d known accounts under their new handles\n", result.Merged)
+	}
+	if len(result.Failed) > 0 {
+		fmt.Printf("Could not resolve %d: %s\n", len(result.Failed), strings.Join(result.Failed, ", "))
+	}
+	return nil
+}
+
+func showHistory(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	handle := fs.String("handle", "", "only show events for this handle")
+	action := fs.String("action", "", "only show events with this action (follow, unfollow, skip, block, rate-limit, evict, expire)")
+	result := fs.String("result", "", "only show events with this result (success, failed)")
+	actor := fs.String("actor", "", "only show events taken by this account DID")
+	since := fs.Duration("since", 0, "only show events from this long ago on")
+	limit := fs.Int("limit", 50, "maximum number of events, 0 for all")
+	out := fs.String("out", "", "export to this file (.csv or .json) instead of printing")
+	fs.Parse(args)
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	filter := db.EventFilter{Actor: *actor, Action: *action, Result: *result}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+	if *handle != "" {
+		users, err := store.LoadUsers(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if user.Handle == *handle {
+				filter.DID = user.DID
+				break
+			}
+		}
+		if filter.DID == "" {
+			return fmt.Errorf("no stored user with handle %s", *handle)
+		}
+	}
+
+	events, err := store.QueryEvents(context.Background(), filter, *limit)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer f.Close()
+
+		if err := backup.WriteEvents(f, events, review.FormatFromPath(*out)); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d events to %s\n", len(events), *out)
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No events recorded")
+		return nil
+	}
+
+	for _, event := range events {
+		subject := event.Handle
+		if subject == "" {
+			subject = event.DID
+		}
+		line := fmt.Sprintf("%s  %-10s %-30s %-7s via %s",
+			event.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			event.Action,
+			subject,
+			event.Result,
+			event.Strategy,
+		)
+		if event.Error != "" {
+			line += ": " + event.Error
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func renderDigest(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	since := fs.Duration("since", 24*time.Hour, "period to summarize")
+	tmplName := fs.String("template", cfg.DigestTemplate, "built-in template name or template file path")
+	out := fs.String("out", "", "write the digest to this file instead of stdout")
+	fs.Parse(args)
+
+	tmpl, err := digest.Load(*tmplName)
+	if err != nil {
+		return err
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	summary, err := stats.Compute(context.Background(), store, time.Now().Add(-*since))
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return digest.Render(os.Stdout, tmpl, summary)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer f.Close()
+	return digest.Render(f, tmpl, summary)
+}
+
+func writeReport(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	days := fs.Int("days", 7, "number of days the report covers")
+	format := fs.String("format", report.FormatMarkdown, "report format: "+strings.Join(report.Formats, " or "))
+	out := fs.String("out", "", "write the report to this file instead of stdout")
+	send := fs.Bool("send", false, "send the Markdown report through the configured notifiers instead")
+	fs.Parse(args)
+
+	notifiers := notify.FromConfig(cfg)
+	if *send && len(notifiers) == 0 {
+		return fmt.Errorf("no notification integration is configured")
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	to := time.Now()
+	r, err := report.Build(context.Background(), store, to.AddDate(0, 0, -*days), to)
+	if err != nil {
+		return err
+	}
+
+	if *send {
+		var text strings.Builder
+		if err := report.Markdown(&text, r); err != nil {
+			return err
+		}
+		if err := notify.SendAll(context.Background(), notifiers, text.String()); err != nil {
+			return err
+		}
+		fmt.Printf("Sent the growth report to %d notifiers\n", len(notifiers))
+		return nil
+	}
+	if *out == "" {
+		return report.Render(os.Stdout, r, *format)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer f.Close()
+	return report.Render(f, r, *format)
+}
+
+func showRising(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("rising", flag.ExitOnError)
+	days := fs.Int("days", 7, "measure growth over this many days")
+	limit := fs.Int("limit", 20, "maximum number of targets to show")
+	refresh := fs.Bool("refresh", false, "log in and observe follower counts first")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if *refresh {
+		session, err := svc.Login()
+		if err != nil {
+			return err
+		}
+		if _, err := svc.RefreshFollowerCounts(session, 0); err != nil {
+			return err
+		}
+	}
+
+	rising, err := svc.RisingTargets(time.Duration(*days)*24*time.Hour, *limit)
+	if err != nil {
+		return err
+	}
+	if len(rising) == 0 {
+		fmt.Println("No growing targets observed yet; follower counts are sampled by the daemon")
+		return nil
+	}
+
+	for _, r := range rising {
+		fmt.Printf("%-35s %8d followers  %+8.1f/day  %+6.2f%%/day\n",
+			r.Handle, r.Followers, r.PerDay, r.GrowthPerDay*100)
+	}
+	return nil
+}
+
+func showRejected(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("rejected", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "maximum number of candidates to show, 0 for all")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	users, err := svc.Rejected(*limit)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		fmt.Println("No candidates rejected by the targeting filters")
+		return nil
+	}
+
+	for _, user := range users {
+		fmt.Printf("%-35s %8d followers  %s\n", user.Handle, user.Followers, user.Rejection)
+	}
+	return nil
+}
+
+func previewSchedule(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	days := fs.Int("days", 7, "number of days to preview")
+	ical := fs.String("ical", "", "also write the schedule to this iCal (.ics) file")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if err := svc.LoadQueue(); err != nil {
+		return err
+	}
+
+	if err := printPause(svc); err != nil {
+		return err
+	}
+
+	plan := svc.PreviewSchedule(time.Duration(*days) * 24 * time.Hour)
+	if err := schedule.WriteText(os.Stdout, plan); err != nil {
+		return err
+	}
+
+	if *ical == "" {
+		return nil
+	}
+	f, err := os.Create(*ical)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *ical, err)
+	}
+	defer f.Close()
+
+	if err := schedule.WriteICal(f, plan); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote schedule to %s\n", *ical)
+	return nil
+}
+
+func showStats(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	days := fs.Int("days", 30, "number of days to show")
+	record := fs.Bool("record", false, "log in and record today's snapshot first")
+	by := fs.String("by", "", "show follow-back analytics per source, strategy or campaign instead")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if *by != "" {
+		return printFollowBackAnalytics(svc, *by)
+	}
+
+	if *record {
+		session, err := svc.Login()
+		if err != nil {
+			return err
+		}
+		if err := svc.LoadQueue(); err != nil {
+			return err
+		}
+		if _, err := svc.RecordDailyStats(session); err != nil {
+			return err
+		}
+	}
+
+	if err := printPause(svc); err != nil {
+		return err
+	}
+
+	snapshots, err := svc.DailyStats(time.Now().AddDate(0, 0, -*days))
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No daily stats recorded")
+		return nil
+	}
+
+	fmt.Printf("%-10s  %9s  %9s  %6s  %7s\n", "day", "followers", "following", "queued", "follows")
+	for _, snapshot := range snapshots {
+		fmt.Printf("%-10s  %9d  %9d  %6d  %7d\n",
+			snapshot.Day.Format("2006-01-02"),
+			snapshot.Followers,
+			snapshot.Following,
+			snapshot.QueueDepth,
+			snapshot.FollowsPerformed,
+		)
+	}
+	return nil
+}
+
+// printFollowBackAnalytics prints the follow-back rate, median time to
+// follow back and churn of my follows per group
+func printFollowBackAnalytics(svc *service.Service, by string) error {
+	groups, err := svc.FollowBackAnalytics(by)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Println("No follows recorded")
+		return nil
+	}
+
+	fmt.Printf("%-30s  %8s  %8s  %6s  %10s  %6s\n", by, "followed", "back", "rate", "median", "churn")
+	for _, group := range groups {
+		fmt.Printf("%-30s  %8d  %8d  %5.1f%%  %10s  %5.1f%%\n",
+			group.Group,
+			group.Followed,
+			group.FollowedBack,
+			group.Rate()*100,
+			group.Median(),
+			group.Churn()*100,
+		)
+	}
+	return nil
+}
+
+func pauseAccount(cfg *models.Config, args []string) error {
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	reason := fs.String("reason", "paused by hand", "why the account is paused")
+	duration := fs.Duration("for", 0, "resume automatically after this long (default: until resumed)")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if err := svc.PauseAccount("", *reason, models.PauseManual, *duration); err != nil {
+		return err
+	}
+	return printPause(svc)
+}
+
+func resumeAccount(cfg *models.Config, args []string) error {
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if err := svc.ResumeAccount(""); err != nil {
+		return err
+	}
+	fmt.Println("Account resumed")
+	return nil
+}
+
+func manageQueue(cfg *models.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: queue pause|resume|status|next|reprioritize")
+	}
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	switch args[0] {
+	case "pause":
+		if err := svc.PauseQueue(); err != nil {
+			return err
+		}
+		fmt.Println("Follow queue paused; queued items are kept")
+	case "resume":
+		if err := svc.ResumeQueue(); err != nil {
+			return err
+		}
+		fmt.Println("Follow queue resumed")
+	case "status":
+		paused, err := svc.QueuePaused()
+		if err != nil {
+			return err
+		}
+		if paused {
+			fmt.Println("Follow queue is paused")
+		} else {
+			fmt.Println("Follow queue is running")
+		}
+		return printPause(svc)
+	case "next":
+		return showUpcoming(svc, args[1:])
+	case "reprioritize":
+		return reprioritizeQueue(svc, args[1:])
+	default:
+		return fmt.Errorf("unknown queue subcommand: %s", args[0])
+	}
+	return nil
+}
+
+// showUpcoming prints or exports the accounts the queue will follow next
+func showUpcoming(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("queue next", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "number of upcoming follows")
+	out := fs.String("out", "", "export to this file (.csv or .json) instead of printing")
+	fs.Parse(args)
+
+	items, err := svc.UpcomingFollows(*limit)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]backup.QueueEntry, len(items))
+	for i, item := range items {
+		entries[i] = backup.QueueEntry{
+			DID:      item.User.DID,
+			Handle:   item.User.Handle,
+			Priority: item.Priority,
+			Attempts: item.Attempts,
+			NextTry:  item.NextTry,
+		}
+	}
+
+	if *out == "" {
+		if len(entries) == 0 {
+			fmt.Println("The follow queue is empty")
+			return nil
+		}
+		for i, entry := range entries {
+			fmt.Printf("%3d. %-40s priority %-3d from %s\n", i+1, entry.Handle, entry.Priority, entry.NextTry.Format("2006-01-02 15:04"))
+		}
+		return printPause(svc)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	defer f.Close()
+
+	if err := backup.WriteQueue(f, entries, review.FormatFromPath(*out)); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d upcoming follows to %s\n", len(entries), *out)
+	return nil
+}
+
+// reprioritizeQueue sets the priority of the pending users matching the flags
+func reprioritizeQueue(svc *service.Service, args []string) error {
+	fs := flag.NewFlagSet("queue reprioritize", flag.ExitOnError)
+	priority := fs.Int("priority", 0, "new priority")
+	source := fs.String("source", "", "only users from this discovery source, or any source of this kind (e.g. list)")
+	minFollowers := fs.Int("min-followers", 0, "only users with at least this many followers")
+	maxFollowers := fs.Int("max-followers", 0, "only users with at most this many followers")
+	bio := fs.String("bio", "", "only users whose bio contains this text")
+	fs.Parse(args)
+
+	filter := db.UserFilter{
+		Source:       *source,
+		MinFollowers: *minFollowers,
+		MaxFollowers: *maxFollowers,
+		BioContains:  *bio,
+	}
+	if filter == (db.UserFilter{}) {
+		return fmt.Errorf("usage: queue reprioritize -priority n [-source s] [-min-followers n] [-max-followers n] [-bio text]")
+	}
+
+	changed, err := svc.Reprioritize(filter, *priority)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Set %d pending users to priority %d\n", changed, *priority)
+	return nil
+}
+
+// printPause tells the user when the account is paused, since nothing will
+// be written until it is resumed
+func printPause(svc *service.Service) error {
+	pause, err := svc.AccountPause("")
+	if err != nil || pause == nil {
+		return err
+	}
+
+	until := "until resumed"
+	if !pause.Until.IsZero() {
+		until = "until " + pause.Until.Local().Format("2006-01-02 15:04")
+	}
+	fmt.Printf("Account paused (%s) %s: %s\n\n", pause.Source, until, pause.Reason)
+	return nil
+}
+
+// unfollowSummary describes the pending unfollows of the running campaigns
+// and how fast they go out, or returns "" when none are pending
+func unfollowSummary(svc *service.Service) (string, error) {
+	campaigns, err := svc.UnfollowCampaigns()
+	if err != nil {
+		return "", err
+	}
+	pending, running := 0, 0
+	for _, campaign := range campaigns {
+		if !campaign.CompletedAt.IsZero() {
+			continue
+		}
+		targets, err := svc.UnfollowTargets(campaign.ID, models.UnfollowPending)
+		if err != nil {
+			return "", err
+		}
+		if len(targets) > 0 {
+			pending += len(targets)
+			running++
+		}
+	}
+	if pending == 0 {
+		return "", nil
+	}
+
+	summary := fmt.Sprintf("About to unfollow %d accounts from %d campaigns", pending, running)
+	if _, perHour := svc.HourlyRates(); perHour > 0 {
+		took := time.Duration(float64(pending) / float64(perHour) * float64(time.Hour)).Round(time.Minute)
+		summary += fmt.Sprintf(" at ~%d/hour, about %s", perHour, took)
+	}
+	return summary + ".", nil
+}
+
+// preview lists the accounts a batch action is about to affect and lets the
+// user deny some of them, returning which rows to go ahead with, or ok false
+// if the user cancelled. Like confirm, it accepts every row without asking
+// when yes is set or there is no terminal to show the list on.
+func preview(cfg *models.Config, title, summary string, rows []ui.PreviewRow, yes bool) (accepted []bool, ok bool, err error) {
+	if yes || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		accepted = make([]bool, len(rows))
+		for i := range accepted {
+			accepted[i] = true
+		}
+		return accepted, true, nil
+	}
+	return ui.Preview(cfg, title, summary, rows)
+}
+
+// previewUnfollows lists the pending targets of the unfinished unfollow
+// campaigns and marks those the user denies skipped, returning ok false if
+// the user cancelled
+func previewUnfollows(cfg *models.Config, svc *service.Service, summary string, yes bool) (ok bool, err error) {
+	campaigns, err := svc.UnfollowCampaigns()
+	if err != nil {
+		return false, err
+	}
+	var targets []models.UnfollowTarget
+	var rows []ui.PreviewRow
+	for _, campaign := range campaigns {
+		if !campaign.CompletedAt.IsZero() {
+			continue
+		}
+		pending, err := svc.UnfollowTargets(campaign.ID, models.UnfollowPending)
+		if err != nil {
+			return false, err
+		}
+		for _, target := range pending {
+			subject := target.Handle
+			if subject == "" {
+				subject = target.DID
+			}
+			targets = append(targets, target)
+			rows = append(rows, ui.PreviewRow{Subject: subject, Detail: fmt.Sprintf("campaign %d (%s)", campaign.ID, campaign.Name)})
+		}
+	}
+
+	accepted, ok, err := preview(cfg, "Unfollow", summary, rows, yes)
+	if err != nil || !ok {
+		return false, err
+	}
+	denied := 0
+	for i, target := range targets {
+		if accepted[i] {
+			continue
+		}
+		if err := svc.SkipUnfollowTarget(target, "denied in the preview"); err != nil {
+			return false, err
+		}
+		denied++
+	}
+	if denied > 0 {
+		fmt.Printf("Skipped %d denied targets\n", denied)
+	}
+	return true, nil
+}
+
+// confirm shows what a batch action is about to do and asks whether to go
+// ahead. It doesn't ask when yes is set or stdin isn't a terminal, so
+// scripts and services run as before.
+func confirm(summary string, yes bool) bool {
+	if yes {
+		return true
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return true
+	}
+
+	fmt.Printf("%s Continue? [y/N] ", summary)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Println("Cancelled, nothing was changed")
+	return false
+}
+
+// defaultPruneAge is used by the prune command when BSKY_PRUNE_DAYS is unset
+const defaultPruneAge = 90 * 24 * time.Hour
+
+func pruneUsers(cfg *models.Config, args []string) error {
+	age := cfg.PruneAge
+	if age == 0 {
+		age = defaultPruneAge
+	}
+
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	days := fs.Int("days", int(age.Hours()/24), "prune users not checked for this many days")
+	archive := fs.Bool("archive", cfg.PruneArchive, "move pruned users to users_archive instead of deleting them")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	count, err := svc.PruneUsers(time.Duration(*days)*24*time.Hour, *archive)
+	if err != nil {
+		return err
+	}
+
+	action := "Deleted"
+	if *archive {
+		action = "Archived"
+	}
+	fmt.Printf("%s %d stale users\n", action, count)
+	return nil
+}
+
+func maintainDatabase(cfg *models.Config, args []string) error {
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	report, err := svc.MaintainDatabase()
+	if err != nil {
+		return err
+	}
+
+	if report.Vacuumed {
+		fmt.Printf("Vacuumed database, %d of %d pages were free\n", report.FreePages, report.TotalPages)
+	} else {
+		fmt.Println("Database analyzed, no vacuum needed")
+	}
+	return nil
+}
+
+func syncFollows(cfg *models.Config, args []string) error {
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	report, err := svc.BackfillFollows(session)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("I follow %d accounts: %d newly marked as followed, %d added\n",
+		report.Follows, report.Marked, report.Added)
+	return nil
+}
+
+func syncFollowers(cfg *models.Config, args []string) error {
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	session, err := svc.Login()
+	if err != nil {
+		return err
+	}
+
+	report, err := svc.ReconcileFollowBacks(session)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Synced %d followers\n", report.Followers)
+	fmt.Printf("%d of %d followed accounts follow back (%d new, %d lost), %d mutuals\n",
+		report.FollowedBack, report.Followed, report.New, report.Lost, report.Mutuals)
+	return nil
+}
+
+// accountList is the set of service operations behind a list command
+type accountList struct {
+	name       string
+	noun       string // names the entries in messages
+	reasonHelp string
+	add        func(svc *service.Service, subject, reason string) error
+	remove     func(svc *service.Service, subject string) error
+	load       func(svc *service.Service) ([]models.BlocklistEntry, error)
+	importFile func(svc *service.Service, r io.Reader, reason string) (int, error)
+}
+
+var (
+	blocklist = accountList{
+		name:       "blocklist",
+		noun:       "blocklist entries",
+		reasonHelp: "why the account is blocklisted",
+		add:        (*service.Service).Block,
+		remove:     (*service.Service).Unblock,
+		load:       (*service.Service).Blocklist,
+		importFile: (*service.Service).ImportBlocklist,
+	}
+	protectedList = accountList{
+		name:       "protect",
+		noun:       "protected accounts",
+		reasonHelp: "why the account is protected",
+		add:        (*service.Service).Protect,
+		remove:     (*service.Service).Unprotect,
+		load:       (*service.Service).ProtectedAccounts,
+		importFile: (*service.Service).ImportProtected,
+	}
+)
+
+func manageBlocklist(cfg *models.Config, args []string) error {
+	return manageList(cfg, blocklist, args)
+}
+
+func manageProtected(cfg *models.Config, args []string) error {
+	return manageList(cfg, protectedList, args)
+}
+
+// manageList implements the add, remove, list and import actions of a list command
+func manageList(cfg *models.Config, list accountList, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s add|remove|list|import", list.name)
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet(list.name+" "+action, flag.ExitOnError)
+	reason := fs.String("reason", "", list.reasonHelp)
+	file := fs.String("file", "", "file with one handle or DID per line (import)")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	switch action {
+	case "add":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: %s add [-reason text] <handle|did>...", list.name)
+		}
+		for _, subject := range fs.Args() {
+			if err := list.add(svc, subject, *reason); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Added %d %s\n", fs.NArg(), list.noun)
+	case "remove":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: %s remove <handle|did>...", list.name)
+		}
+		for _, subject := range fs.Args() {
+			if err := list.remove(svc, subject); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Removed %d %s\n", fs.NArg(), list.noun)
+	case "list":
+		entries, err := list.load(svc)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s  %-40s %s\n", entry.AddedOn.Local().Format("2006-01-02"), entry.Subject, entry.Reason)
+		}
+		fmt.Printf("%d entries\n", len(entries))
+	case "import":
+		if *file == "" {
+			return fmt.Errorf("usage: %s import -file <path> [-reason text]", list.name)
+		}
+		f, err := os.Open(*file)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", *file, err)
+		}
+		defer f.Close()
+
+		count, err := list.importFile(svc, f, *reason)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d %s\n", count, list.noun)
+	default:
+		return fmt.Errorf("unknown %s action: %s", list.name, action)
+	}
+	return nil
+}
+
+// manageUnfollows implements the start, run, list and auto actions of the unfollow command
+func manageUnfollows(cfg *models.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: unfollow start|run|list|auto")
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("unfollow "+action, flag.ExitOnError)
+	name := fs.String("name", "", "campaign name (start)")
+	file := fs.String("file", "", "file with one handle or DID per line (start)")
+	yes := fs.Bool("yes", false, "don't ask before unfollowing (run)")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	switch action {
+	case "start":
+		subjects := fs.Args()
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", *file, err)
+			}
+			defer f.Close()
+
+			listed, err := service.ReadSubjects(f)
+			if err != nil {
+				return err
+			}
+			subjects = append(subjects, listed...)
+		}
+		if *name == "" || len(subjects) == 0 {
+			return fmt.Errorf("usage: unfollow start -name <name> [-file <path>] [<handle|did>...]")
+		}
+
+		session, err := svc.Login()
+		if err != nil {
+			return err
+		}
+		campaign, count, err := svc.StartUnfollowCampaign(session, *name, subjects)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created campaign %d (%s) with %d targets; run it with `unfollow run` or the daemon\n",
+			campaign.ID, campaign.Name, count)
+	case "run":
+		summary, err := unfollowSummary(svc)
+		if err != nil {
+			return err
+		}
+		if summary != "" {
+			ok, err := previewUnfollows(cfg, svc, summary, *yes || svc.DryRun())
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		session, err := svc.Login()
+		if err != nil {
+			return err
+		}
+		if err := svc.RunUnfollowCampaigns(session); err != nil {
+			return err
+		}
+		if svc.DryRun() {
+			return printDryRun(svc)
+		}
+		fmt.Println("All unfollow campaigns complete")
+	case "auto":
+		if cfg.UnfollowAfter <= 0 {
+			return fmt.Errorf("auto-unfollow is off; set BSKY_AUTO_UNFOLLOW_DAYS")
+		}
+		session, err := svc.Login()
+		if err != nil {
+			return err
+		}
+		if _, err := svc.ReconcileFollowBacks(session); err != nil {
+			return err
+		}
+		count, err := svc.QueueAutoUnfollows(session)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Queued %d accounts that did not follow back; run them with `unfollow run` or the daemon\n", count)
+	case "list":
+		if err := printPause(svc); err != nil {
+			return err
+		}
+		campaigns, err := svc.UnfollowCampaigns()
+		if err != nil {
+			return err
+		}
+		for _, campaign := range campaigns {
+			targets, err := svc.UnfollowTargets(campaign.ID, "")
+			if err != nil {
+				return err
+			}
+			counts := make(map[string]int)
+			for _, target := range targets {
+				counts[target.Status]++
+			}
+
+			state := "running"
+			if !campaign.CompletedAt.IsZero() {
+				state = "completed " + campaign.CompletedAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%4d  %-20s %-27s pending %d, unfollowed %d, verified %d, failed %d, skipped %d\n",
+				campaign.ID, campaign.Name, state,
+				counts[models.UnfollowPending],
+				counts[models.UnfollowDone],
+				counts[models.UnfollowVerified],
+				counts[models.UnfollowFailed],
+				counts[models.UnfollowSkipped],
+			)
+		}
+		fmt.Printf("%d campaigns\n", len(campaigns))
+	default:
+		return fmt.Errorf("unknown unfollow action: %s", action)
+	}
+	return nil
+}
+
+func manageCampaigns(cfg *models.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: campaign create|list|report|stop")
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("campaign "+action, flag.ExitOnError)
+	name := fs.String("name", "", "campaign name")
+	target := fs.Int("target", 0, "follows to make (create)")
+	strategies := fs.String("strategies", "", "comma-separated search:<keyword> and followers-of:<handle> specs (create)")
+	start := fs.String("start", "", "start date as YYYY-MM-DD, empty for now (create)")
+	days := fs.Int("days", 0, "run for this many days, 0 for no end date (create)")
+	perDay := fs.Int("per-day", 0, "most follows per day, 0 to spread the target over the days (create)")
+	fs.Parse(args)
+
+	svc, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	switch action {
+	case "create":
+		campaign := models.Campaign{
+			Name:          *name,
+			Target:        *target,
+			StartsAt:      time.Now(),
+			FollowsPerDay: *perDay,
+		}
+		for _, spec := range strings.Split(*strategies, ",") {
+			if spec = strings.TrimSpace(spec); spec != "" {
+				campaign.Strategies = append(campaign.Strategies, spec)
+			}
+		}
+		if *start != "" {
+			day, err := time.ParseInLocation("2006-01-02", *start, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid -start %q, expected YYYY-MM-DD", *start)
+			}
+			campaign.StartsAt = day
+		}
+		if *days > 0 {
+			campaign.EndsAt = campaign.StartsAt.AddDate(0, 0, *days)
+		}
+
+		created, err := svc.CreateCampaign(campaign)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created campaign %d (%s); the daemon runs it from %s\n",
+			created.ID, created.Name, created.StartsAt.Local().Format("2006-01-02 15:04"))
+	case "list":
+		campaigns, err := svc.Campaigns()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, campaign := range campaigns {
+			progress, err := svc.CampaignProgress(campaign, now)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%4d  %-20s %-10s %d/%d followed, %d pending, %.0f%% followed back\n",
+				campaign.ID, campaign.Name, progress.Status,
+				progress.Followed, campaign.Target, progress.Pending, progress.FollowBackRate()*100)
+		}
+		fmt.Printf("%d campaigns\n", len(campaigns))
+	case "report":
+		if *name == "" {
+			return fmt.Errorf("usage: campaign report -name <name>")
+		}
+		campaign, err := svc.Campaign(*name)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return fmt.Errorf("no campaign named %s", *name)
+		}
+		progress, err := svc.CampaignProgress(*campaign, time.Now())
+		if err != nil {
+			return err
+		}
+		printCampaignReport(progress)
+	case "stop":
+		if *name == "" {
+			return fmt.Errorf("usage: campaign stop -name <name>")
+		}
+		if err := svc.StopCampaign(*name); err != nil {
+			return err
+		}
+		fmt.Printf("Stopped campaign %s; its queued candidates stay in the queue\n", *name)
+	default:
+		return fmt.Errorf("unknown campaign action: %s", action)
+	}
+	return nil
+}
+
+// printCampaignReport prints the progress of one follow campaign
+func printCampaignReport(progress *service.CampaignProgress) {
+	campaign := progress.Campaign
+	fmt.Printf("Campaign:      %s (%s)\n", campaign.Name, progress.Status)
+	fmt.Printf("Strategies:    %s\n", strings.Join(campaign.Strategies, ", "))
+	period := campaign.StartsAt.Local().Format("2006-01-02") + " onwards"
+	if !campaign.EndsAt.IsZero() {
+		period = campaign.StartsAt.Local().Format("2006-01-02") + " – " + campaign.EndsAt.Local().Format("2006-01-02")
+	}
+	fmt.Printf("Period:        %s\n", period)
+	if !campaign.FinishedAt.IsZero() {
+		fmt.Printf("Finished:      %s\n", campaign.FinishedAt.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("Followed:      %d of %d (%.0f%%)\n", progress.Followed, campaign.Target,
+		float64(progress.Followed)/float64(campaign.Target)*100)
+	if progress.Expected > 0 {
+		pace := "on pace"
+		if progress.Followed < progress.Expected {
+			pace = fmt.Sprintf("%d behind", progress.Expected-progress.Followed)
+		}
+		fmt.Printf("Expected:      %d by now (%s)\n", progress.Expected, pace)
+	}
+	fmt.Printf("Followed back: %d (%.1f%%)\n", progress.FollowedBack, progress.FollowBackRate()*100)
+	fmt.Printf("Pending:       %d\n", progress.Pending)
+	fmt.Printf("Rejected:      %d\n", progress.Rejected)
+	if progress.DailyLimit > 0 {
+		fmt.Printf("Today:         %d of %d per day\n", progress.Today, progress.DailyLimit)
+	} else {
+		fmt.Printf("Today:         %d\n", progress.Today)
+	}
+}
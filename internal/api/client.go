@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"bsky_follower/internal/models"
@@ -15,6 +18,7 @@ const apiBase = "https://bsky.social/xrpc"
 // Client represents a Bluesky API client
 type Client struct {
 	httpClient *http.Client
+	transport  *transport
 	logger     Logger
 }
 
@@ -27,8 +31,10 @@ type Logger interface {
 
 // NewClient creates a new Bluesky API client
 func NewClient(timeout time.Duration, logger Logger) *Client {
+	t := &transport{base: http.DefaultTransport}
 	return &Client{
-		httpClient: &http.Client{Timeout: timeout},
+		httpClient: &http.Client{Timeout: timeout, Transport: t},
+		transport:  t,
 		logger:     logger,
 	}
 }
@@ -36,12 +42,12 @@ func NewClient(timeout time.Duration, logger Logger) *Client {
 // Login authenticates with the Bluesky API
 func (c *Client) Login(identifier, password string) (*models.Session, error) {
 	c.logger.Info("Attempting to login with identifier: %s", identifier)
-	
+
 	payload := map[string]string{
 		"identifier": identifier,
 		"password":   password,
 	}
-	
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		c.logger.Error("Failed to marshal login payload", "error", err)
@@ -74,62 +80,121 @@ func (c *Client) Login(identifier, password string) (*models.Session, error) {
 	}
 
 	session.CreatedAt = time.Now()
+	session.ExpiresAt = TokenExpiry(session.AccessJwt)
 	c.logger.Info("Successfully logged in as: %s (DID: %s)", session.Handle, session.Did)
 	return &session, nil
 }
 
-// GetFollowerCount retrieves the follower count for a user
-func (c *Client) GetFollowerCount(session *models.Session, actor string) (int, error) {
-	c.logger.Debug("Getting follower count for actor: %s", actor)
-	
+// GetProfile retrieves the profile counts for a user
+func (c *Client) GetProfile(session *models.Session, actor string) (*models.Profile, error) {
+	c.logger.Debug("Getting profile for actor: %s", actor)
+
 	url := apiBase + "/app.bsky.actor.getProfile?actor=" + actor
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		c.logger.Error("Failed to create profile request", "error", err)
-		return 0, fmt.Errorf("failed to create profile request: %w", err)
+		return nil, fmt.Errorf("failed to create profile request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-	
+	req.Header.Set("Authorization", session.Bearer())
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.logger.Error("Failed to fetch profile", "error", err)
-		return 0, fmt.Errorf("failed to fetch profile: %w", err)
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("Profile fetch failed with status: %d", resp.StatusCode)
-		return 0, fmt.Errorf("profile fetch failed with status: %d", resp.StatusCode)
+		if err := accountStatus(resp, actor); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("profile fetch failed with status: %d", resp.StatusCode)
 	}
 
 	var profile models.Profile
 	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
 		c.logger.Error("Failed to decode profile response", "error", err)
-		return 0, fmt.Errorf("failed to decode profile response: %w", err)
+		return nil, fmt.Errorf("failed to decode profile response: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// maxProfilesPerRequest is the getProfiles batch limit
+const maxProfilesPerRequest = 25
+
+// GetProfiles retrieves profiles for up to 25 actors in one request. Actors
+// that cannot be found are left out of the result.
+func (c *Client) GetProfiles(session *models.Session, actors []string) ([]models.Profile, error) {
+	if len(actors) > maxProfilesPerRequest {
+		return nil, fmt.Errorf("at most %d actors per profiles request, got %d", maxProfilesPerRequest, len(actors))
+	}
+	c.logger.Debug("Getting %d profiles", len(actors))
+
+	params := url.Values{}
+	for _, actor := range actors {
+		params.Add("actors", actor)
+	}
+
+	req, err := http.NewRequest("GET", apiBase+"/app.bsky.actor.getProfiles?"+params.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Failed to create profiles request: %v", err)
+		return nil, fmt.Errorf("failed to create profiles request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to fetch profiles: %v", err)
+		return nil, fmt.Errorf("failed to fetch profiles: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Profiles fetch failed with status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("profiles fetch failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Profiles []models.Profile `json:"profiles"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Failed to decode profiles response: %v", err)
+		return nil, fmt.Errorf("failed to decode profiles response: %w", err)
 	}
 
+	return result.Profiles, nil
+}
+
+// GetFollowerCount retrieves the follower count for a user
+func (c *Client) GetFollowerCount(session *models.Session, actor string) (int, error) {
+	profile, err := c.GetProfile(session, actor)
+	if err != nil {
+		return 0, err
+	}
 	return profile.FollowersCount, nil
 }
 
 // GetDID retrieves the DID for a handle
 func (c *Client) GetDID(session *models.Session, handle string) (string, error) {
 	c.logger.Debug("Getting DID for handle: %s", handle)
-	
+
 	url := apiBase + "/com.atproto.identity.resolveHandle?handle=" + handle
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		c.logger.Error("Failed to create resolve handle request", "error", err)
 		return "", fmt.Errorf("failed to create resolve handle request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-	
+	req.Header.Set("Authorization", session.Bearer())
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.logger.Error("Failed to resolve handle", "error", err)
 		return "", fmt.Errorf("failed to resolve handle: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("Handle resolution failed with status: %d", resp.StatusCode)
 		return "", fmt.Errorf("handle resolution failed with status: %d", resp.StatusCode)
@@ -146,6 +211,94 @@ func (c *Client) GetDID(session *models.Session, handle string) (string, error)
 	return result.Did, nil
 }
 
+// GetFollowers retrieves one page of an actor's followers. An empty cursor
+// starts from the beginning; the returned cursor is empty on the last page.
+func (c *Client) GetFollowers(session *models.Session, actor, cursor string) ([]models.Actor, string, error) {
+	c.logger.Debug("Getting followers for actor: %s (cursor: %q)", actor, cursor)
+
+	params := url.Values{}
+	params.Set("actor", actor)
+	params.Set("limit", "100")
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequest("GET", apiBase+"/app.bsky.graph.getFollowers?"+params.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Failed to create followers request: %v", err)
+		return nil, "", fmt.Errorf("failed to create followers request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to fetch followers: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch followers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Followers fetch failed with status: %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("followers fetch failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Followers []models.Actor `json:"followers"`
+		Cursor    string         `json:"cursor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Failed to decode followers response: %v", err)
+		return nil, "", fmt.Errorf("failed to decode followers response: %w", err)
+	}
+
+	return result.Followers, result.Cursor, nil
+}
+
+// GetFollows retrieves one page of the accounts an actor follows. For my
+// own follows each profile's Viewer.Following is the URI of the follow
+// record. An empty cursor starts from the beginning; the returned cursor is
+// empty on the last page.
+func (c *Client) GetFollows(session *models.Session, actor, cursor string) ([]models.Profile, string, error) {
+	c.logger.Debug("Getting follows for actor: %s (cursor: %q)", actor, cursor)
+
+	params := url.Values{}
+	params.Set("actor", actor)
+	params.Set("limit", "100")
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequest("GET", apiBase+"/app.bsky.graph.getFollows?"+params.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Failed to create follows request: %v", err)
+		return nil, "", fmt.Errorf("failed to create follows request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to fetch follows: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch follows: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Follows fetch failed with status: %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("follows fetch failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Follows []models.Profile `json:"follows"`
+		Cursor  string           `json:"cursor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Failed to decode follows response: %v", err)
+		return nil, "", fmt.Errorf("failed to decode follows response: %w", err)
+	}
+
+	return result.Follows, result.Cursor, nil
+}
+
 // FollowUser follows a user on Bluesky
 func (c *Client) FollowUser(session *models.Session, handleOrDid string, simulate bool) error {
 	if simulate {
@@ -154,7 +307,7 @@ func (c *Client) FollowUser(session *models.Session, handleOrDid string, simulat
 	}
 
 	c.logger.Info("Following user: %s", handleOrDid)
-	
+
 	payload := map[string]interface{}{
 		"collection": "app.bsky.graph.follow",
 		"repo":       session.Did,
@@ -162,7 +315,7 @@ func (c *Client) FollowUser(session *models.Session, handleOrDid string, simulat
 			Subject: handleOrDid,
 		},
 	}
-	
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		c.logger.Error("Failed to marshal follow payload", "error", err)
@@ -175,15 +328,26 @@ func (c *Client) FollowUser(session *models.Session, handleOrDid string, simulat
 		return fmt.Errorf("failed to create follow request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+session.AccessJwt)
-	
+	req.Header.Set("Authorization", session.Bearer())
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.logger.Error("Failed to execute follow request", "error", err)
 		return fmt.Errorf("failed to execute follow request: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err := rateLimitError(resp)
+		c.logger.Error("Follow rate limited: %v", err)
+		return err
+	}
+	// Following someone I follow already leaves me following them, which is
+	// all the caller asked for
+	if alreadyFollowing(resp) {
+		c.logger.Info("Already following user: %s", handleOrDid)
+		return nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("Follow failed with status: %d", resp.StatusCode)
 		return fmt.Errorf("follow failed with status: %d", resp.StatusCode)
@@ -191,4 +355,23 @@ func (c *Client) FollowUser(session *models.Session, handleOrDid string, simulat
 
 	c.logger.Info("Successfully followed user: %s", handleOrDid)
 	return nil
-} 
\ No newline at end of file
+}
+
+// xrpcError is the body of an XRPC error response
+type xrpcError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// alreadyFollowing reports whether a refused follow was refused because the
+// follow record exists already
+func alreadyFollowing(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusConflict {
+		return false
+	}
+	var body xrpcError
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err != nil {
+		return false
+	}
+	return body.Error == "AlreadyExists" || strings.Contains(strings.ToLower(body.Message), "already follow")
+}
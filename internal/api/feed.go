@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"bsky_follower/internal/models"
+)
+
+// GetAuthorPosts retrieves up to limit of an account's most recent posts,
+// newest first. Replies and reposts of other accounts' posts are left out.
+func (c *Client) GetAuthorPosts(session *models.Session, actor string, limit int) ([]models.Post, error) {
+	c.logger.Debug("Fetching recent posts of: %s", actor)
+
+	params := url.Values{}
+	params.Set("actor", actor)
+	params.Set("filter", "posts_no_replies")
+	params.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequest("GET", apiBase+"/app.bsky.feed.getAuthorFeed?"+params.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Failed to create author feed request: %v", err)
+		return nil, fmt.Errorf("failed to create author feed request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to fetch author feed: %v", err)
+		return nil, fmt.Errorf("failed to fetch author feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Author feed request failed with status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("author feed request failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Feed []struct {
+			Post   models.Post     `json:"post"`
+			Reason json.RawMessage `json:"reason"` // set for reposts
+		} `json:"feed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Failed to decode author feed response: %v", err)
+		return nil, fmt.Errorf("failed to decode author feed response: %w", err)
+	}
+
+	var posts []models.Post
+	for _, item := range result.Feed {
+		if len(item.Reason) == 0 {
+			posts = append(posts, item.Post)
+		}
+	}
+	return posts, nil
+}
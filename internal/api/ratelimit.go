@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is what the server reported about its rate limit on a response
+type RateLimit struct {
+	Method     string        // XRPC method of the request, e.g. com.atproto.repo.createRecord
+	Limit      int           // points allowed in the current window, 0 if not reported
+	Remaining  int           // points left in the current window
+	Reset      time.Time     // when the window resets, zero if not reported
+	Exceeded   bool          // the request was refused with 429
+	RetryAfter time.Duration // how long the server asked to wait after a 429, zero if it did not say
+}
+
+// IsWrite reports whether the limit is on repository writes, the budget
+// follows and unfollows are spent from
+func (r RateLimit) IsWrite() bool {
+	return strings.HasPrefix(r.Method, "com.atproto.repo.")
+}
+
+// OnRateLimit registers a function called with the rate limit the server
+// reports on each response that carries one, and on every 429. It replaces
+// any earlier function and must be set before the client is used.
+func (c *Client) OnRateLimit(observe func(RateLimit)) {
+	c.transport.observe = observe
+}
+
+// transport inspects every response before the client's methods see it:
+// rate limit headers are reported to the observer, and a refused access token
+// is turned into ErrExpiredToken
+type transport struct {
+	base    http.RoundTripper
+	observe func(RateLimit)
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if t.observe != nil {
+		t.report(req, resp)
+	}
+	if req.Header.Get("Authorization") != "" && (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized) {
+		return checkExpired(resp)
+	}
+	return resp, nil
+}
+
+// report passes the rate limit headers of a response to the observer
+func (t *transport) report(req *http.Request, resp *http.Response) {
+	limit := RateLimit{
+		Method:   req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:],
+		Exceeded: resp.StatusCode == http.StatusTooManyRequests,
+	}
+	limit.Limit, _ = strconv.Atoi(resp.Header.Get("ratelimit-limit"))
+	limit.Remaining, _ = strconv.Atoi(resp.Header.Get("ratelimit-remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("ratelimit-reset"), 10, 64); err == nil {
+		limit.Reset = time.Unix(reset, 0)
+	}
+	if limit.Exceeded {
+		limit.RetryAfter = rateLimitError(resp).RetryAfter
+	}
+	if limit.Limit > 0 || limit.Exceeded {
+		t.observe(limit)
+	}
+}
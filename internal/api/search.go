@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// SearchPosts retrieves one page of the most recent posts matching a query,
+// such as a keyword or "#hashtag", posted since the given time (zero for any
+// time). An empty cursor starts from the beginning; the returned cursor is
+// empty on the last page.
+func (c *Client) SearchPosts(session *models.Session, query string, since time.Time, cursor string) ([]models.Post, string, error) {
+	c.logger.Debug("Searching posts for: %s (cursor: %q)", query, cursor)
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("sort", "latest")
+	params.Set("limit", "100")
+	if !since.IsZero() {
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequest("GET", apiBase+"/app.bsky.feed.searchPosts?"+params.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Failed to create post search request: %v", err)
+		return nil, "", fmt.Errorf("failed to create post search request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to search posts: %v", err)
+		return nil, "", fmt.Errorf("failed to search posts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Post search failed with status: %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("post search failed with status: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Posts  []models.Post `json:"posts"`
+		Cursor string        `json:"cursor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.logger.Error("Failed to decode post search response: %v", err)
+		return nil, "", fmt.Errorf("failed to decode post search response: %w", err)
+	}
+
+	return result.Posts, result.Cursor, nil
+}
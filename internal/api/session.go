@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// RefreshSession exchanges the session's refresh token for new tokens
+func (c *Client) RefreshSession(session *models.Session) (*models.Session, error) {
+	c.logger.Info("Refreshing session for: %s", session.Handle)
+
+	req, err := http.NewRequest("POST", apiBase+"/com.atproto.server.refreshSession", nil)
+	if err != nil {
+		c.logger.Error("Failed to create refresh request: %v", err)
+		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+session.RefreshToken())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to execute refresh request: %v", err)
+		return nil, fmt.Errorf("failed to execute refresh request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Session refresh failed with status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("session refresh failed with status code: %d", resp.StatusCode)
+	}
+
+	var refreshed models.Session
+	if err := json.NewDecoder(resp.Body).Decode(&refreshed); err != nil {
+		c.logger.Error("Failed to decode refresh response: %v", err)
+		return nil, fmt.Errorf("failed to decode refresh response: %w", err)
+	}
+
+	// The session's age is counted from the original login
+	refreshed.CreatedAt = session.CreatedAt
+	refreshed.ExpiresAt = TokenExpiry(refreshed.AccessJwt)
+	c.logger.Info("Refreshed session for: %s", refreshed.Handle)
+	return &refreshed, nil
+}
+
+// GetSession checks that the session's access token is still accepted
+func (c *Client) GetSession(session *models.Session) error {
+	c.logger.Debug("Checking session for: %s", session.Handle)
+
+	req, err := http.NewRequest("GET", apiBase+"/com.atproto.server.getSession", nil)
+	if err != nil {
+		c.logger.Error("Failed to create session request: %v", err)
+		return fmt.Errorf("failed to create session request: %w", err)
+	}
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to execute session request: %v", err)
+		return fmt.Errorf("failed to execute session request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Session check failed with status code: %d", resp.StatusCode)
+		if err := accountStatus(resp, session.Did); err != nil {
+			return err
+		}
+		return fmt.Errorf("session check failed with status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// TokenExpiry returns the exp claim of a JWT, or the zero time if the token
+// cannot be parsed
+func TokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+// ErrExpiredToken is returned when the server refuses a request because its
+// token expired; the session must be refreshed or logged in again
+var ErrExpiredToken = errors.New("token expired")
+
+// maxErrorBody bounds how much of an error response is read to find out
+// whether the token expired
+const maxErrorBody = 4096
+
+// checkExpired returns ErrExpiredToken for a response refusing an expired
+// token, and otherwise the response with its body intact
+func checkExpired(resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var xrpcErr xrpcError
+	if json.Unmarshal(body, &xrpcErr) == nil && xrpcErr.Error == "ExpiredToken" {
+		return nil, ErrExpiredToken
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// accountStatusCodes are the XRPC errors for an account that can no longer
+// act: taken down or suspended by moderation, or deactivated by its owner
+var accountStatusCodes = map[string]bool{
+	"AccountTakedown":    true,
+	"AccountSuspended":   true,
+	"AccountDeactivated": true,
+}
+
+// AccountStatusError is returned when the server refuses a request because an
+// account is taken down, suspended or deactivated
+type AccountStatusError struct {
+	Actor   string // handle or DID of the account the status is about
+	Code    string // XRPC error, one of accountStatusCodes
+	Message string
+}
+
+func (e *AccountStatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("account %s: %s", e.Actor, e.Code)
+	}
+	return fmt.Sprintf("account %s: %s (%s)", e.Actor, e.Message, e.Code)
+}
+
+// accountStatus returns an *AccountStatusError when an error response says
+// the account of actor can no longer act, and nil otherwise
+func accountStatus(resp *http.Response, actor string) error {
+	var body xrpcError
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err != nil {
+		return nil
+	}
+	if !accountStatusCodes[body.Error] {
+		return nil
+	}
+	return &AccountStatusError{Actor: actor, Code: body.Error, Message: body.Message}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// followCollection is the record collection follows are stored in
+const followCollection = "app.bsky.graph.follow"
+
+// RateLimitError is returned when the server rejects a request for exceeding
+// a rate limit. RetryAfter is how long the server asked us to wait, zero if
+// it did not say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+	}
+	return "rate limited"
+}
+
+// rateLimitError builds a RateLimitError from a 429 response, preferring the
+// ratelimit-reset header (unix seconds) over Retry-After (seconds)
+func rateLimitError(resp *http.Response) *RateLimitError {
+	if reset, err := strconv.ParseInt(resp.Header.Get("ratelimit-reset"), 10, 64); err == nil {
+		return &RateLimitError{RetryAfter: time.Until(time.Unix(reset, 0))}
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return &RateLimitError{RetryAfter: time.Duration(seconds) * time.Second}
+	}
+	return &RateLimitError{}
+}
+
+// GetFollowURI returns the URI of my follow record for an actor, or an
+// empty string if I do not follow them
+func (c *Client) GetFollowURI(session *models.Session, actor string) (string, error) {
+	profile, err := c.GetProfile(session, actor)
+	if err != nil {
+		return "", err
+	}
+	return profile.Viewer.Following, nil
+}
+
+// UnfollowUser deletes a follow record given its at:// URI
+func (c *Client) UnfollowUser(session *models.Session, followURI string) error {
+	rkey := followURI[strings.LastIndex(followURI, "/")+1:]
+	if !strings.Contains(followURI, followCollection) || rkey == "" {
+		return fmt.Errorf("not a follow record URI: %s", followURI)
+	}
+
+	c.logger.Info("Deleting follow record: %s", followURI)
+
+	payload := map[string]string{
+		"repo":       session.Did,
+		"collection": followCollection,
+		"rkey":       rkey,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		c.logger.Error("Failed to marshal unfollow payload: %v", err)
+		return fmt.Errorf("failed to marshal unfollow payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", apiBase+"/com.atproto.repo.deleteRecord", bytes.NewBuffer(jsonData))
+	if err != nil {
+		c.logger.Error("Failed to create unfollow request: %v", err)
+		return fmt.Errorf("failed to create unfollow request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", session.Bearer())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to execute unfollow request: %v", err)
+		return fmt.Errorf("failed to execute unfollow request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err := rateLimitError(resp)
+		c.logger.Error("Unfollow rate limited: %v", err)
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("Unfollow failed with status: %d", resp.StatusCode)
+		return fmt.Errorf("unfollow failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package backup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Version is the state document format written by Write
+const Version = 1
+
+// Conflict modes decide what happens when an imported record already exists
+const (
+	ConflictSkip      = "skip"      // keep the existing record
+	ConflictOverwrite = "overwrite" // replace it with the imported one
+	ConflictMerge     = "merge"     // combine both, keeping the strongest state
+)
+
+// QueueEntry is a queued follow as it stood at export time. The queue is
+// rebuilt from users on startup, so entries carry the retry state that is
+// not otherwise visible in the users list.
+type QueueEntry struct {
+	DID      string    `json:"did"`
+	Handle   string    `json:"handle"`
+	Priority int       `json:"priority"` // effective priority, including source offsets
+	Attempts int       `json:"attempts"`
+	NextTry  time.Time `json:"nextTry"`
+}
+
+// State is the full application state as a single document
+type State struct {
+	Version    int                       `json:"version"`
+	ExportedAt time.Time                 `json:"exportedAt"`
+	AccountDID string                    `json:"accountDid,omitempty"`
+	Users      []models.TargetUser       `json:"users"`
+	Queue      []QueueEntry              `json:"queue"`
+	Blocklist  []models.BlocklistEntry   `json:"blocklist"`
+	Protected  []models.ProtectedAccount `json:"protected"`
+}
+
+// ImportResult counts what an import did with one kind of record
+type ImportResult struct {
+	Added   int
+	Updated int
+	Skipped int
+}
+
+// ImportReport is the outcome of an import per kind of record
+type ImportReport struct {
+	Users     ImportResult
+	Blocklist ImportResult
+	Protected ImportResult
+}
+
+// ValidConflictMode reports whether mode is a known conflict mode
+func ValidConflictMode(mode string) bool {
+	switch mode {
+	case ConflictSkip, ConflictOverwrite, ConflictMerge:
+		return true
+	}
+	return false
+}
+
+// Write encodes a state document as indented JSON
+func Write(w io.Writer, state *State) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(state); err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	return nil
+}
+
+// WriteQueue writes queue entries in order as "json" or "csv"
+func WriteQueue(w io.Writer, entries []QueueEntry, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			return fmt.Errorf("failed to encode queue: %w", err)
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"position", "handle", "did", "priority", "attempts", "next_try"}); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		for i, entry := range entries {
+			record := []string{
+				strconv.Itoa(i + 1),
+				entry.Handle,
+				entry.DID,
+				strconv.Itoa(entry.Priority),
+				strconv.Itoa(entry.Attempts),
+				entry.NextTry.Format(time.RFC3339),
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write csv record: %w", err)
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// WriteEvents writes history events in order as "json" or "csv", for
+// keeping or auditing outside the database
+func WriteEvents(w io.Writer, events []models.FollowEvent, format string) error {
+	switch format {
+	case "json":
+		if events == nil {
+			events = []models.FollowEvent{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(events); err != nil {
+			return fmt.Errorf("failed to encode events: %w", err)
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "created_at", "actor", "action", "did", "handle", "strategy", "result", "error"}); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		for _, event := range events {
+			record := []string{
+				strconv.FormatInt(event.ID, 10),
+				event.CreatedAt.Format(time.RFC3339),
+				event.Actor,
+				event.Action,
+				event.DID,
+				event.Handle,
+				event.Strategy,
+				event.Result,
+				event.Error,
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write csv record: %w", err)
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// Read decodes a state document, rejecting formats newer than this build
+func Read(r io.Reader) (*State, error) {
+	var state State
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+	if state.Version == 0 || state.Version > Version {
+		return nil, fmt.Errorf("unsupported state version %d", state.Version)
+	}
+	return &state, nil
+}
@@ -8,11 +8,35 @@ import (
 	"time"
 
 	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+	"bsky_follower/internal/schedule"
+	"bsky_follower/internal/score"
 
 	"github.com/joho/godotenv"
 )
 
-const defaultTimeout = 10 * time.Second
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultDBPath           = "users.db"
+	defaultMetricsInterval  = 5 * time.Minute
+	defaultRetryBase        = 5 * time.Minute
+	defaultRetryCap         = 2 * time.Hour
+	defaultDecayEvery       = 7 * 24 * time.Hour
+	defaultFollowLimit      = 50
+	defaultReadLimit        = 30000
+	defaultEnrichWorkers    = 8
+	defaultUnfollowLimit    = 20
+	defaultPacing           = "uniform"
+	defaultFollowGap        = 24 * time.Hour
+	defaultFollowJitter     = 0.25
+	defaultUnfollowGap      = 2 * time.Minute
+	defaultPauseChance      = 0.05
+	defaultPauseMin         = 30 * time.Minute
+	defaultPauseMax         = 3 * time.Hour
+	defaultErrorWindow      = 20
+	defaultFollowerDrop     = 10
+	defaultRefollowCooldown = 90 * 24 * time.Hour
+)
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*models.Config, error) {
@@ -21,7 +45,7 @@ func LoadConfig() (*models.Config, error) {
 
 	identifier := os.Getenv("BSKY_IDENTIFIER")
 	password := os.Getenv("BSKY_PASSWORD")
-	
+
 	if identifier == "" || password == "" {
 		return nil, fmt.Errorf("BSKY_IDENTIFIER and BSKY_PASSWORD environment variables must be set")
 	}
@@ -39,11 +63,342 @@ func LoadConfig() (*models.Config, error) {
 			timeout = time.Duration(timeoutSec) * time.Second
 		}
 	}
-	
+
+	metricsInterval := getSeconds("BSKY_METRICS_INTERVAL", defaultMetricsInterval)
+	pruneAge := getDays("BSKY_PRUNE_DAYS", 0)
+	retryBase := getSeconds("BSKY_RETRY_BASE", defaultRetryBase)
+	retryCap := getSeconds("BSKY_RETRY_CAP", defaultRetryCap)
+	maxQueueSize := getCount("BSKY_MAX_QUEUE_SIZE", 0)
+	decayAfter := getDays("BSKY_DECAY_DAYS", 0)
+	decayEvery := getDays("BSKY_DECAY_EVERY_DAYS", defaultDecayEvery)
+	expireAge := getDays("BSKY_EXPIRE_DAYS", 0)
+	unfollowAfter := getDays("BSKY_AUTO_UNFOLLOW_DAYS", 0)
+	unfollowsPerHour := getCount("BSKY_UNFOLLOWS_PER_HOUR", defaultUnfollowLimit)
+
+	sourcePriority, err := parseSourcePriority(os.Getenv("BSKY_SOURCE_PRIORITY"))
+	if err != nil {
+		return nil, err
+	}
+
+	queueWeights, err := parseQueueWeights(os.Getenv("BSKY_QUEUE_WEIGHTS"))
+	if err != nil {
+		return nil, err
+	}
+
+	sourceBudgets, err := parseSourceBudgets(os.Getenv("BSKY_SOURCE_BUDGETS"))
+	if err != nil {
+		return nil, err
+	}
+
+	scoreWeights, err := parseScoreWeights(os.Getenv("BSKY_SCORE_WEIGHTS"))
+	if err != nil {
+		return nil, err
+	}
+
+	pacing, err := parsePacing(os.Getenv("BSKY_PACING"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := parseKeys(os.Getenv("BSKY_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	activeHours := os.Getenv("BSKY_ACTIVE_HOURS")
+	activeDays := os.Getenv("BSKY_ACTIVE_DAYS")
+	if _, err := schedule.ParseWindow(activeHours, activeDays); err != nil {
+		return nil, fmt.Errorf("invalid BSKY_ACTIVE_HOURS or BSKY_ACTIVE_DAYS: %w", err)
+	}
+
+	maxErrorPercent := getCount("BSKY_MAX_ERROR_PERCENT", 0)
+	if maxErrorPercent > 100 {
+		return nil, fmt.Errorf("BSKY_MAX_ERROR_PERCENT must be between 0 and 100, got %d", maxErrorPercent)
+	}
+
+	followerDrop := getCount("BSKY_FOLLOWER_DROP_PERCENT", defaultFollowerDrop)
+	if followerDrop > 100 {
+		return nil, fmt.Errorf("BSKY_FOLLOWER_DROP_PERCENT must be between 0 and 100, got %d", followerDrop)
+	}
+
+	if (os.Getenv("BSKY_TELEGRAM_TOKEN") == "") != (os.Getenv("BSKY_TELEGRAM_CHAT_ID") == "") {
+		return nil, fmt.Errorf("BSKY_TELEGRAM_TOKEN and BSKY_TELEGRAM_CHAT_ID must be set together")
+	}
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = defaultDBPath
+	}
+
 	return &models.Config{
-		Identifier:      identifier,
-		Password:        password,
-		Timeout:         timeout,
-		FallbackHandles: fallbackHandles,
+		Identifier:       identifier,
+		Password:         password,
+		Timeout:          timeout,
+		FallbackHandles:  fallbackHandles,
+		DBPath:           dbPath,
+		DatabaseURL:      os.Getenv("DATABASE_URL"),
+		AccountDID:       os.Getenv("BSKY_ACCOUNT_DID"),
+		AccountNamespace: os.Getenv("BSKY_ACCOUNT_NAMESPACE") == "true",
+		PprofAddr:        os.Getenv("BSKY_PPROF_ADDR"),
+		ControlAddr:      os.Getenv("BSKY_CONTROL_ADDR"),
+		MetricsInterval:  metricsInterval,
+		DigestTemplate:   os.Getenv("BSKY_DIGEST_TEMPLATE"),
+		FollowBack:       os.Getenv("BSKY_FOLLOW_BACK") == "true",
+		RandomSelection:  os.Getenv("BSKY_RANDOM_SELECTION") == "true",
+		SourcePriority:   sourcePriority,
+		ScoreWeights:     scoreWeights,
+		PruneAge:         pruneAge,
+		PruneArchive:     os.Getenv("BSKY_PRUNE_ARCHIVE") == "true",
+		Ephemeral:        os.Getenv("BSKY_EPHEMERAL") == "true",
+		RetryBase:        retryBase,
+		RetryCap:         retryCap,
+		MaxQueueSize:     maxQueueSize,
+		QueueWeights:     queueWeights,
+		SourceBudgets:    sourceBudgets,
+		DecayAfter:       decayAfter,
+		DecayEvery:       decayEvery,
+		ExpireAge:        expireAge,
+		DryRun:           os.Getenv("BSKY_DRY_RUN") == "true",
+		Keywords:         getList("BSKY_KEYWORDS"),
+		FollowersOf:      getList("BSKY_FOLLOWERS_OF"),
+		SecondDegree:     os.Getenv("BSKY_SECOND_DEGREE") == "true",
+		Strategies:       getList("BSKY_STRATEGIES"),
+		MinFollowers:     getCount("BSKY_DISCOVER_MIN_FOLLOWERS", 0),
+		MaxFollowers:     getCount("BSKY_DISCOVER_MAX_FOLLOWERS", 0),
+		MinPosts:         getCount("BSKY_DISCOVER_MIN_POSTS", 0),
+		UnfollowAfter:    unfollowAfter,
+		FollowsPerHour:   getCount("BSKY_FOLLOWS_PER_HOUR", defaultFollowLimit),
+		ReadsPerHour:     getCount("BSKY_READS_PER_HOUR", defaultReadLimit),
+		EnrichWorkers:    max(getCount("BSKY_ENRICH_WORKERS", defaultEnrichWorkers), 1),
+		UnfollowsPerHour: unfollowsPerHour,
+		UnfollowsPerDay:  getCount("BSKY_UNFOLLOWS_PER_DAY", 0),
+		UnfollowGap:      getSeconds("BSKY_UNFOLLOW_GAP", defaultUnfollowGap),
+		FollowsPerDay:    getCount("BSKY_FOLLOWS_PER_DAY", 0),
+		FollowsPerWeek:   getCount("BSKY_FOLLOWS_PER_WEEK", 0),
+		Pacing:           pacing,
+		FollowGap:        getSeconds("BSKY_FOLLOW_GAP", defaultFollowGap),
+		FollowJitter:     getFloat("BSKY_FOLLOW_JITTER", defaultFollowJitter),
+		PauseChance:      getFloat("BSKY_LONG_PAUSE_CHANCE", defaultPauseChance),
+		PauseMin:         getSeconds("BSKY_LONG_PAUSE_MIN", defaultPauseMin),
+		PauseMax:         getSeconds("BSKY_LONG_PAUSE_MAX", defaultPauseMax),
+		ActiveHours:      activeHours,
+		ActiveDays:       activeDays,
+		MaxFollowRatio:   getFloat("BSKY_MAX_FOLLOW_RATIO", 0),
+		RatioUnfollow:    os.Getenv("BSKY_RATIO_UNFOLLOW") == "true",
+		UnfollowMutuals:  os.Getenv("BSKY_UNFOLLOW_MUTUALS") == "true",
+		RefollowCooldown: getDays("BSKY_REFOLLOW_COOLDOWN_DAYS", defaultRefollowCooldown),
+		MaxErrorPercent:  maxErrorPercent,
+		ErrorWindow:      max(getCount("BSKY_ERROR_WINDOW", defaultErrorWindow), 1),
+		FollowerDrop:     followerDrop,
+		DiscordWebhook:   os.Getenv("BSKY_DISCORD_WEBHOOK"),
+		SlackWebhook:     os.Getenv("BSKY_SLACK_WEBHOOK"),
+		TelegramToken:    os.Getenv("BSKY_TELEGRAM_TOKEN"),
+		TelegramChat:     os.Getenv("BSKY_TELEGRAM_CHAT_ID"),
+		ReportDir:        os.Getenv("BSKY_REPORT_DIR"),
+		Keys:             keys,
+		Targeting: models.TargetingRules{
+			MinFollowers:  getCount("BSKY_TARGET_MIN_FOLLOWERS", 0),
+			MaxFollowers:  getCount("BSKY_TARGET_MAX_FOLLOWERS", 0),
+			MaxRatio:      getFloat("BSKY_TARGET_MAX_RATIO", 0),
+			MinAge:        getDays("BSKY_TARGET_MIN_AGE_DAYS", 0),
+			RequireAvatar: os.Getenv("BSKY_TARGET_REQUIRE_AVATAR") == "true",
+			PostedWithin:  getDays("BSKY_TARGET_POSTED_DAYS", 0),
+			BioInclude:    getList("BSKY_TARGET_BIO_INCLUDE"),
+			BioExclude:    getList("BSKY_TARGET_BIO_EXCLUDE"),
+			Langs:         getList("BSKY_TARGET_LANGS"),
+			ExcludeLabels: getList("BSKY_TARGET_EXCLUDE_LABELS"),
+		},
 	}, nil
-} 
\ No newline at end of file
+}
+
+// parseSourcePriority parses "source=offset" pairs separated by commas,
+// e.g. "likes=2,suggestions=-1"
+func parseSourcePriority(value string) (map[string]int, error) {
+	offsets := make(map[string]int)
+	if value == "" {
+		return offsets, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, offset, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid BSKY_SOURCE_PRIORITY entry %q, expected source=offset", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(offset))
+		if err != nil {
+			return nil, fmt.Errorf("invalid BSKY_SOURCE_PRIORITY offset for %s: %w", name, err)
+		}
+		offsets[strings.TrimSpace(name)] = n
+	}
+
+	return offsets, nil
+}
+
+// parseScoreWeights parses "signal=weight" pairs separated by commas, e.g.
+// "followers=2,recency=0.5", checking each signal name
+func parseScoreWeights(value string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	if value == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, weight, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid BSKY_SCORE_WEIGHTS entry %q, expected signal=weight", pair)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BSKY_SCORE_WEIGHTS weight for %s: %w", name, err)
+		}
+		weights[strings.TrimSpace(name)] = w
+	}
+
+	if _, err := score.FromMap(weights); err != nil {
+		return nil, fmt.Errorf("invalid BSKY_SCORE_WEIGHTS: %w (known signals: %s)", err, strings.Join(score.Names, ", "))
+	}
+	return weights, nil
+}
+
+// parseQueueWeights parses "kind=weight" pairs separated by commas, e.g.
+// "list=3,search=2,default=1". Weights must be positive.
+func parseQueueWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int)
+	if value == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, weight, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid BSKY_QUEUE_WEIGHTS entry %q, expected kind=weight", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid BSKY_QUEUE_WEIGHTS weight for %s, expected a positive number", name)
+		}
+		weights[strings.TrimSpace(name)] = n
+	}
+
+	return weights, nil
+}
+
+// parseSourceBudgets parses "kind=percent" pairs separated by commas, e.g.
+// "list=30,search=10,default=60". Each share must be between 1 and 100 and
+// together they may not exceed 100.
+func parseSourceBudgets(value string) (map[string]int, error) {
+	budgets := make(map[string]int)
+	if value == "" {
+		return budgets, nil
+	}
+
+	total := 0
+	for _, pair := range strings.Split(value, ",") {
+		name, share, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid BSKY_SOURCE_BUDGETS entry %q, expected kind=percent", pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(share), "%"))
+		if err != nil || n <= 0 || n > 100 {
+			return nil, fmt.Errorf("invalid BSKY_SOURCE_BUDGETS share for %s, expected a percentage from 1 to 100", name)
+		}
+		budgets[strings.TrimSpace(name)] = n
+		total += n
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("invalid BSKY_SOURCE_BUDGETS: the shares add up to %d%%, more than 100%%", total)
+	}
+
+	return budgets, nil
+}
+
+// parseKeys parses "action=keys" pairs separated by commas, where the keys
+// are separated by spaces, e.g. "quit=ctrl+q,up=up i". Action names are
+// checked by the UI, which owns them.
+func parseKeys(value string) (map[string][]string, error) {
+	keys := make(map[string][]string)
+	if value == "" {
+		return keys, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, list, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || strings.TrimSpace(name) == "" || len(strings.Fields(list)) == 0 {
+			return nil, fmt.Errorf("invalid BSKY_KEYS entry %q, expected action=keys", pair)
+		}
+		keys[strings.ToLower(strings.TrimSpace(name))] = strings.Fields(list)
+	}
+
+	return keys, nil
+}
+
+// parsePacing checks the distribution of the gaps between follows, defaulting
+// to uniform
+func parsePacing(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return defaultPacing, nil
+	}
+	for _, name := range queue.PaceDistributions {
+		if value == name {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("invalid BSKY_PACING %q (known distributions: %s)", value, strings.Join(queue.PaceDistributions, ", "))
+}
+
+// getSeconds parses a non-negative number of seconds from an environment
+// variable, falling back to def when unset or invalid
+func getSeconds(name string, def time.Duration) time.Duration {
+	if value := os.Getenv(name); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return def
+}
+
+// getDays parses a non-negative number of days from an environment variable,
+// falling back to def when unset or invalid
+func getDays(name string, def time.Duration) time.Duration {
+	if value := os.Getenv(name); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return def
+}
+
+// getCount parses a non-negative integer from an environment variable,
+// falling back to def when unset or invalid
+func getCount(name string, def int) int {
+	if value := os.Getenv(name); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
+
+// getFloat parses a non-negative number from an environment variable,
+// falling back to def when unset or invalid
+func getFloat(name string, def float64) float64 {
+	if value := os.Getenv(name); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f >= 0 {
+			return f
+		}
+	}
+	return def
+}
+
+// getList parses a comma-separated list from an environment variable,
+// trimming spaces and dropping empty entries
+func getList(name string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
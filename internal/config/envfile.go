@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvFile is the file LoadConfig reads settings from, and SaveSetting writes
+// them to
+const EnvFile = ".env"
+
+// SaveSetting sets a variable in an env file and in the environment. An
+// existing line for the variable is replaced in place, keeping the rest of
+// the file and its comments as they are; otherwise the line is appended. The
+// file is created if it doesn't exist.
+func SaveSetting(path, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	line := key + "=" + quoteEnv(value)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	found := false
+	for i, existing := range lines {
+		name, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(existing), "export "), "=")
+		if ok && strings.TrimSpace(name) == key {
+			lines[i] = line
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, line)
+	}
+
+	// Write next to the file and rename, so a crash never leaves it half written
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		os.Chmod(tmp.Name(), info.Mode())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return os.Setenv(key, value)
+}
+
+// quoteEnv quotes a value that wouldn't read back as itself unquoted
+func quoteEnv(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t#\"'\\$") {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+	}
+	return value
+}
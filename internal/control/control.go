@@ -0,0 +1,301 @@
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/events"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+	"bsky_follower/internal/ratelimit"
+	"bsky_follower/internal/review"
+	"bsky_follower/internal/service"
+)
+
+// Logger interface for logging
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+}
+
+// Service is the part of the service the control API drives
+type Service interface {
+	PauseQueue() error
+	ResumeQueue() error
+	QueuePaused() (bool, error)
+	QueueLen() int
+	QueueStats() queue.Stats
+	Reprioritize(filter db.UserFilter, priority int) (int, error)
+	ApplyReview(decisions []review.Decision) (service.ReviewResult, error)
+	Health() models.Health
+	FollowRate() ratelimit.Status
+	SubscribeEvents() (<-chan events.Event, func())
+}
+
+// keepAliveInterval is how often an idle event stream sends a comment, so
+// proxies don't close it
+const keepAliveInterval = 30 * time.Second
+
+// QueueStatus is the response of every queue endpoint
+type QueueStatus struct {
+	Paused bool `json:"paused"`
+	Length int  `json:"length"`
+}
+
+// Handler serves the daemon's control API:
+//
+//	GET  /queue         queue status
+//	GET  /queue/stats   depth, priority bands, throughput and dead letters
+//	POST /queue/pause   stop taking items from the follow queue
+//	POST /queue/resume  start taking items again
+//	POST /queue/reprioritize?priority=3&source=list&maxFollowers=499
+//	                    set the priority of matching pending users
+//	POST /review/decisions?format=csv
+//	                    apply a reviewed candidate file sent as the body,
+//	                    CSV or JSON (the default)
+//	GET  /rate-limit    current follow rate, server budget and any wait
+//	                    on a refused request
+//	GET  /healthz       liveness: 200 unless the database is unreachable or
+//	                    the follow queue processor is stuck
+//	GET  /readyz        readiness: 200 once the database, session and follow
+//	                    queue processor checks all pass
+//	GET  /events?types=action,rate-limit
+//	                    Server-Sent Events stream of service events, all
+//	                    types unless some are listed
+func Handler(svc Service, logger Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		streamEvents(w, r, svc, logger)
+	})
+	mux.HandleFunc("/healthz", healthProbe(svc, func(h models.Health) bool { return h.Live }))
+	mux.HandleFunc("/readyz", healthProbe(svc, func(h models.Health) bool { return h.Ready }))
+	mux.HandleFunc("/queue", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeQueueStatus(w, svc, logger)
+	})
+	mux.HandleFunc("/queue/stats", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc.QueueStats())
+	})
+	mux.HandleFunc("/rate-limit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(svc.FollowRate())
+	})
+	mux.HandleFunc("/queue/pause", queueAction(svc.PauseQueue, svc, logger))
+	mux.HandleFunc("/queue/resume", queueAction(svc.ResumeQueue, svc, logger))
+	mux.HandleFunc("/queue/reprioritize", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		filter, priority, err := parseReprioritize(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		changed, err := svc.Reprioritize(filter, priority)
+		if err != nil {
+			logger.Error("Control API %s failed: %v", r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"changed": changed})
+	})
+	mux.HandleFunc("/review/decisions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = review.FormatJSON
+		}
+		decisions, err := review.ReadDecisions(r.Body, format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := svc.ApplyReview(decisions)
+		if err != nil {
+			logger.Error("Control API %s failed: %v", r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+	return mux
+}
+
+// parseReprioritize reads the filter and new priority of a reprioritize
+// request. At least one filter parameter is required so a bare request
+// cannot rewrite every pending user.
+func parseReprioritize(r *http.Request) (db.UserFilter, int, error) {
+	params := r.URL.Query()
+	number := func(name string) (int, error) {
+		value := params.Get(name)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return n, nil
+	}
+
+	var filter db.UserFilter
+	var err error
+	filter.Source = params.Get("source")
+	filter.BioContains = params.Get("bio")
+	if filter.MinFollowers, err = number("minFollowers"); err != nil {
+		return filter, 0, err
+	}
+	if filter.MaxFollowers, err = number("maxFollowers"); err != nil {
+		return filter, 0, err
+	}
+	if filter == (db.UserFilter{}) {
+		return filter, 0, fmt.Errorf("at least one of source, bio, minFollowers or maxFollowers is required")
+	}
+	if params.Get("priority") == "" {
+		return filter, 0, fmt.Errorf("priority is required")
+	}
+	priority, err := number("priority")
+	return filter, priority, err
+}
+
+// Serve starts the control API on addr in the background
+func Serve(addr string, svc Service, logger Logger) {
+	go func() {
+		logger.Info("Serving control API on http://%s/", addr)
+		if err := http.ListenAndServe(addr, Handler(svc, logger)); err != nil {
+			logger.Error("Control API stopped: %v", err)
+		}
+	}()
+}
+
+// queueAction handles a POST that changes the queue state and answers with
+// the resulting status
+func queueAction(action func() error, svc Service, logger Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := action(); err != nil {
+			logger.Error("Control API %s failed: %v", r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeQueueStatus(w, svc, logger)
+	}
+}
+
+// healthProbe answers with the health report, with status 503 when the
+// probe's condition doesn't hold
+func healthProbe(svc Service, healthy func(models.Health) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		health := svc.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy(health) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(health)
+	}
+}
+
+// streamEvents sends service events as Server-Sent Events until the client
+// goes away. Each event's type is the SSE event name and its JSON is the
+// data.
+func streamEvents(w http.ResponseWriter, r *http.Request, svc Service, logger Logger) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	types := make(map[string]bool)
+	for _, name := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			types[name] = true
+		}
+	}
+
+	stream, unsubscribe := svc.SubscribeEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			if len(types) > 0 && !types[event.Type] {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.Error("Failed to encode %s event: %v", event.Type, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeQueueStatus(w http.ResponseWriter, svc Service, logger Logger) {
+	paused, err := svc.QueuePaused()
+	if err != nil {
+		logger.Error("Failed to read queue state: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(QueueStatus{Paused: paused, Length: svc.QueueLen()})
+}
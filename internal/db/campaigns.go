@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// CreateCampaign stores a new follow campaign, filling in its ID and
+// creation time
+func (s *SQLStore) CreateCampaign(ctx context.Context, campaign *models.Campaign) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	campaign.CreatedAt = time.Now()
+	if err := s.queryRow(ctx, `
+		INSERT INTO follow_campaigns (name, strategies, target, starts_at, ends_at, follows_per_day, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id
+	`,
+		campaign.Name,
+		strings.Join(campaign.Strategies, "\n"),
+		campaign.Target,
+		campaign.StartsAt,
+		nullTime(campaign.EndsAt),
+		campaign.FollowsPerDay,
+		campaign.CreatedAt,
+	).Scan(&campaign.ID); err != nil {
+		s.logger.Error("Failed to create follow campaign %s: %v", campaign.Name, err)
+		return fmt.Errorf("failed to create follow campaign: %w", err)
+	}
+
+	return nil
+}
+
+// LoadCampaigns returns all follow campaigns, oldest first
+func (s *SQLStore) LoadCampaigns(ctx context.Context) ([]models.Campaign, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `
+		SELECT id, name, strategies, target, starts_at, ends_at, follows_per_day, created_at, finished_at, outcome
+		FROM follow_campaigns ORDER BY id
+	`)
+	if err != nil {
+		s.logger.Error("Failed to query follow campaigns: %v", err)
+		return nil, fmt.Errorf("failed to query follow campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []models.Campaign
+	for rows.Next() {
+		var campaign models.Campaign
+		var strategies string
+		var endsAt, finishedAt sql.NullTime
+		if err := rows.Scan(
+			&campaign.ID,
+			&campaign.Name,
+			&strategies,
+			&campaign.Target,
+			&campaign.StartsAt,
+			&endsAt,
+			&campaign.FollowsPerDay,
+			&campaign.CreatedAt,
+			&finishedAt,
+			&campaign.Outcome,
+		); err != nil {
+			s.logger.Error("Failed to scan follow campaign row: %v", err)
+			return nil, fmt.Errorf("failed to scan follow campaign row: %w", err)
+		}
+		if strategies != "" {
+			campaign.Strategies = strings.Split(strategies, "\n")
+		}
+		if endsAt.Valid {
+			campaign.EndsAt = endsAt.Time
+		}
+		if finishedAt.Valid {
+			campaign.FinishedAt = finishedAt.Time
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// FinishCampaign records how a follow campaign finished
+func (s *SQLStore) FinishCampaign(ctx context.Context, id int64, outcome string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `
+		UPDATE follow_campaigns SET finished_at = ?, outcome = ? WHERE id = ? AND outcome = ''
+	`, time.Now(), outcome, id); err != nil {
+		s.logger.Error("Failed to finish follow campaign %d: %v", id, err)
+		return fmt.Errorf("failed to finish follow campaign: %w", err)
+	}
+
+	return nil
+}
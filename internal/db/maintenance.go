@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// vacuumFreeRatio is the share of free pages above which a SQLite database
+// is vacuumed to return the space and defragment it
+const vacuumFreeRatio = 0.2
+
+// maintenanceTimeout replaces the usual query timeout for Maintain, since a
+// vacuum rewrites the whole database
+const maintenanceTimeout = 10 * time.Minute
+
+// MaintenanceReport describes what Maintain did
+type MaintenanceReport struct {
+	FreePages  int  // SQLite free pages before any vacuum
+	TotalPages int  // SQLite pages before any vacuum
+	Vacuumed   bool // whether the database was vacuumed
+}
+
+// Maintain refreshes query planner statistics and, on SQLite, vacuums the
+// database when more than a fifth of its pages are free. PostgreSQL
+// reclaims space with autovacuum, so it is only analyzed.
+func (s *SQLStore) Maintain(ctx context.Context) (*MaintenanceReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, maintenanceTimeout)
+	defer cancel()
+
+	report := &MaintenanceReport{}
+
+	if s.dialect == dialectPostgres {
+		if _, err := s.db.ExecContext(ctx, `ANALYZE`); err != nil {
+			s.logger.Error("Failed to analyze database: %v", err)
+			return nil, fmt.Errorf("failed to analyze database: %w", err)
+		}
+		return report, nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, `PRAGMA optimize`); err != nil {
+		s.logger.Error("Failed to optimize database: %v", err)
+		return nil, fmt.Errorf("failed to optimize database: %w", err)
+	}
+
+	if err := s.db.QueryRowContext(ctx, `PRAGMA freelist_count`).Scan(&report.FreePages); err != nil {
+		return nil, fmt.Errorf("failed to read free page count: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&report.TotalPages); err != nil {
+		return nil, fmt.Errorf("failed to read page count: %w", err)
+	}
+
+	if report.TotalPages == 0 || float64(report.FreePages)/float64(report.TotalPages) <= vacuumFreeRatio {
+		return report, nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, `VACUUM`); err != nil {
+		s.logger.Error("Failed to vacuum database: %v", err)
+		return nil, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	// Truncate the WAL the vacuum just filled instead of leaving it at full size
+	if _, err := s.db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		s.logger.Error("Failed to checkpoint WAL: %v", err)
+	}
+	report.Vacuumed = true
+	return report, nil
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// NewMemoryStore opens an empty store that lives only in memory, for tests
+// and ephemeral runs. It is an in-memory SQLite database, so it behaves
+// exactly like the file-backed store and everything is lost on Close.
+func NewMemoryStore(logger Logger) (*SQLStore, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		logger.Error("Failed to open in-memory database: %v", err)
+		return nil, fmt.Errorf("failed to open in-memory database: %w", err)
+	}
+
+	// Every connection to :memory: is a separate database, so the pool must
+	// hold on to exactly one connection for the life of the store
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	return newSQLStore(db, dialectSQLite, logger)
+}
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// nopLogger discards the store's logging
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Debug(string, ...interface{}) {}
+
+// newTestStore opens an empty in-memory store, closed when the test ends
+func newTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	store, err := NewMemoryStore(nopLogger{})
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// saveUsers stores users, failing the test on an error
+func saveUsers(t *testing.T, store *SQLStore, users ...models.TargetUser) {
+	t.Helper()
+	if err := store.SaveUsers(context.Background(), users); err != nil {
+		t.Fatalf("SaveUsers: %v", err)
+	}
+}
+
+// handles returns the handles of users in order
+func handles(users []models.TargetUser) []string {
+	out := make([]string, len(users))
+	for i, user := range users {
+		out[i] = user.Handle
+	}
+	return out
+}
+
+func TestUsersRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	saved := time.Now().Add(-time.Hour).Truncate(time.Second)
+	saveUsers(t, store, models.TargetUser{
+		DID:       "did:plc:alice",
+		Handle:    "alice.bsky.social",
+		Followers: 120,
+		Priority:  3,
+		SavedOn:   saved,
+		Source:    "list:friends",
+		Langs:     []string{"en", "de"},
+		Rejection: models.RejectReview,
+	})
+
+	users, err := store.LoadUsers(ctx)
+	if err != nil {
+		t.Fatalf("LoadUsers: %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("LoadUsers returned %d users, want 1", len(users))
+	}
+	got := users[0]
+	if got.Handle != "alice.bsky.social" || got.Followers != 120 || got.Priority != 3 || got.Source != "list:friends" {
+		t.Errorf("loaded user = %+v, not as saved", got)
+	}
+	if !got.SavedOn.Equal(saved) {
+		t.Errorf("SavedOn = %v, want %v", got.SavedOn, saved)
+	}
+	if len(got.Langs) != 2 || got.Rejection != models.RejectReview {
+		t.Errorf("Langs = %v, Rejection = %q, not as saved", got.Langs, got.Rejection)
+	}
+
+	if err := store.DeleteUser(ctx, "alice.bsky.social"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if users, _ := store.LoadUsers(ctx); len(users) != 0 {
+		t.Errorf("LoadUsers after DeleteUser = %v, want none", handles(users))
+	}
+}
+
+func TestMemoryStoresAreSeparate(t *testing.T) {
+	a, b := newTestStore(t), newTestStore(t)
+	saveUsers(t, a, models.TargetUser{DID: "did:plc:alice", Handle: "alice.bsky.social"})
+
+	users, err := b.LoadUsers(context.Background())
+	if err != nil {
+		t.Fatalf("LoadUsers: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("second store holds %v, want nothing", handles(users))
+	}
+}
+
+func TestQueryUsersSearchIsLiteral(t *testing.T) {
+	store := newTestStore(t)
+	saveUsers(t, store,
+		models.TargetUser{DID: "did:plc:1", Handle: "a_b.bsky.social"},
+		models.TargetUser{DID: "did:plc:2", Handle: "axb.bsky.social"},
+		models.TargetUser{DID: "did:plc:3", Handle: "100%.bsky.social"},
+		models.TargetUser{DID: "did:plc:4", Handle: `back\slash.bsky.social`},
+	)
+
+	for search, want := range map[string]int{
+		"a_b":  1,
+		"%":    1,
+		`\`:    1,
+		"A_B":  1,
+		"bsky": 4,
+	} {
+		users, err := store.QueryUsers(context.Background(), UserFilter{HandleContains: search}, 0, 0)
+		if err != nil {
+			t.Fatalf("QueryUsers(%q): %v", search, err)
+		}
+		if len(users) != want {
+			t.Errorf("search %q matched %v, want %d users", search, handles(users), want)
+		}
+	}
+}
+
+func TestBlocklistRefusesMutuals(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	saveUsers(t, store,
+		models.TargetUser{DID: "did:plc:mutual", Handle: "mutual.bsky.social", Followed: true, Mutual: true},
+		models.TargetUser{DID: "did:plc:other", Handle: "other.bsky.social"},
+	)
+
+	err := store.AddToBlocklist(ctx, models.BlocklistEntry{Subject: "did:plc:mutual", Reason: "test"})
+	if !errors.Is(err, ErrMutual) {
+		t.Errorf("AddToBlocklist of a mutual = %v, want ErrMutual", err)
+	}
+	if err := store.AddToBlocklist(ctx, models.BlocklistEntry{Subject: "did:plc:other", Reason: "test"}); err != nil {
+		t.Fatalf("AddToBlocklist: %v", err)
+	}
+
+	for _, c := range []struct {
+		did, handle string
+		want        bool
+	}{
+		{"did:plc:other", "", true},
+		{"", "other.bsky.social", false}, // listed by DID only
+		{"did:plc:mutual", "mutual.bsky.social", false},
+	} {
+		blocked, err := store.IsBlocked(ctx, c.did, c.handle)
+		if err != nil {
+			t.Fatalf("IsBlocked: %v", err)
+		}
+		if blocked != c.want {
+			t.Errorf("IsBlocked(%q, %q) = %v, want %v", c.did, c.handle, blocked, c.want)
+		}
+	}
+}
+
+func TestPruneKeepsHistory(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	stale := func(did string) models.TargetUser {
+		return models.TargetUser{DID: did, Handle: did[len("did:plc:"):] + ".bsky.social", SavedOn: old, LastChecked: old}
+	}
+
+	rejected := stale("did:plc:rejected")
+	rejected.Rejection = models.RejectReview
+	followed := stale("did:plc:followed")
+	followed.Followed = true
+	followed.FollowDate = old
+	unfollowed := stale("did:plc:unfollowed")
+	unfollowed.UnfollowedAt = old
+	fresh := stale("did:plc:fresh")
+	fresh.SavedOn = time.Now()
+	saveUsers(t, store, stale("did:plc:stale"), stale("did:plc:protected"), rejected, followed, unfollowed, fresh)
+	if err := store.AddProtected(ctx, models.ProtectedAccount{Subject: "did:plc:protected"}); err != nil {
+		t.Fatalf("AddProtected: %v", err)
+	}
+
+	pruned, err := store.PruneUsers(ctx, time.Now().Add(-24*time.Hour), false)
+	if err != nil {
+		t.Fatalf("PruneUsers: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("PruneUsers pruned %d users, want 1", pruned)
+	}
+	users, err := store.LoadUsers(ctx)
+	if err != nil {
+		t.Fatalf("LoadUsers: %v", err)
+	}
+	for _, user := range users {
+		if user.DID == "did:plc:stale" {
+			t.Error("the stale user was kept")
+		}
+	}
+	if len(users) != 5 {
+		t.Errorf("kept %v, want every user but the stale one", handles(users))
+	}
+}
+
+func TestSettings(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	value, err := store.GetSetting(ctx, "missing")
+	if err != nil || value != "" {
+		t.Errorf("GetSetting of an unset key = %q, %v, want empty", value, err)
+	}
+	for _, want := range []string{"first", "second"} {
+		if err := store.SetSetting(ctx, "key", want); err != nil {
+			t.Fatalf("SetSetting: %v", err)
+		}
+		if got, err := store.GetSetting(ctx, "key"); err != nil || got != want {
+			t.Errorf("GetSetting = %q, %v, want %q", got, err, want)
+		}
+	}
+}
+
+func TestLoadEventTimes(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	for _, event := range []models.FollowEvent{
+		{DID: "did:plc:1", Action: models.ActionFollow, Result: models.ResultSuccess, CreatedAt: now.Add(-2 * time.Hour)},
+		{DID: "did:plc:2", Action: models.ActionFollow, Result: models.ResultSuccess, CreatedAt: now.Add(-30 * time.Minute)},
+		{DID: "did:plc:3", Action: models.ActionFollow, Result: models.ResultSuccess, CreatedAt: now.Add(-10 * time.Minute)},
+		{DID: "did:plc:4", Action: models.ActionUnfollow, Result: models.ResultSuccess, CreatedAt: now.Add(-5 * time.Minute)},
+	} {
+		if err := store.RecordEvent(ctx, event); err != nil {
+			t.Fatalf("RecordEvent: %v", err)
+		}
+	}
+
+	times, err := store.LoadEventTimes(ctx, models.ActionFollow, models.ResultSuccess, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("LoadEventTimes: %v", err)
+	}
+	if len(times) != 2 || !times[0].Equal(now.Add(-30*time.Minute)) || !times[1].Equal(now.Add(-10*time.Minute)) {
+		t.Errorf("LoadEventTimes = %v, want the two follows of the last hour, oldest first", times)
+	}
+}
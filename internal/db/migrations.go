@@ -0,0 +1,482 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+const schemaVersionSetting = "schema_version"
+
+// migration is a single schema change applied inside a transaction
+type migration struct {
+	name string
+	up   func(s *SQLStore, tx *sql.Tx) error
+}
+
+// migrations are applied in order; a database at version N has applied the
+// first N entries. Never reorder or remove entries, only append.
+var migrations = []migration{
+	{name: "key users by did", up: migrateUsersToDIDKey},
+	{name: "follow events", up: createFollowEvents},
+	{name: "followers snapshot", up: createFollowers},
+	{name: "sessions", up: createSessions},
+	{name: "blocklist", up: createBlocklist},
+	{name: "protected accounts", up: createProtected},
+	{name: "daily stats", up: createDailyStats},
+	{name: "unfollow campaigns", up: createUnfollowCampaigns},
+	{name: "account pauses", up: createAccountPauses},
+	{name: "users archive", up: createUsersArchive},
+	{name: "follower observations", up: createFollowerObservations},
+	{name: "follow back tracking", up: addFollowBackColumns},
+	{name: "profile metadata", up: addProfileColumns},
+	{name: "discovery source", up: addSourceColumn},
+	{name: "scheduled follows", up: addNotBeforeColumn},
+	{name: "follow events by action", up: indexEventsByAction},
+	{name: "targeting filters", up: addTargetingColumns},
+	{name: "follow record uris", up: addFollowURIColumn},
+	{name: "follow campaigns", up: createFollowCampaigns},
+	{name: "profile labels", up: addLabelsColumn},
+	{name: "mutual flags", up: addMutualColumn},
+	{name: "event actors", up: addEventActorColumn},
+	{name: "unfollowed me dates", up: addUnfollowedMeColumn},
+	{name: "unfollow verifications", up: addVerificationsColumn},
+	{name: "null follow dates", up: nullFollowDates},
+}
+
+// migrate applies any migrations newer than the stored schema version
+func (s *SQLStore) migrate(ctx context.Context) error {
+	version := 0
+	stored, err := s.GetSetting(ctx, schemaVersionSetting)
+	if err != nil {
+		return err
+	}
+	if stored != "" {
+		if version, err = strconv.Atoi(stored); err != nil {
+			return fmt.Errorf("invalid schema version %q: %w", stored, err)
+		}
+	}
+
+	for i := version; i < len(migrations); i++ {
+		m := migrations[i]
+		s.logger.Info("Applying database migration %d: %s", i+1, m.name)
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", i+1, err)
+		}
+
+		if err := m.up(s, tx); err != nil {
+			tx.Rollback()
+			s.logger.Error("Migration %d (%s) failed: %v", i+1, m.name, err)
+			return fmt.Errorf("migration %d (%s) failed: %w", i+1, m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, s.rebind(`
+			INSERT INTO settings (key, value) VALUES (?, ?)
+			ON CONFLICT (key) DO UPDATE SET value = excluded.value
+		`), schemaVersionSetting, strconv.Itoa(i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", i+1, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// didKeyUserColumns are the users columns as of migrateUsersToDIDKey. Later
+// migrations add columns, so it must not use userColumns.
+const didKeyUserColumns = `handle, did, followers, saved_on, followed, last_checked, follow_date, priority, attempts`
+
+// migrateUsersToDIDKey rebuilds the users table with did as the primary key
+// and a unique handle, merging rows that share a DID. Rows without a DID are
+// merged into the row with their handle, or else kept with the handle as a
+// placeholder key until the account is saved with its DID.
+func migrateUsersToDIDKey(s *SQLStore, tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT ` + didKeyUserColumns + ` FROM users`)
+	if err != nil {
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+
+	var order []string
+	merged := make(map[string]models.TargetUser)
+	var unresolved []models.TargetUser
+	for rows.Next() {
+		var user models.TargetUser
+		var savedOn, lastChecked, followDate sql.NullTime
+		err := rows.Scan(&user.Handle, &user.DID, &user.Followers, &savedOn, &user.Followed,
+			&lastChecked, &followDate, &user.Priority, &user.Attempts)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+		user.SavedOn, user.LastChecked, user.FollowDate = savedOn.Time, lastChecked.Time, followDate.Time
+		if user.DID == "" {
+			unresolved = append(unresolved, user)
+			continue
+		}
+		if existing, ok := merged[user.DID]; ok {
+			merged[user.DID] = models.MergeUsers(existing, user)
+			continue
+		}
+		order = append(order, user.DID)
+		merged[user.DID] = user
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	byHandle := make(map[string]string, len(merged))
+	for did, user := range merged {
+		byHandle[user.Handle] = did
+	}
+	placeholders := 0
+	for _, user := range unresolved {
+		did, ok := byHandle[user.Handle]
+		if !ok {
+			did = user.Handle
+			user.DID = did
+			byHandle[user.Handle] = did
+			order = append(order, did)
+			merged[did] = user
+			placeholders++
+			continue
+		}
+		user.DID = did
+		merged[did] = models.MergeUsers(merged[did], user)
+	}
+
+	// The rows are held in memory, so the table can be rebuilt in place
+	statements := []string{
+		`DROP TABLE users`,
+		`CREATE TABLE users (
+			did TEXT PRIMARY KEY,
+			handle TEXT NOT NULL,
+			followers INTEGER,
+			saved_on TIMESTAMP,
+			followed BOOLEAN,
+			last_checked TIMESTAMP,
+			follow_date TIMESTAMP,
+			priority INTEGER DEFAULT 1,
+			attempts INTEGER DEFAULT 0
+		)`,
+		`CREATE UNIQUE INDEX users_handle ON users (handle)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	insert := s.rebind(`INSERT INTO users (` + didKeyUserColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	for _, did := range order {
+		user := merged[did]
+		_, err := tx.Exec(insert, user.Handle, user.DID, user.Followers, user.SavedOn, user.Followed,
+			user.LastChecked, user.FollowDate, user.Priority, user.Attempts)
+		if err != nil {
+			return fmt.Errorf("failed to copy user %s: %w", did, err)
+		}
+	}
+
+	if placeholders > 0 {
+		s.logger.Info("Kept %d users without a DID keyed by their handle; they are rekeyed when next saved with one", placeholders)
+	}
+	s.logger.Info("Migrated %d users to DID keys", len(order))
+	return nil
+}
+
+// createFollowEvents adds the follow/unfollow history table
+func createFollowEvents(s *SQLStore, tx *sql.Tx) error {
+	statements := []string{
+		`CREATE TABLE follow_events (
+			id ` + s.serialKey() + `,
+			did TEXT NOT NULL,
+			handle TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			strategy TEXT NOT NULL DEFAULT '',
+			result TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX follow_events_did ON follow_events (did, created_at)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createFollowers adds the snapshot of accounts that follow me
+func createFollowers(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE followers (
+		did TEXT PRIMARY KEY,
+		handle TEXT NOT NULL,
+		synced_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// createSessions adds persisted sessions, one per account
+func createSessions(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE sessions (
+		did TEXT PRIMARY KEY,
+		handle TEXT NOT NULL,
+		access_jwt TEXT NOT NULL,
+		refresh_jwt TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP
+	)`)
+	return err
+}
+
+// createBlocklist adds the denylist consulted before queuing or following
+func createBlocklist(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE blocklist (
+		subject TEXT PRIMARY KEY,
+		reason TEXT NOT NULL DEFAULT '',
+		added_on TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// createProtected adds the whitelist of accounts cleanup must never touch
+func createProtected(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE protected (
+		subject TEXT PRIMARY KEY,
+		reason TEXT NOT NULL DEFAULT '',
+		added_on TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// createDailyStats adds one growth snapshot row per day
+func createDailyStats(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE daily_stats (
+		day TEXT PRIMARY KEY,
+		followers INTEGER NOT NULL,
+		following INTEGER NOT NULL,
+		queue_depth INTEGER NOT NULL,
+		follows_performed INTEGER NOT NULL,
+		recorded_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// createUnfollowCampaigns adds resumable unfollow campaigns and their
+// per-account progress
+func createUnfollowCampaigns(s *SQLStore, tx *sql.Tx) error {
+	if _, err := tx.Exec(`CREATE TABLE unfollow_campaigns (
+		id ` + s.serialKey() + `,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		completed_at TIMESTAMP
+	)`); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`CREATE TABLE unfollow_targets (
+		campaign_id BIGINT NOT NULL REFERENCES unfollow_campaigns (id),
+		did TEXT NOT NULL,
+		handle TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		updated_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (campaign_id, did)
+	)`)
+	return err
+}
+
+// createAccountPauses adds the per-account paused state
+func createAccountPauses(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE account_pauses (
+		did TEXT PRIMARY KEY,
+		reason TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL,
+		paused_at TIMESTAMP NOT NULL,
+		until TIMESTAMP
+	)`)
+	return err
+}
+
+// createUsersArchive adds the archive for pruned users. Rows are kept as
+// JSON so the archive does not have to follow every users column change.
+func createUsersArchive(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE users_archive (
+		did TEXT PRIMARY KEY,
+		handle TEXT NOT NULL,
+		data TEXT NOT NULL,
+		archived_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// createFollowerObservations adds the follower count history of targets
+func createFollowerObservations(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE follower_observations (
+		did TEXT NOT NULL,
+		followers INTEGER NOT NULL,
+		observed_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (did, observed_at)
+	)`)
+	return err
+}
+
+// addFollowBackColumns records whether followed users followed back and
+// when they were unfollowed
+func addFollowBackColumns(s *SQLStore, tx *sql.Tx) error {
+	statements := []string{
+		`ALTER TABLE users ADD COLUMN followed_back BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE users ADD COLUMN followed_back_at TIMESTAMP`,
+		`ALTER TABLE users ADD COLUMN unfollowed_at TIMESTAMP`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProfileColumns stores profile metadata on users for display and filters
+func addProfileColumns(s *SQLStore, tx *sql.Tx) error {
+	statements := []string{
+		`ALTER TABLE users ADD COLUMN display_name TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE users ADD COLUMN description TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE users ADD COLUMN avatar TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE users ADD COLUMN posts_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN account_created_at TIMESTAMP`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSourceColumn records which discovery source first found each user
+func addSourceColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN source TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// addNotBeforeColumn lets a user carry a time before which it is not followed
+func addNotBeforeColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN not_before TIMESTAMP`)
+	return err
+}
+
+// indexEventsByAction speeds up counting recent follows for the daily and
+// weekly caps
+func indexEventsByAction(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE INDEX follow_events_action ON follow_events (action, created_at)`)
+	return err
+}
+
+// addTargetingColumns stores what the targeting filters judge candidates by
+// and why a candidate was rejected
+func addTargetingColumns(s *SQLStore, tx *sql.Tx) error {
+	statements := []string{
+		`ALTER TABLE users ADD COLUMN following INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN last_post_at TIMESTAMP`,
+		`ALTER TABLE users ADD COLUMN langs TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE users ADD COLUMN rejection TEXT NOT NULL DEFAULT ''`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFollowURIColumn stores the URI of my follow record for followed users
+func addFollowURIColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN follow_uri TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// createFollowCampaigns adds follow campaigns and tags users with the
+// campaign that found them
+func createFollowCampaigns(s *SQLStore, tx *sql.Tx) error {
+	statements := []string{
+		`CREATE TABLE follow_campaigns (
+			id ` + s.serialKey() + `,
+			name TEXT NOT NULL UNIQUE,
+			strategies TEXT NOT NULL,
+			target INTEGER NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP,
+			follows_per_day INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL,
+			finished_at TIMESTAMP,
+			outcome TEXT NOT NULL DEFAULT ''
+		)`,
+		`ALTER TABLE users ADD COLUMN campaign_id BIGINT NOT NULL DEFAULT 0`,
+		`CREATE INDEX users_campaign ON users (campaign_id)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addLabelsColumn stores the moderation labels on a user's profile, so label
+// rejections can be audited
+func addLabelsColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN labels TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// addMutualColumn flags the users who follow me back while I still follow
+// them, so unfollow policies can leave them alone
+func addMutualColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN mutual BOOLEAN NOT NULL DEFAULT FALSE`)
+	return err
+}
+
+// addEventActorColumn records which account took each action, and indexes
+// events by time for audit queries over a period
+func addEventActorColumn(s *SQLStore, tx *sql.Tx) error {
+	if _, err := tx.Exec(`ALTER TABLE follow_events ADD COLUMN actor TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`CREATE INDEX follow_events_created_at ON follow_events (created_at)`)
+	return err
+}
+
+// addUnfollowedMeColumn records when a user stopped following me back, which
+// starts their re-follow cooldown like my own unfollow does
+func addUnfollowedMeColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE users ADD COLUMN unfollowed_me_at TIMESTAMP`)
+	return err
+}
+
+// addVerificationsColumn counts the failed lookups of an unfollow target's
+// follow record, so a target that can't be verified doesn't stay open forever
+func addVerificationsColumn(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE unfollow_targets ADD COLUMN verifications INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// nullFollowDates clears the zero follow dates once stored for users never
+// followed, so "never followed" is NULL like the other missing dates
+func nullFollowDates(s *SQLStore, tx *sql.Tx) error {
+	_, err := tx.Exec(s.rebind(`UPDATE users SET follow_date = NULL WHERE follow_date = ?`), time.Time{})
+	return err
+}
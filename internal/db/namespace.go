@@ -0,0 +1,65 @@
+package db
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"bsky_follower/internal/models"
+)
+
+// namespacePrefix starts every per-account PostgreSQL schema name
+const namespacePrefix = "bsky_"
+
+// Namespace returns the storage namespace for the configured account, or ""
+// when namespacing is disabled. The expected DID is preferred over the login
+// identifier because handles can change.
+func Namespace(config *models.Config) string {
+	if !config.AccountNamespace {
+		return ""
+	}
+	account := config.AccountDID
+	if account == "" {
+		account = config.Identifier
+	}
+	return sanitizeNamespace(account)
+}
+
+// sanitizeNamespace lowercases an account and replaces everything but
+// letters and digits with underscores, e.g. did:plc:abc -> did_plc_abc
+func sanitizeNamespace(account string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.ToLower(account))
+}
+
+// namespacedPath inserts the namespace before a database file's extension,
+// e.g. users.db -> users.did_plc_abc.db
+func namespacedPath(dbPath, namespace string) string {
+	if namespace == "" {
+		return dbPath
+	}
+	ext := filepath.Ext(dbPath)
+	return strings.TrimSuffix(dbPath, ext) + "." + namespace + ext
+}
+
+// namespacedURL restricts a PostgreSQL connection to the namespace's schema
+// by setting search_path, for both URL and keyword/value connection strings
+func namespacedURL(databaseURL, schema string) (string, error) {
+	if !strings.Contains(databaseURL, "://") {
+		return databaseURL + " search_path=" + schema, nil
+	}
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
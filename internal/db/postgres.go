@@ -0,0 +1,44 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewPostgresStore connects to a PostgreSQL database using pgx. A non-empty
+// namespace keeps every table in its own schema, created if needed.
+func NewPostgresStore(databaseURL, namespace string, logger Logger) (*SQLStore, error) {
+	schema := ""
+	if namespace != "" {
+		schema = namespacePrefix + namespace
+		var err error
+		if databaseURL, err = namespacedURL(databaseURL, schema); err != nil {
+			logger.Error("Failed to parse database URL: %v", err)
+			return nil, fmt.Errorf("failed to parse database URL: %w", err)
+		}
+	}
+
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		logger.Error("Failed to open postgres connection: %v", err)
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		logger.Error("Failed to connect to postgres: %v", err)
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	if schema != "" {
+		if _, err := db.Exec(`CREATE SCHEMA IF NOT EXISTS ` + schema); err != nil {
+			db.Close()
+			logger.Error("Failed to create schema %s: %v", schema, err)
+			return nil, fmt.Errorf("failed to create schema %s: %w", schema, err)
+		}
+	}
+
+	return newSQLStore(db, dialectPostgres, logger)
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// AddProtected adds or updates a protected account
+func (s *SQLStore) AddProtected(ctx context.Context, entry models.ProtectedAccount) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if entry.AddedOn.IsZero() {
+		entry.AddedOn = time.Now()
+	}
+
+	_, err := s.exec(ctx, `
+		INSERT INTO protected (subject, reason, added_on) VALUES (?, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET reason = excluded.reason
+	`, entry.Subject, entry.Reason, entry.AddedOn)
+	if err != nil {
+		s.logger.Error("Failed to protect %s: %v", entry.Subject, err)
+		return fmt.Errorf("failed to add protected account: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveProtected removes a protected account by subject
+func (s *SQLStore) RemoveProtected(ctx context.Context, subject string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `DELETE FROM protected WHERE subject = ?`, subject); err != nil {
+		s.logger.Error("Failed to unprotect %s: %v", subject, err)
+		return fmt.Errorf("failed to remove protected account: %w", err)
+	}
+
+	return nil
+}
+
+// LoadProtected returns all protected accounts, most recent first
+func (s *SQLStore) LoadProtected(ctx context.Context) ([]models.ProtectedAccount, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `SELECT subject, reason, added_on FROM protected ORDER BY added_on DESC`)
+	if err != nil {
+		s.logger.Error("Failed to query protected accounts: %v", err)
+		return nil, fmt.Errorf("failed to query protected accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.ProtectedAccount
+	for rows.Next() {
+		var entry models.ProtectedAccount
+		if err := rows.Scan(&entry.Subject, &entry.Reason, &entry.AddedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan protected row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// IsProtected reports whether a DID or handle is protected
+func (s *SQLStore) IsProtected(ctx context.Context, did, handle string) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := s.queryRow(ctx, `SELECT COUNT(*) FROM protected WHERE subject = ? OR subject = ?`, did, handle).Scan(&count)
+	if err != nil {
+		s.logger.Error("Failed to check protected accounts: %v", err)
+		return false, fmt.Errorf("failed to check protected accounts: %w", err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// PruneUsers removes never-followed users that were saved and last checked
+// before the cutoff. Users that were ever followed, unfollowed me or were
+// rejected by the filters keep their history, and protected accounts are
+// never pruned. With archive set,
+// pruned users are copied to users_archive first. It returns the number of
+// users pruned.
+func (s *SQLStore) PruneUsers(ctx context.Context, cutoff time.Time, archive bool) (int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction: %v", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, s.rebind(`
+		SELECT `+userColumns+` FROM users u
+		WHERE (followed IS NULL OR NOT followed)
+			AND follow_date IS NULL AND unfollowed_at IS NULL AND unfollowed_me_at IS NULL
+			AND rejection = ''
+			AND last_checked < ? AND saved_on < ?
+			AND NOT EXISTS (SELECT 1 FROM protected p WHERE p.subject = u.did OR p.subject = u.handle)
+	`), cutoff, cutoff)
+	if err != nil {
+		s.logger.Error("Failed to query stale users: %v", err)
+		return 0, fmt.Errorf("failed to query stale users: %w", err)
+	}
+
+	var stale []models.TargetUser
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		stale = append(stale, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, user := range stale {
+		if archive {
+			data, err := json.Marshal(user)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encode user %s: %w", user.Handle, err)
+			}
+			if _, err := tx.ExecContext(ctx, s.rebind(`
+				INSERT INTO users_archive (did, handle, data, archived_at) VALUES (?, ?, ?, ?)
+				ON CONFLICT (did) DO UPDATE SET
+					handle = excluded.handle,
+					data = excluded.data,
+					archived_at = excluded.archived_at
+			`), user.DID, user.Handle, string(data), now); err != nil {
+				s.logger.Error("Failed to archive user %s: %v", user.Handle, err)
+				return 0, fmt.Errorf("failed to archive user: %w", err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM users WHERE did = ?`), user.DID); err != nil {
+			s.logger.Error("Failed to prune user %s: %v", user.Handle, err)
+			return 0, fmt.Errorf("failed to prune user: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit pruning: %w", err)
+	}
+	return len(stale), nil
+}
@@ -0,0 +1,301 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Orders QueryUsers can sort by, highest or newest first
+const (
+	SortPriority     = "priority"
+	SortFollowers    = "followers"
+	SortSaved        = "saved"
+	SortFollowedBack = "followed back"
+)
+
+// UserSorts lists the orders QueryUsers supports
+var UserSorts = []string{SortPriority, SortFollowers, SortSaved, SortFollowedBack}
+
+// userOrders maps each sort to its ORDER BY clause
+var userOrders = map[string]string{
+	SortPriority:     `priority DESC, followers DESC, did`,
+	SortFollowers:    `followers DESC, priority DESC, did`,
+	SortSaved:        `saved_on DESC, did`,
+	SortFollowedBack: `followed_back_at IS NULL, followed_back_at DESC, did`,
+}
+
+// UserFilter narrows QueryUsers and CountUsers. Zero values leave a field
+// unconstrained; ranges are inclusive.
+type UserFilter struct {
+	Followed     *bool // nil for both followed and pending users
+	FollowedBack *bool // nil regardless of whether they followed back
+	Unfollowed   *bool // nil regardless of whether I unfollowed them
+	Mutual       *bool // nil regardless of whether we follow each other
+	Rejected     *bool // nil regardless of whether the targeting filters rejected them
+
+	MinFollowers int
+	MaxFollowers int
+	MinPriority  int
+	MaxPriority  int
+
+	SavedAfter     time.Time
+	SavedBefore    time.Time
+	CheckedAfter   time.Time
+	CheckedBefore  time.Time
+	FollowedAfter  time.Time
+	FollowedBefore time.Time
+
+	FollowedBackAfter time.Time // they followed me back at or after this time
+
+	BioContains    string // case-insensitive substring of the profile description
+	HandleContains string // case-insensitive substring of the handle
+	Source         string // exact discovery source, or a kind such as "search" matching all its parameters
+	Campaign       int64  // follow campaign that found the users, 0 for any
+
+	Sort string // order of QueryUsers results, SortPriority if empty
+}
+
+// where builds the WHERE clause and arguments for a filter
+func (f UserFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+
+	if f.Followed != nil {
+		add(`followed = ?`, *f.Followed)
+	}
+	if f.FollowedBack != nil {
+		add(`followed_back = ?`, *f.FollowedBack)
+	}
+	if f.Mutual != nil {
+		add(`mutual = ?`, *f.Mutual)
+	}
+	if f.Unfollowed != nil {
+		if *f.Unfollowed {
+			conds = append(conds, `unfollowed_at IS NOT NULL`)
+		} else {
+			conds = append(conds, `unfollowed_at IS NULL`)
+		}
+	}
+	if f.Rejected != nil {
+		if *f.Rejected {
+			conds = append(conds, `rejection <> ''`)
+		} else {
+			conds = append(conds, `rejection = ''`)
+		}
+	}
+	if f.MinFollowers > 0 {
+		add(`followers >= ?`, f.MinFollowers)
+	}
+	if f.MaxFollowers > 0 {
+		add(`followers <= ?`, f.MaxFollowers)
+	}
+	if f.MinPriority != 0 {
+		add(`priority >= ?`, f.MinPriority)
+	}
+	if f.MaxPriority != 0 {
+		add(`priority <= ?`, f.MaxPriority)
+	}
+	if !f.SavedAfter.IsZero() {
+		add(`saved_on >= ?`, f.SavedAfter)
+	}
+	if !f.SavedBefore.IsZero() {
+		add(`saved_on <= ?`, f.SavedBefore)
+	}
+	if !f.CheckedAfter.IsZero() {
+		add(`last_checked >= ?`, f.CheckedAfter)
+	}
+	if !f.CheckedBefore.IsZero() {
+		add(`last_checked <= ?`, f.CheckedBefore)
+	}
+	if !f.FollowedAfter.IsZero() {
+		add(`follow_date >= ?`, f.FollowedAfter)
+	}
+	if !f.FollowedBefore.IsZero() {
+		add(`follow_date <= ?`, f.FollowedBefore)
+	}
+	if !f.FollowedBackAfter.IsZero() {
+		add(`followed_back_at >= ?`, f.FollowedBackAfter)
+	}
+	if f.Source != "" {
+		conds = append(conds, `(source = ? OR source LIKE ? ESCAPE '\')`)
+		args = append(args, f.Source, escapeLike(f.Source)+":%")
+	}
+	if f.Campaign != 0 {
+		add(`campaign_id = ?`, f.Campaign)
+	}
+	if f.BioContains != "" {
+		add(`LOWER(description) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(f.BioContains))+"%")
+	}
+	if f.HandleContains != "" {
+		add(`LOWER(handle) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(f.HandleContains))+"%")
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return ` WHERE ` + strings.Join(conds, ` AND `), args
+}
+
+// likeEscaper escapes the LIKE wildcards, and the escape character itself,
+// so the text matches only as typed
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes text safe to put in a LIKE pattern ending in ESCAPE '\'
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+// QueryUsers returns one page of the users matching a filter in the
+// filter's order, highest priority and follower count first by default. A
+// limit of 0 returns every match.
+func (s *SQLStore) QueryUsers(ctx context.Context, filter UserFilter, limit, offset int) ([]models.TargetUser, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	order, ok := userOrders[filter.Sort]
+	if !ok {
+		if filter.Sort != "" {
+			return nil, fmt.Errorf("unknown user sort %q", filter.Sort)
+		}
+		order = userOrders[SortPriority]
+	}
+
+	where, args := filter.where()
+	query := `SELECT ` + userColumns + ` FROM users` + where + ` ORDER BY ` + order
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("Failed to query users: %v", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.TargetUser
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			s.logger.Error("Failed to scan user row: %v", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// CountUsers returns the number of users matching a filter
+func (s *SQLStore) CountUsers(ctx context.Context, filter UserFilter) (int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+
+	var count int
+	if err := s.queryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&count); err != nil {
+		s.logger.Error("Failed to count users: %v", err)
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
+// EventFilter narrows QueryEvents. Zero values leave a field unconstrained;
+// the time range is inclusive.
+type EventFilter struct {
+	DID    string
+	Actor  string // DID of the account that acted
+	Action string
+	Result string
+	Since  time.Time
+	Until  time.Time
+}
+
+// where builds the WHERE clause and arguments for a filter
+func (f EventFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+
+	if f.DID != "" {
+		add(`did = ?`, f.DID)
+	}
+	if f.Actor != "" {
+		add(`actor = ?`, f.Actor)
+	}
+	if f.Action != "" {
+		add(`action = ?`, f.Action)
+	}
+	if f.Result != "" {
+		add(`result = ?`, f.Result)
+	}
+	if !f.Since.IsZero() {
+		add(`created_at >= ?`, f.Since)
+	}
+	if !f.Until.IsZero() {
+		add(`created_at <= ?`, f.Until)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return ` WHERE ` + strings.Join(conds, ` AND `), args
+}
+
+// QueryEvents returns the most recent events matching a filter, newest
+// first. A limit of 0 returns every match.
+func (s *SQLStore) QueryEvents(ctx context.Context, filter EventFilter, limit int) ([]models.FollowEvent, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+	query := `SELECT id, actor, did, handle, action, strategy, result, error, created_at FROM follow_events` + where +
+		` ORDER BY created_at DESC, id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("Failed to query events: %v", err)
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.FollowEvent
+	for rows.Next() {
+		var event models.FollowEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.Actor,
+			&event.DID,
+			&event.Handle,
+			&event.Action,
+			&event.Strategy,
+			&event.Result,
+			&event.Error,
+			&event.CreatedAt,
+		); err != nil {
+			s.logger.Error("Failed to scan event row: %v", err)
+			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
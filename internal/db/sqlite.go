@@ -0,0 +1,53 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteBusyTimeout is how long a connection waits for a lock before failing
+// with SQLITE_BUSY
+const sqliteBusyTimeout = 5 * time.Second
+
+// NewSQLiteStore opens (and if needed creates) a SQLite store. The database
+// runs in WAL mode so UI reads do not block queue writes, with a busy timeout
+// and a single connection so writers queue up in Go instead of failing.
+func NewSQLiteStore(dbPath string, logger Logger) (*SQLStore, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath))
+	if err != nil {
+		logger.Error("Failed to open database: %v", err)
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite allows one writer at a time; a single pooled connection
+	// serializes writes instead of surfacing SQLITE_BUSY to callers
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	var journalMode string
+	if err := db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode); err != nil {
+		db.Close()
+		logger.Error("Failed to read journal mode: %v", err)
+		return nil, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	if !strings.EqualFold(journalMode, "wal") {
+		logger.Info("SQLite journal mode is %s, WAL is not available for %s", journalMode, dbPath)
+	}
+
+	return newSQLStore(db, dialectSQLite, logger)
+}
+
+// sqliteDSN adds the connection pragmas to a database path
+func sqliteDSN(dbPath string) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)&_pragma=synchronous(NORMAL)&_txlock=immediate",
+		dbPath, sep, sqliteBusyTimeout.Milliseconds())
+}
@@ -0,0 +1,942 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// queryTimeout bounds every store call, so a stuck disk or a held lock
+// fails the call instead of hanging the service loop
+const queryTimeout = 30 * time.Second
+
+// SQL dialects supported by SQLStore
+const (
+	dialectSQLite   = "sqlite"
+	dialectPostgres = "postgres"
+)
+
+// SQLStore is a Store backed by a database/sql connection. Queries are
+// written with ? placeholders and rebound for the dialect in use.
+type SQLStore struct {
+	db      *sql.DB
+	dialect string
+	logger  Logger
+}
+
+// newSQLStore wraps an open connection and initializes the schema
+func newSQLStore(db *sql.DB, dialect string, logger Logger) (*SQLStore, error) {
+	store := &SQLStore{
+		db:      db,
+		dialect: dialect,
+		logger:  logger,
+	}
+
+	// Migrations can take a while on large databases, so startup has no timeout
+	if err := store.init(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// serialKey returns the dialect's auto-incrementing integer primary key type
+func (s *SQLStore) serialKey() string {
+	if s.dialect == dialectPostgres {
+		return "BIGSERIAL PRIMARY KEY"
+	}
+	return "INTEGER PRIMARY KEY AUTOINCREMENT"
+}
+
+// rebind rewrites ? placeholders into the dialect's placeholder syntax
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != dialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// withTimeout derives the context a store call runs its queries under
+func (s *SQLStore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+func (s *SQLStore) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLStore) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLStore) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.rebind(query), args...)
+}
+
+// init initializes the database schema
+func (s *SQLStore) init(ctx context.Context) error {
+	_, err := s.exec(ctx, `
+		CREATE TABLE IF NOT EXISTS users (
+			handle TEXT PRIMARY KEY,
+			did TEXT,
+			followers INTEGER,
+			saved_on TIMESTAMP,
+			followed BOOLEAN,
+			last_checked TIMESTAMP,
+			follow_date TIMESTAMP,
+			priority INTEGER DEFAULT 1,
+			attempts INTEGER DEFAULT 0
+		)
+	`)
+	if err != nil {
+		s.logger.Error("Failed to create table", "error", err)
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	_, err = s.exec(ctx, `
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT
+		)
+	`)
+	if err != nil {
+		s.logger.Error("Failed to create settings table: %v", err)
+		return fmt.Errorf("failed to create settings table: %w", err)
+	}
+
+	return s.migrate(ctx)
+}
+
+// userColumns lists the users columns in the order scanUser expects
+const userColumns = `handle, did, followers, saved_on, followed, last_checked, follow_date, priority, attempts,
+	followed_back, followed_back_at, unfollowed_at,
+	display_name, description, avatar, posts_count, account_created_at, source, not_before,
+	following, last_post_at, langs, rejection, follow_uri, campaign_id, labels, mutual, unfollowed_me_at`
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUser scans a row selected with userColumns
+func scanUser(row scanner) (models.TargetUser, error) {
+	var user models.TargetUser
+	var savedOn, lastChecked, followDate, followedBackAt, unfollowedAt, unfollowedMeAt, accountCreatedAt, notBefore, lastPostAt sql.NullTime
+	var langs, labels string
+
+	err := row.Scan(
+		&user.Handle,
+		&user.DID,
+		&user.Followers,
+		&savedOn,
+		&user.Followed,
+		&lastChecked,
+		&followDate,
+		&user.Priority,
+		&user.Attempts,
+		&user.FollowedBack,
+		&followedBackAt,
+		&unfollowedAt,
+		&user.DisplayName,
+		&user.Description,
+		&user.Avatar,
+		&user.PostsCount,
+		&accountCreatedAt,
+		&user.Source,
+		&notBefore,
+		&user.Following,
+		&lastPostAt,
+		&langs,
+		&user.Rejection,
+		&user.FollowURI,
+		&user.Campaign,
+		&labels,
+		&user.Mutual,
+		&unfollowedMeAt,
+	)
+	if err != nil {
+		return user, err
+	}
+
+	if savedOn.Valid {
+		user.SavedOn = savedOn.Time
+	}
+	if lastChecked.Valid {
+		user.LastChecked = lastChecked.Time
+	}
+	if followDate.Valid {
+		user.FollowDate = followDate.Time
+	}
+	if followedBackAt.Valid {
+		user.FollowedBackAt = followedBackAt.Time
+	}
+	if unfollowedAt.Valid {
+		user.UnfollowedAt = unfollowedAt.Time
+	}
+	if unfollowedMeAt.Valid {
+		user.UnfollowedMeAt = unfollowedMeAt.Time
+	}
+	if accountCreatedAt.Valid {
+		user.AccountCreatedAt = accountCreatedAt.Time
+	}
+	if notBefore.Valid {
+		user.NotBefore = notBefore.Time
+	}
+	if lastPostAt.Valid {
+		user.LastPostAt = lastPostAt.Time
+	}
+	if langs != "" {
+		user.Langs = strings.Split(langs, ",")
+	}
+	if labels != "" {
+		user.Labels = strings.Split(labels, ",")
+	}
+
+	return user, nil
+}
+
+// LoadUsers loads all users from the database
+func (s *SQLStore) LoadUsers(ctx context.Context) ([]models.TargetUser, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `SELECT `+userColumns+` FROM users`)
+	if err != nil {
+		s.logger.Error("Failed to query users: %v", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.TargetUser
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			s.logger.Error("Failed to scan user row: %v", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// SaveUser saves a user to the database, keyed by DID. If the handle was
+// previously held by a different DID, that row's handle is replaced with
+// its DID until the account is seen again under its new handle. A row the
+// DID-key migration kept with its handle as a placeholder key is rekeyed to
+// the DID, or dropped if the DID is already stored.
+func (s *SQLStore) SaveUser(ctx context.Context, user models.TargetUser) error {
+	return s.SaveUsers(ctx, []models.TargetUser{user})
+}
+
+// SaveUsers saves a batch of users like SaveUser, in a single transaction
+// with prepared statements. Either every user is saved or none is.
+func (s *SQLStore) SaveUsers(ctx context.Context, users []models.TargetUser) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	for _, user := range users {
+		if user.DID == "" {
+			return fmt.Errorf("cannot save user %s without a DID", user.Handle)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	claim, err := tx.PrepareContext(ctx, s.rebind(`
+		UPDATE users SET did = ?
+		WHERE did = ? AND handle = did AND NOT EXISTS (SELECT 1 FROM users WHERE did = ?)
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare placeholder claim: %w", err)
+	}
+	defer claim.Close()
+
+	drop, err := tx.PrepareContext(ctx, s.rebind(`DELETE FROM users WHERE did = ? AND handle = did AND did <> ?`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare placeholder drop: %w", err)
+	}
+	defer drop.Close()
+
+	release, err := tx.PrepareContext(ctx, s.rebind(`UPDATE users SET handle = did WHERE handle = ? AND did <> ?`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare handle release: %w", err)
+	}
+	defer release.Close()
+
+	upsert, err := tx.PrepareContext(ctx, s.rebind(upsertUserQuery))
+	if err != nil {
+		return fmt.Errorf("failed to prepare user upsert: %w", err)
+	}
+	defer upsert.Close()
+
+	for _, user := range users {
+		if _, err := claim.ExecContext(ctx, user.DID, user.Handle, user.DID); err != nil {
+			s.logger.Error("Failed to claim the placeholder of %s: %v", user.Handle, err)
+			return fmt.Errorf("failed to claim placeholder: %w", err)
+		}
+		if _, err := drop.ExecContext(ctx, user.Handle, user.DID); err != nil {
+			s.logger.Error("Failed to drop the placeholder of %s: %v", user.Handle, err)
+			return fmt.Errorf("failed to drop placeholder: %w", err)
+		}
+		if _, err := release.ExecContext(ctx, user.Handle, user.DID); err != nil {
+			s.logger.Error("Failed to release handle %s: %v", user.Handle, err)
+			return fmt.Errorf("failed to release handle: %w", err)
+		}
+		if _, err := upsert.ExecContext(ctx, userArgs(user)...); err != nil {
+			s.logger.Error("Failed to save user %s: %v", user.Handle, err)
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Failed to commit %d users: %v", len(users), err)
+		return fmt.Errorf("failed to commit users: %w", err)
+	}
+	return nil
+}
+
+// upsertUserQuery inserts or updates a user row keyed by DID, taking userArgs.
+// The discovery source and campaign are set on insert and only filled in
+// later if empty.
+const upsertUserQuery = `
+	INSERT INTO users (` + userColumns + `)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (did) DO UPDATE SET
+		handle = excluded.handle,
+		followers = excluded.followers,
+		saved_on = excluded.saved_on,
+		followed = excluded.followed,
+		last_checked = excluded.last_checked,
+		follow_date = excluded.follow_date,
+		priority = excluded.priority,
+		attempts = excluded.attempts,
+		followed_back = excluded.followed_back,
+		followed_back_at = excluded.followed_back_at,
+		unfollowed_at = excluded.unfollowed_at,
+		display_name = excluded.display_name,
+		description = excluded.description,
+		avatar = excluded.avatar,
+		posts_count = excluded.posts_count,
+		account_created_at = excluded.account_created_at,
+		source = CASE WHEN users.source = '' THEN excluded.source ELSE users.source END,
+		not_before = excluded.not_before,
+		following = excluded.following,
+		last_post_at = excluded.last_post_at,
+		langs = excluded.langs,
+		rejection = excluded.rejection,
+		follow_uri = excluded.follow_uri,
+		campaign_id = CASE WHEN users.campaign_id = 0 THEN excluded.campaign_id ELSE users.campaign_id END,
+		labels = excluded.labels,
+		mutual = excluded.mutual,
+		unfollowed_me_at = excluded.unfollowed_me_at
+`
+
+// userArgs returns a user's values in userColumns order
+func userArgs(user models.TargetUser) []interface{} {
+	return []interface{}{
+		user.Handle,
+		user.DID,
+		user.Followers,
+		user.SavedOn,
+		user.Followed,
+		user.LastChecked,
+		nullTime(user.FollowDate),
+		user.Priority,
+		user.Attempts,
+		user.FollowedBack,
+		nullTime(user.FollowedBackAt),
+		nullTime(user.UnfollowedAt),
+		user.DisplayName,
+		user.Description,
+		user.Avatar,
+		user.PostsCount,
+		nullTime(user.AccountCreatedAt),
+		user.Source,
+		nullTime(user.NotBefore),
+		user.Following,
+		nullTime(user.LastPostAt),
+		strings.Join(user.Langs, ","),
+		user.Rejection,
+		user.FollowURI,
+		user.Campaign,
+		strings.Join(user.Labels, ","),
+		user.Mutual,
+		nullTime(user.UnfollowedMeAt),
+	}
+}
+
+// nullTime stores a zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// DeleteUser removes a user from the database
+func (s *SQLStore) DeleteUser(ctx context.Context, handle string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `DELETE FROM users WHERE handle = ?`, handle); err != nil {
+		s.logger.Error("Failed to delete user %s: %v", handle, err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// RecordEvent appends an event to the history
+func (s *SQLStore) RecordEvent(ctx context.Context, event models.FollowEvent) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := s.exec(ctx, `
+		INSERT INTO follow_events (actor, did, handle, action, strategy, result, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, event.Actor, event.DID, event.Handle, event.Action, event.Strategy, event.Result, event.Error, event.CreatedAt)
+	if err != nil {
+		s.logger.Error("Failed to record %s event for %s: %v", event.Action, event.DID, err)
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	return nil
+}
+
+// LoadEvents returns the most recent events for a DID (all DIDs if empty), newest first
+func (s *SQLStore) LoadEvents(ctx context.Context, did string, limit int) ([]models.FollowEvent, error) {
+	return s.QueryEvents(ctx, EventFilter{DID: did}, limit)
+}
+
+// LoadEventTimes returns when the events with an action and result since a
+// time were recorded, oldest first
+func (s *SQLStore) LoadEventTimes(ctx context.Context, action, result string, since time.Time) ([]time.Time, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `
+		SELECT created_at FROM follow_events
+		WHERE action = ? AND result = ? AND created_at > ?
+		ORDER BY created_at
+	`, action, result, since)
+	if err != nil {
+		s.logger.Error("Failed to query %s event times: %v", action, err)
+		return nil, fmt.Errorf("failed to query event times: %w", err)
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			s.logger.Error("Failed to scan event time: %v", err)
+			return nil, fmt.Errorf("failed to scan event time: %w", err)
+		}
+		times = append(times, t)
+	}
+
+	return times, rows.Err()
+}
+
+// RecordFollowerCounts appends follower count observations of targets
+func (s *SQLStore) RecordFollowerCounts(ctx context.Context, observations []models.FollowerObservation) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(`
+		INSERT INTO follower_observations (did, followers, observed_at) VALUES (?, ?, ?)
+		ON CONFLICT (did, observed_at) DO UPDATE SET followers = excluded.followers
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare observation insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, observation := range observations {
+		if observation.ObservedAt.IsZero() {
+			observation.ObservedAt = time.Now()
+		}
+		if _, err := stmt.ExecContext(ctx, observation.DID, observation.Followers, observation.ObservedAt); err != nil {
+			s.logger.Error("Failed to record follower count for %s: %v", observation.DID, err)
+			return fmt.Errorf("failed to record follower count: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LoadFollowerHistory returns observations since a time for a DID (all DIDs
+// if empty), ordered by DID and then oldest first
+func (s *SQLStore) LoadFollowerHistory(ctx context.Context, did string, since time.Time) ([]models.FollowerObservation, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT did, followers, observed_at FROM follower_observations WHERE observed_at >= ?`
+	args := []interface{}{since}
+	if did != "" {
+		query += ` AND did = ?`
+		args = append(args, did)
+	}
+	query += ` ORDER BY did, observed_at`
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("Failed to query follower history: %v", err)
+		return nil, fmt.Errorf("failed to query follower history: %w", err)
+	}
+	defer rows.Close()
+
+	var observations []models.FollowerObservation
+	for rows.Next() {
+		var observation models.FollowerObservation
+		if err := rows.Scan(&observation.DID, &observation.Followers, &observation.ObservedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan observation row: %w", err)
+		}
+		observations = append(observations, observation)
+	}
+
+	return observations, rows.Err()
+}
+
+// SaveFollowers replaces the snapshot of accounts that follow me
+func (s *SQLStore) SaveFollowers(ctx context.Context, followers []models.Actor) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM followers`); err != nil {
+		s.logger.Error("Failed to clear followers snapshot: %v", err)
+		return fmt.Errorf("failed to clear followers snapshot: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(`
+		INSERT INTO followers (did, handle, synced_at) VALUES (?, ?, ?)
+		ON CONFLICT (did) DO NOTHING
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare followers insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, follower := range followers {
+		if _, err := stmt.ExecContext(ctx, follower.Did, follower.Handle, now); err != nil {
+			s.logger.Error("Failed to save follower %s: %v", follower.Handle, err)
+			return fmt.Errorf("failed to save follower: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LoadFollowers returns the latest followers snapshot
+func (s *SQLStore) LoadFollowers(ctx context.Context) ([]models.Actor, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `SELECT did, handle FROM followers`)
+	if err != nil {
+		s.logger.Error("Failed to query followers: %v", err)
+		return nil, fmt.Errorf("failed to query followers: %w", err)
+	}
+	defer rows.Close()
+
+	var followers []models.Actor
+	for rows.Next() {
+		var follower models.Actor
+		if err := rows.Scan(&follower.Did, &follower.Handle); err != nil {
+			return nil, fmt.Errorf("failed to scan follower row: %w", err)
+		}
+		followers = append(followers, follower)
+	}
+
+	return followers, rows.Err()
+}
+
+// SaveSession stores the session for its account, replacing any previous one
+func (s *SQLStore) SaveSession(ctx context.Context, session *models.Session) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.exec(ctx, `
+		INSERT INTO sessions (did, handle, access_jwt, refresh_jwt, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (did) DO UPDATE SET
+			handle = excluded.handle,
+			access_jwt = excluded.access_jwt,
+			refresh_jwt = excluded.refresh_jwt,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+	`, session.Did, session.Handle, session.AccessJwt, session.RefreshJwt, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		s.logger.Error("Failed to save session for %s: %v", session.Did, err)
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession returns the stored session for an account DID, or nil if none
+func (s *SQLStore) LoadSession(ctx context.Context, did string) (*models.Session, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var session models.Session
+	err := s.queryRow(ctx, `
+		SELECT did, handle, access_jwt, refresh_jwt, created_at, expires_at
+		FROM sessions WHERE did = ?
+	`, did).Scan(
+		&session.Did,
+		&session.Handle,
+		&session.AccessJwt,
+		&session.RefreshJwt,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to load session for %s: %v", did, err)
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// DeleteSession removes the stored session for an account DID
+func (s *SQLStore) DeleteSession(ctx context.Context, did string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `DELETE FROM sessions WHERE did = ?`, did); err != nil {
+		s.logger.Error("Failed to delete session for %s: %v", did, err)
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
+}
+
+// ErrMutual is returned when an operation would block or unfollow a mutual,
+// an account that follows me and that I follow
+var ErrMutual = errors.New("account is a mutual")
+
+// AddToBlocklist adds or updates a blocklist entry. Mutuals are refused.
+func (s *SQLStore) AddToBlocklist(ctx context.Context, entry models.BlocklistEntry) error {
+	mutual, err := s.IsMutual(ctx, entry.Subject, entry.Subject)
+	if err != nil {
+		return err
+	}
+	if mutual {
+		s.logger.Error("Refusing to blocklist mutual %s (%s)", entry.Subject, entry.Reason)
+		return fmt.Errorf("cannot blocklist %s: %w", entry.Subject, ErrMutual)
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if entry.AddedOn.IsZero() {
+		entry.AddedOn = time.Now()
+	}
+
+	_, err = s.exec(ctx, `
+		INSERT INTO blocklist (subject, reason, added_on) VALUES (?, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET reason = excluded.reason
+	`, entry.Subject, entry.Reason, entry.AddedOn)
+	if err != nil {
+		s.logger.Error("Failed to add %s to blocklist: %v", entry.Subject, err)
+		return fmt.Errorf("failed to add to blocklist: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFromBlocklist removes a blocklist entry by subject
+func (s *SQLStore) RemoveFromBlocklist(ctx context.Context, subject string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `DELETE FROM blocklist WHERE subject = ?`, subject); err != nil {
+		s.logger.Error("Failed to remove %s from blocklist: %v", subject, err)
+		return fmt.Errorf("failed to remove from blocklist: %w", err)
+	}
+
+	return nil
+}
+
+// LoadBlocklist returns all blocklist entries, most recent first
+func (s *SQLStore) LoadBlocklist(ctx context.Context) ([]models.BlocklistEntry, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `SELECT subject, reason, added_on FROM blocklist ORDER BY added_on DESC`)
+	if err != nil {
+		s.logger.Error("Failed to query blocklist: %v", err)
+		return nil, fmt.Errorf("failed to query blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.BlocklistEntry
+	for rows.Next() {
+		var entry models.BlocklistEntry
+		if err := rows.Scan(&entry.Subject, &entry.Reason, &entry.AddedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan blocklist row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// IsBlocked reports whether a DID or handle is on the blocklist
+func (s *SQLStore) IsBlocked(ctx context.Context, did, handle string) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := s.queryRow(ctx, `SELECT COUNT(*) FROM blocklist WHERE subject = ? OR subject = ?`, did, handle).Scan(&count)
+	if err != nil {
+		s.logger.Error("Failed to check blocklist: %v", err)
+		return false, fmt.Errorf("failed to check blocklist: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// IsMutual reports whether a DID or handle belongs to a stored user I follow
+// who was flagged as a mutual or is in the followers snapshot
+func (s *SQLStore) IsMutual(ctx context.Context, did, handle string) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := s.queryRow(ctx, `
+		SELECT COUNT(*) FROM users u LEFT JOIN followers f ON f.did = u.did
+		WHERE u.followed AND (u.mutual OR f.did IS NOT NULL) AND (u.did = ? OR u.handle = ?)
+	`, did, handle).Scan(&count)
+	if err != nil {
+		s.logger.Error("Failed to check mutual %s: %v", handle, err)
+		return false, fmt.Errorf("failed to check mutual: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// SavePause pauses an account, replacing any previous pause
+func (s *SQLStore) SavePause(ctx context.Context, pause models.AccountPause) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if pause.PausedAt.IsZero() {
+		pause.PausedAt = time.Now()
+	}
+
+	_, err := s.exec(ctx, `
+		INSERT INTO account_pauses (did, reason, source, paused_at, until) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (did) DO UPDATE SET
+			reason = excluded.reason,
+			source = excluded.source,
+			paused_at = excluded.paused_at,
+			until = excluded.until
+	`, pause.DID, pause.Reason, pause.Source, pause.PausedAt, nullTime(pause.Until))
+	if err != nil {
+		s.logger.Error("Failed to pause account %s: %v", pause.DID, err)
+		return fmt.Errorf("failed to pause account: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPause returns the pause for an account DID, or nil if it is not paused
+func (s *SQLStore) LoadPause(ctx context.Context, did string) (*models.AccountPause, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var pause models.AccountPause
+	var until sql.NullTime
+	err := s.queryRow(ctx, `
+		SELECT did, reason, source, paused_at, until FROM account_pauses WHERE did = ?
+	`, did).Scan(&pause.DID, &pause.Reason, &pause.Source, &pause.PausedAt, &until)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to load pause for %s: %v", did, err)
+		return nil, fmt.Errorf("failed to load pause: %w", err)
+	}
+
+	if until.Valid {
+		pause.Until = until.Time
+	}
+	return &pause, nil
+}
+
+// DeletePause resumes an account
+func (s *SQLStore) DeletePause(ctx context.Context, did string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `DELETE FROM account_pauses WHERE did = ?`, did); err != nil {
+		s.logger.Error("Failed to resume account %s: %v", did, err)
+		return fmt.Errorf("failed to resume account: %w", err)
+	}
+
+	return nil
+}
+
+// dayLayout is how daily_stats days are stored, so they sort as text
+const dayLayout = "2006-01-02"
+
+// SaveDailyStats stores the snapshot for its day, replacing any earlier one
+func (s *SQLStore) SaveDailyStats(ctx context.Context, stats models.DailyStats) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if stats.RecordedAt.IsZero() {
+		stats.RecordedAt = time.Now()
+	}
+
+	_, err := s.exec(ctx, `
+		INSERT INTO daily_stats (day, followers, following, queue_depth, follows_performed, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (day) DO UPDATE SET
+			followers = excluded.followers,
+			following = excluded.following,
+			queue_depth = excluded.queue_depth,
+			follows_performed = excluded.follows_performed,
+			recorded_at = excluded.recorded_at
+	`, stats.Day.Format(dayLayout), stats.Followers, stats.Following, stats.QueueDepth, stats.FollowsPerformed, stats.RecordedAt)
+	if err != nil {
+		s.logger.Error("Failed to save daily stats for %s: %v", stats.Day.Format(dayLayout), err)
+		return fmt.Errorf("failed to save daily stats: %w", err)
+	}
+
+	return nil
+}
+
+// LoadDailyStats returns the snapshots for days on or after since, oldest first
+func (s *SQLStore) LoadDailyStats(ctx context.Context, since time.Time) ([]models.DailyStats, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `
+		SELECT day, followers, following, queue_depth, follows_performed, recorded_at
+		FROM daily_stats WHERE day >= ? ORDER BY day
+	`, since.Format(dayLayout))
+	if err != nil {
+		s.logger.Error("Failed to query daily stats: %v", err)
+		return nil, fmt.Errorf("failed to query daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.DailyStats
+	for rows.Next() {
+		var stats models.DailyStats
+		var day string
+		if err := rows.Scan(
+			&day,
+			&stats.Followers,
+			&stats.Following,
+			&stats.QueueDepth,
+			&stats.FollowsPerformed,
+			&stats.RecordedAt,
+		); err != nil {
+			s.logger.Error("Failed to scan daily stats row: %v", err)
+			return nil, fmt.Errorf("failed to scan daily stats row: %w", err)
+		}
+		if stats.Day, err = time.ParseInLocation(dayLayout, day, time.Local); err != nil {
+			return nil, fmt.Errorf("invalid daily stats day %q: %w", day, err)
+		}
+		snapshots = append(snapshots, stats)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// GetSetting returns a stored setting, or an empty string if it is not set
+func (s *SQLStore) GetSetting(ctx context.Context, key string) (string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var value string
+	err := s.queryRow(ctx, `SELECT value FROM settings WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to read setting %s: %v", key, err)
+		return "", fmt.Errorf("failed to read setting %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// SetSetting stores a setting, replacing any previous value
+func (s *SQLStore) SetSetting(ctx context.Context, key, value string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`, key, value); err != nil {
+		s.logger.Error("Failed to save setting %s: %v", key, err)
+		return fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Ping checks the database connection
+func (s *SQLStore) Ping(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to reach database: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connection
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Store is the storage backend used by the service and commands. Every call
+// takes a context and is additionally bounded by a query timeout, so a
+// stuck disk or lock surfaces as an error instead of a hang.
+type Store interface {
+	// LoadUsers loads all users
+	LoadUsers(ctx context.Context) ([]models.TargetUser, error)
+	// QueryUsers returns one page of the users matching a filter (all matches if limit is 0)
+	QueryUsers(ctx context.Context, filter UserFilter, limit, offset int) ([]models.TargetUser, error)
+	// CountUsers returns the number of users matching a filter
+	CountUsers(ctx context.Context, filter UserFilter) (int, error)
+	// SaveUser inserts or updates a user keyed by DID
+	SaveUser(ctx context.Context, user models.TargetUser) error
+	// SaveUsers saves a batch of users in a single transaction
+	SaveUsers(ctx context.Context, users []models.TargetUser) error
+	// PruneUsers removes never-followed, unprotected users that were saved and
+	// last checked before the cutoff, optionally archiving them first
+	PruneUsers(ctx context.Context, cutoff time.Time, archive bool) (int, error)
+	// DeleteUser removes a user by handle
+	DeleteUser(ctx context.Context, handle string) error
+
+	// RecordEvent appends an event to the history
+	RecordEvent(ctx context.Context, event models.FollowEvent) error
+	// LoadEvents returns the most recent events for a DID (all DIDs if empty), newest first
+	LoadEvents(ctx context.Context, did string, limit int) ([]models.FollowEvent, error)
+	// QueryEvents returns the most recent events matching a filter, newest
+	// first. A limit of 0 returns every match.
+	QueryEvents(ctx context.Context, filter EventFilter, limit int) ([]models.FollowEvent, error)
+	// LoadEventTimes returns when the events with an action and result since a
+	// time were recorded, oldest first
+	LoadEventTimes(ctx context.Context, action, result string, since time.Time) ([]time.Time, error)
+
+	// RecordFollowerCounts appends follower count observations of targets
+	RecordFollowerCounts(ctx context.Context, observations []models.FollowerObservation) error
+	// LoadFollowerHistory returns observations since a time for a DID (all DIDs
+	// if empty), ordered by DID and then oldest first
+	LoadFollowerHistory(ctx context.Context, did string, since time.Time) ([]models.FollowerObservation, error)
+
+	// SaveFollowers replaces the snapshot of accounts that follow me
+	SaveFollowers(ctx context.Context, followers []models.Actor) error
+	// LoadFollowers returns the latest followers snapshot
+	LoadFollowers(ctx context.Context) ([]models.Actor, error)
+
+	// SaveSession stores the session for its account, replacing any previous one
+	SaveSession(ctx context.Context, session *models.Session) error
+	// LoadSession returns the stored session for an account DID, or nil if none
+	LoadSession(ctx context.Context, did string) (*models.Session, error)
+	// DeleteSession removes the stored session for an account DID
+	DeleteSession(ctx context.Context, did string) error
+
+	// AddToBlocklist adds or updates a blocklist entry, refusing mutuals with ErrMutual
+	AddToBlocklist(ctx context.Context, entry models.BlocklistEntry) error
+	// RemoveFromBlocklist removes a blocklist entry by subject
+	RemoveFromBlocklist(ctx context.Context, subject string) error
+	// LoadBlocklist returns all blocklist entries, most recent first
+	LoadBlocklist(ctx context.Context) ([]models.BlocklistEntry, error)
+	// IsBlocked reports whether a DID or handle is on the blocklist
+	IsBlocked(ctx context.Context, did, handle string) (bool, error)
+
+	// IsMutual reports whether a DID or handle is a followed user who follows me back
+	IsMutual(ctx context.Context, did, handle string) (bool, error)
+
+	// AddProtected adds or updates an account that cleanup must never touch
+	AddProtected(ctx context.Context, entry models.ProtectedAccount) error
+	// RemoveProtected removes a protected account by subject
+	RemoveProtected(ctx context.Context, subject string) error
+	// LoadProtected returns all protected accounts, most recent first
+	LoadProtected(ctx context.Context) ([]models.ProtectedAccount, error)
+	// IsProtected reports whether a DID or handle is protected
+	IsProtected(ctx context.Context, did, handle string) (bool, error)
+
+	// CreateUnfollowCampaign stores a new campaign with all of its targets pending
+	CreateUnfollowCampaign(ctx context.Context, name string, targets []models.UnfollowTarget) (*models.UnfollowCampaign, error)
+	// LoadUnfollowCampaigns returns all campaigns, oldest first
+	LoadUnfollowCampaigns(ctx context.Context) ([]models.UnfollowCampaign, error)
+	// CompleteUnfollowCampaign marks a campaign as finished
+	CompleteUnfollowCampaign(ctx context.Context, id int64) error
+	// LoadUnfollowTargets returns a campaign's targets with the given status (all if empty)
+	LoadUnfollowTargets(ctx context.Context, campaignID int64, status string) ([]models.UnfollowTarget, error)
+	// UpdateUnfollowTarget stores a target's status, attempts and last error
+	UpdateUnfollowTarget(ctx context.Context, target models.UnfollowTarget) error
+
+	// CreateCampaign stores a new follow campaign, filling in its ID and creation time
+	CreateCampaign(ctx context.Context, campaign *models.Campaign) error
+	// LoadCampaigns returns all follow campaigns, oldest first
+	LoadCampaigns(ctx context.Context) ([]models.Campaign, error)
+	// FinishCampaign records how a follow campaign finished
+	FinishCampaign(ctx context.Context, id int64, outcome string) error
+
+	// SavePause pauses an account, replacing any previous pause
+	SavePause(ctx context.Context, pause models.AccountPause) error
+	// LoadPause returns the pause for an account DID, or nil if it is not paused
+	LoadPause(ctx context.Context, did string) (*models.AccountPause, error)
+	// DeletePause resumes an account
+	DeletePause(ctx context.Context, did string) error
+
+	// SaveDailyStats stores the snapshot for its day, replacing any earlier one
+	SaveDailyStats(ctx context.Context, stats models.DailyStats) error
+	// LoadDailyStats returns the snapshots for days on or after since, oldest first
+	LoadDailyStats(ctx context.Context, since time.Time) ([]models.DailyStats, error)
+
+	// GetSetting returns a stored setting, or an empty string if it is not set
+	GetSetting(ctx context.Context, key string) (string, error)
+	// SetSetting stores a setting, replacing any previous value
+	SetSetting(ctx context.Context, key, value string) error
+
+	// Maintain refreshes planner statistics and reclaims free space if worthwhile
+	Maintain(ctx context.Context) (*MaintenanceReport, error)
+
+	// Ping checks that the backend can still be reached
+	Ping(ctx context.Context) error
+
+	// Close releases the backend's resources
+	Close() error
+}
+
+// Logger interface for logging
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+}
+
+// NewStore opens the configured backend: an in-memory store in ephemeral
+// mode, PostgreSQL when a database URL is set, otherwise the SQLite file at
+// the configured path
+func NewStore(config *models.Config, logger Logger) (Store, error) {
+	var (
+		store *SQLStore
+		err   error
+	)
+	namespace := Namespace(config)
+	if config.Ephemeral {
+		logger.Info("Using ephemeral in-memory storage, nothing will be saved")
+		store, err = NewMemoryStore(logger)
+	} else if config.DatabaseURL != "" {
+		if namespace != "" {
+			logger.Info("Using PostgreSQL storage in schema %s%s", namespacePrefix, namespace)
+		} else {
+			logger.Info("Using PostgreSQL storage")
+		}
+		store, err = NewPostgresStore(config.DatabaseURL, namespace, logger)
+	} else {
+		dbPath := namespacedPath(config.DBPath, namespace)
+		logger.Info("Using SQLite storage at %s", dbPath)
+		store, err = NewSQLiteStore(dbPath, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// CreateUnfollowCampaign stores a new campaign with all of its targets pending
+func (s *SQLStore) CreateUnfollowCampaign(ctx context.Context, name string, targets []models.UnfollowTarget) (*models.UnfollowCampaign, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	campaign := &models.UnfollowCampaign{Name: name, CreatedAt: time.Now()}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction: %v", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, s.rebind(`
+		INSERT INTO unfollow_campaigns (name, created_at) VALUES (?, ?) RETURNING id
+	`), campaign.Name, campaign.CreatedAt).Scan(&campaign.ID); err != nil {
+		s.logger.Error("Failed to create unfollow campaign %s: %v", name, err)
+		return nil, fmt.Errorf("failed to create unfollow campaign: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(`
+		INSERT INTO unfollow_targets (campaign_id, did, handle, position, status, attempts, error, updated_at)
+		VALUES (?, ?, ?, ?, ?, 0, '', ?)
+		ON CONFLICT (campaign_id, did) DO NOTHING
+	`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare unfollow target insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, target := range targets {
+		if _, err := stmt.ExecContext(ctx, campaign.ID, target.DID, target.Handle, i, models.UnfollowPending, campaign.CreatedAt); err != nil {
+			s.logger.Error("Failed to add unfollow target %s: %v", target.Handle, err)
+			return nil, fmt.Errorf("failed to add unfollow target: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit unfollow campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// LoadUnfollowCampaigns returns all campaigns, oldest first
+func (s *SQLStore) LoadUnfollowCampaigns(ctx context.Context) ([]models.UnfollowCampaign, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.query(ctx, `SELECT id, name, created_at, completed_at FROM unfollow_campaigns ORDER BY id`)
+	if err != nil {
+		s.logger.Error("Failed to query unfollow campaigns: %v", err)
+		return nil, fmt.Errorf("failed to query unfollow campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []models.UnfollowCampaign
+	for rows.Next() {
+		var campaign models.UnfollowCampaign
+		var completedAt sql.NullTime
+		if err := rows.Scan(&campaign.ID, &campaign.Name, &campaign.CreatedAt, &completedAt); err != nil {
+			s.logger.Error("Failed to scan unfollow campaign row: %v", err)
+			return nil, fmt.Errorf("failed to scan unfollow campaign row: %w", err)
+		}
+		if completedAt.Valid {
+			campaign.CompletedAt = completedAt.Time
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// CompleteUnfollowCampaign marks a campaign as finished
+func (s *SQLStore) CompleteUnfollowCampaign(ctx context.Context, id int64) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.exec(ctx, `UPDATE unfollow_campaigns SET completed_at = ? WHERE id = ?`, time.Now(), id); err != nil {
+		s.logger.Error("Failed to complete unfollow campaign %d: %v", id, err)
+		return fmt.Errorf("failed to complete unfollow campaign: %w", err)
+	}
+
+	return nil
+}
+
+// LoadUnfollowTargets returns a campaign's targets with the given status
+// (all targets if empty), in the order they were added
+func (s *SQLStore) LoadUnfollowTargets(ctx context.Context, campaignID int64, status string) ([]models.UnfollowTarget, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT campaign_id, did, handle, status, attempts, verifications, error, updated_at FROM unfollow_targets WHERE campaign_id = ?`
+	args := []interface{}{campaignID}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY position`
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("Failed to query unfollow targets: %v", err)
+		return nil, fmt.Errorf("failed to query unfollow targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []models.UnfollowTarget
+	for rows.Next() {
+		var target models.UnfollowTarget
+		if err := rows.Scan(
+			&target.CampaignID,
+			&target.DID,
+			&target.Handle,
+			&target.Status,
+			&target.Attempts,
+			&target.Verifications,
+			&target.Error,
+			&target.UpdatedAt,
+		); err != nil {
+			s.logger.Error("Failed to scan unfollow target row: %v", err)
+			return nil, fmt.Errorf("failed to scan unfollow target row: %w", err)
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, rows.Err()
+}
+
+// UpdateUnfollowTarget stores a target's status, attempts, failed
+// verifications and last error
+func (s *SQLStore) UpdateUnfollowTarget(ctx context.Context, target models.UnfollowTarget) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if target.UpdatedAt.IsZero() {
+		target.UpdatedAt = time.Now()
+	}
+
+	_, err := s.exec(ctx, `
+		UPDATE unfollow_targets SET status = ?, attempts = ?, verifications = ?, error = ?, updated_at = ?
+		WHERE campaign_id = ? AND did = ?
+	`, target.Status, target.Attempts, target.Verifications, target.Error, target.UpdatedAt, target.CampaignID, target.DID)
+	if err != nil {
+		s.logger.Error("Failed to update unfollow target %s: %v", target.Handle, err)
+		return fmt.Errorf("failed to update unfollow target: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package digest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"bsky_follower/internal/stats"
+)
+
+// DefaultTemplate is used when no template is configured
+const DefaultTemplate = "growth"
+
+// builtins are the digest templates shipped with the application
+var builtins = map[string]string{
+	"growth": `Bluesky Follower digest ({{date .From}} – {{date .To}})
+
+Followed {{.FollowsPerformed}} new accounts{{if .UnfollowsPerformed}} and unfollowed {{.UnfollowsPerformed}}{{end}}.
+{{.PendingUsers}} candidates are still waiting in the pipeline.
+`,
+	"conversion": `Follow campaign report: {{date .From}} – {{date .To}}
+
+Attempts:        {{.FollowAttempts}}
+Successful:      {{.FollowsPerformed}} ({{percent .SuccessRate}})
+Failed:          {{.FollowsFailed}}
+Unfollows:       {{.UnfollowsPerformed}}
+
+Pipeline:        {{.PendingUsers}} pending / {{.FollowedUsers}} followed / {{.TotalUsers}} total
+{{- if .RecentFollows}}
+
+Latest follows:
+{{- range limit .RecentFollows 10}}
+  - {{.}}
+{{- end}}
+{{- end}}
+`,
+}
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Local().Format("Jan 2, 2006")
+	},
+	"percent": func(f float64) string {
+		return fmt.Sprintf("%.1f%%", f*100)
+	},
+	"limit": func(items []string, n int) []string {
+		if len(items) > n {
+			return items[:n]
+		}
+		return items
+	},
+	"join": strings.Join,
+}
+
+// Builtins returns the names of the built-in templates
+func Builtins() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load parses a digest template. name may be a built-in template name or a
+// path to a Go text/template file; an empty name selects the default.
+func Load(name string) (*template.Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+
+	text, ok := builtins[name]
+	if !ok {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown digest template %q (built-ins: %s): %w",
+				name, strings.Join(Builtins(), ", "), err)
+		}
+		text = string(data)
+	}
+
+	tmpl, err := template.New("digest").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// Render writes the digest for a summary using the given template
+func Render(w io.Writer, tmpl *template.Template, summary *stats.Summary) error {
+	if err := tmpl.Execute(w, summary); err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package dryrun collects what a dry run would have done: the follows and
+// unfollows it would have made, in order and with why each account ranked
+// where it did, and the candidates the filters kept out
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bsky_follower/internal/score"
+)
+
+// Report formats
+const (
+	FormatJSON  = "json"
+	FormatTable = "table"
+)
+
+// Formats lists the report formats
+var Formats = []string{FormatJSON, FormatTable}
+
+// Filters that keep candidates out of a run
+const (
+	FilterTargeting = "targeting" // the BSKY_TARGET_* rules
+	FilterBlocklist = "blocklist" // the local blocklist
+	FilterBlock     = "block"     // a block on the server, either way
+	FilterCooldown  = "cooldown"  // unfollowed too recently to follow again
+	FilterReview    = "review"    // rejected by a reviewer
+)
+
+// Action is a follow or unfollow the run would have made
+type Action struct {
+	Order    int             `json:"order"` // position in the run, from 1
+	Action   string          `json:"action"`
+	DID      string          `json:"did"`
+	Handle   string          `json:"handle"`
+	Source   string          `json:"source"`
+	Strategy string          `json:"strategy"`
+	Priority int             `json:"priority"` // queue priority, for follows
+	Score    score.Breakdown `json:"score"`    // what the stored signals add to the priority, for follows
+	At       time.Time       `json:"at"`
+}
+
+// Rejection is a candidate a filter kept out of the run
+type Rejection struct {
+	DID    string    `json:"did"`
+	Handle string    `json:"handle"`
+	Source string    `json:"source"`
+	Filter string    `json:"filter"` // one of the Filter constants
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+// Report is what a dry run would have done. The zero value is an empty
+// report; it is not safe for concurrent use.
+type Report struct {
+	Actions  []Action    `json:"actions"`
+	Rejected []Rejection `json:"rejected"`
+
+	seen map[string]bool // action or "rejected" plus DID of each entry
+}
+
+// add reports whether key is new to the report, remembering it
+func (r *Report) add(key string) bool {
+	if r.seen == nil {
+		r.seen = make(map[string]bool)
+	}
+	if r.seen[key] {
+		return false
+	}
+	r.seen[key] = true
+	return true
+}
+
+// Act adds an action, numbered in the order added. Each account and action
+// is reported at most once.
+func (r *Report) Act(action Action) {
+	if !r.add(action.Action + ":" + action.DID) {
+		return
+	}
+	action.Order = len(r.Actions) + 1
+	r.Actions = append(r.Actions, action)
+}
+
+// Reject adds a rejected candidate. Each account is reported at most once,
+// with the first filter that kept it out.
+func (r *Report) Reject(rejection Rejection) {
+	if !r.add("rejected:" + rejection.DID) {
+		return
+	}
+	r.Rejected = append(r.Rejected, rejection)
+}
+
+// Copy returns a copy of the report that shares nothing with it
+func (r *Report) Copy() Report {
+	return Report{
+		Actions:  append([]Action{}, r.Actions...),
+		Rejected: append([]Rejection{}, r.Rejected...),
+	}
+}
+
+// Render writes a report in one of Formats
+func Render(w io.Writer, r Report, format string) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, r)
+	case FormatTable:
+		return Table(w, r)
+	}
+	return fmt.Errorf("unknown report format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// JSON writes a report as indented JSON
+func JSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// Table writes a report as aligned plain-text tables
+func Table(w io.Writer, r Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Dry run: %d actions would have been taken\n", len(r.Actions))
+	if len(r.Actions) > 0 {
+		fmt.Fprintln(tw, "#\tWHEN\tACTION\tHANDLE\tSOURCE\tPRIORITY\tSCORE")
+		for _, a := range r.Actions {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				a.Order, a.At.Local().Format("2006-01-02 15:04"), a.Action, a.Handle,
+				orDash(a.Source), a.Priority, orDash(a.Score.String()))
+		}
+	}
+
+	fmt.Fprintf(tw, "\n%d candidates were rejected\n", len(r.Rejected))
+	if len(r.Rejected) > 0 {
+		fmt.Fprintln(tw, "HANDLE\tSOURCE\tFILTER\tREASON")
+		for _, rej := range r.Rejected {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", rej.Handle, orDash(rej.Source), rej.Filter, rej.Reason)
+		}
+	}
+	return tw.Flush()
+}
+
+// orDash returns s, or "-" in place of an empty cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
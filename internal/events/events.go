@@ -0,0 +1,103 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TypeAction    = "action"     // a follow, unfollow or other attempt was recorded
+	TypeQueue     = "queue"      // the follow queue changed, including retries
+	TypeRateLimit = "rate-limit" // the service is waiting for a limit to reset
+	TypeStats     = "stats"      // a daily stats snapshot was recorded
+	TypeAlert     = "alert"      // a background job failed
+)
+
+// buffer is how many events a slow subscriber may fall behind by before
+// further events are dropped for it
+const buffer = 256
+
+// Event is something that happened in the service. Data is encoded as JSON
+// for subscribers outside the process.
+type Event struct {
+	ID   uint64      `json:"id"`
+	Type string      `json:"type"`
+	At   time.Time   `json:"at"`
+	Data interface{} `json:"data"`
+}
+
+// Bus fans events out to subscribers
+type Bus struct {
+	mu          sync.Mutex
+	next        uint64
+	subscribers []chan Event
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe returns a channel receiving every event from now on and a
+// function that ends the subscription and closes the channel. Events are
+// never waited on: a subscriber that falls behind misses events rather than
+// stalling the service.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, ch)
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subscribers {
+			if sub == ch {
+				b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}
+}
+
+// Publish sends an event to every subscriber. IDs increase by one per event,
+// so a subscriber can tell when it missed some.
+func (b *Bus) Publish(eventType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		return
+	}
+	b.next++
+	event := Event{ID: b.next, Type: eventType, At: time.Now(), Data: data}
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// RateLimit is the data of a rate-limit event
+type RateLimit struct {
+	Limit string    `json:"limit"` // which limit was reached
+	Until time.Time `json:"until"` // when the service tries again
+}
+
+// QueueChange is the data of a queue event
+type QueueChange struct {
+	Kind     string `json:"kind"` // one of the queue event kinds
+	DID      string `json:"did"`
+	Handle   string `json:"handle"`
+	Priority int    `json:"priority"`
+	Attempts int    `json:"attempts"`
+}
+
+// Alert is the data of an alert event
+type Alert struct {
+	Message string `json:"message"`
+}
@@ -12,15 +12,18 @@ import (
 type APILogger struct{}
 
 func (l *APILogger) Info(msg string, args ...interface{}) {
+	record(LevelInfo, msg, args...)
 	log.Printf("[INFO] "+msg, args...)
 }
 
 func (l *APILogger) Error(msg string, args ...interface{}) {
+	record(LevelError, msg, args...)
 	log.Printf("[ERROR] "+msg, args...)
 }
 
 func (l *APILogger) Debug(msg string, args ...interface{}) {
 	if os.Getenv("DEBUG_MODE") == "true" {
+		record(LevelDebug, msg, args...)
 		log.Printf("[DEBUG] "+msg, args...)
 	}
 }
@@ -30,9 +33,12 @@ func GetAPILogger() *APILogger {
 	return &APILogger{}
 }
 
+// logFile is the rotating log file set up by InitLogger, nil until then
+var logFile *lumberjack.Logger
+
 func InitLogger() {
 	// Configure the logger to write to both file and stdout
-	logFile := &lumberjack.Logger{
+	logFile = &lumberjack.Logger{
 		Filename:   "logs/bsky_follower.log",
 		MaxSize:    100, // megabytes
 		MaxBackups: 3,
@@ -56,4 +62,15 @@ func InitLogger() {
 	// Configure the standard logger
 	log.SetOutput(io.MultiWriter(writers...))
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-} 
\ No newline at end of file
+} 
+
+// Close sends further log output to stdout only and closes the log file
+func Close() error {
+	if logFile == nil {
+		return nil
+	}
+	log.SetOutput(os.Stdout)
+	err := logFile.Close()
+	logFile = nil
+	return err
+}
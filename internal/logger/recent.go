@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Log levels, from the most to the least verbose
+const (
+	LevelDebug = "DEBUG"
+	LevelInfo  = "INFO"
+	LevelError = "ERROR"
+)
+
+// Levels lists the log levels from the most to the least verbose
+var Levels = []string{LevelDebug, LevelInfo, LevelError}
+
+// recentSize is how many of the latest log lines are kept in memory
+const recentSize = 1000
+
+// Entry is one logged line
+type Entry struct {
+	Seq     uint64 // goes up by one per line, so a gap means lines were dropped
+	Time    time.Time
+	Level   string
+	Message string
+}
+
+// AtLeast reports whether the entry is at least as severe as a level
+func (e Entry) AtLeast(level string) bool {
+	return levelRank(e.Level) >= levelRank(level)
+}
+
+// levelRank orders the levels, unknown ones first
+func levelRank(level string) int {
+	for i, l := range Levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// recent is a ring buffer of the latest log lines, whatever the output
+var recent struct {
+	mu      sync.Mutex
+	entries [recentSize]Entry
+	next    uint64 // sequence number of the next line
+}
+
+// record keeps a log line in the ring buffer, overwriting the oldest
+func record(level, msg string, args ...interface{}) {
+	entry := Entry{Time: time.Now(), Level: level, Message: fmt.Sprintf(msg, args...)}
+
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	recent.next++
+	entry.Seq = recent.next
+	recent.entries[entry.Seq%recentSize] = entry
+}
+
+// Recent returns the kept log lines logged after the line with sequence
+// number after, oldest first. Pass 0 for every kept line.
+func Recent(after uint64) []Entry {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	first := max(after+1, recent.next+1-min(recent.next, recentSize))
+	if first > recent.next {
+		return nil
+	}
+	entries := make([]Entry, 0, recent.next+1-first)
+	for seq := first; seq <= recent.next; seq++ {
+		entries = append(entries, recent.entries[seq%recentSize])
+	}
+	return entries
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// leakWindow is how many consecutive growing samples flag a possible leak
+const leakWindow = 12
+
+// Logger interface for logging
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+	Debug(msg string, args ...interface{})
+}
+
+// Monitor periodically logs runtime metrics and the size of tracked
+// in-memory collections, warning when a collection keeps growing
+type Monitor struct {
+	interval time.Duration
+	logger   Logger
+	mu       sync.Mutex
+	gauges   map[string]func() int
+	last     map[string]int
+	growth   map[string]int
+}
+
+// NewMonitor creates a new runtime monitor
+func NewMonitor(interval time.Duration, logger Logger) *Monitor {
+	return &Monitor{
+		interval: interval,
+		logger:   logger,
+		gauges:   make(map[string]func() int),
+		last:     make(map[string]int),
+		growth:   make(map[string]int),
+	}
+}
+
+// Track registers a size gauge to sample and check for unbounded growth
+func (m *Monitor) Track(name string, gauge func() int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[name] = gauge
+}
+
+// Run samples metrics every interval. It never returns.
+func (m *Monitor) Run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.Sample()
+	}
+}
+
+// Sample logs one round of runtime and gauge metrics
+func (m *Monitor) Sample() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	m.logger.Info("Runtime: goroutines=%d heap_alloc=%dKB heap_objects=%d sys=%dKB gc_cycles=%d gc_pause_total=%s",
+		runtime.NumGoroutine(),
+		mem.HeapAlloc/1024,
+		mem.HeapObjects,
+		mem.Sys/1024,
+		mem.NumGC,
+		time.Duration(mem.PauseTotalNs),
+	)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, gauge := range m.gauges {
+		size := gauge()
+		if last, ok := m.last[name]; ok && size > last {
+			m.growth[name]++
+		} else {
+			m.growth[name] = 0
+		}
+		m.last[name] = size
+
+		m.logger.Info("Gauge %s=%d", name, size)
+		if m.growth[name] >= leakWindow {
+			m.logger.Error("Possible leak: %s has grown for %d consecutive samples (now %d)", name, m.growth[name], size)
+		}
+	}
+}
+
+// StartPprof serves the net/http/pprof handlers on addr in the background
+func StartPprof(addr string, logger Logger) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	go func() {
+		logger.Info("Serving pprof on http://%s/debug/pprof/", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			logger.Error("pprof server stopped: %v", err)
+		}
+	}()
+}
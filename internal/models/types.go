@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
 
 // Config holds application configuration
 type Config struct {
@@ -8,19 +14,218 @@ type Config struct {
 	Password         string
 	Timeout          time.Duration
 	FallbackHandles  []string
+	DBPath           string
+	DatabaseURL      string // PostgreSQL connection URL, overrides DBPath when set
+	AccountDID       string // expected account DID, empty to trust the first login
+	AccountNamespace bool   // keep each account's data in its own SQLite file or PostgreSQL schema
+	PprofAddr        string // address for pprof endpoints in daemon mode, empty to disable
+	ControlAddr      string // address for the daemon's control API, empty to disable
+	MetricsInterval  time.Duration
+	DigestTemplate   string             // built-in digest template name or path to a template file
+	FollowBack       bool               // allow queuing accounts that already follow me
+	RandomSelection  bool               // sample within the top priority band instead of strict order
+	SourcePriority   map[string]int     // priority offset per discovery source
+	ScoreWeights     map[string]float64 // weight per scoring signal, unlisted signals weigh 1
+	PruneAge         time.Duration      // daemon prunes never-followed users unchecked this long, 0 disables
+	PruneArchive     bool               // archive pruned users instead of deleting them
+	Ephemeral        bool               // keep all state in memory, writing nothing to disk
+	RetryBase        time.Duration      // delay before the first retry of a failed follow
+	RetryCap         time.Duration      // upper bound on the retry delay
+	MaxQueueSize     int                // follow queue size limit, 0 for unbounded
+	QueueWeights     map[string]int     // drain weight per source kind with its own queue lane
+	SourceBudgets    map[string]int     // percent of the hourly and daily follows each queue lane may spend
+	DecayAfter       time.Duration      // queued users older than this lose priority, 0 disables
+	DecayEvery       time.Duration      // one priority step is lost per this much further age
+	ExpireAge        time.Duration      // queued users older than this are dropped, 0 keeps them
+	DryRun           bool               // simulate follows and unfollows, reporting what would have been done
+	Keywords         []string           // keywords and hashtags the daemon searches posts for
+	FollowersOf      []string           // accounts whose followers the daemon discovers
+	SecondDegree     bool               // daemon discovers the accounts many of my followers follow
+	Strategies       []string           // discovery strategies the daemon runs by name or kind, empty for all
+	MinFollowers     int                // discovery skips accounts with fewer followers
+	MaxFollowers     int                // discovery skips accounts with more followers, 0 for no limit
+	MinPosts         int                // discovery skips accounts with fewer posts
+	UnfollowAfter    time.Duration      // followed users not following back this long after are unfollowed, 0 disables
+	FollowsPerHour   int                // most follows per hour, lowered automatically when the server pushes back, 0 for no limit
+	ReadsPerHour     int                // most profile lookups per hour across enrichment workers, lowered like FollowsPerHour
+	EnrichWorkers    int                // profile lookups made at once when importing targets
+	UnfollowsPerHour int                // unfollow limit per hour, 0 for no limit beyond the unfollow pace
+	UnfollowsPerDay  int                // unfollow cap per rolling day, counted from the unfollow history, 0 for no cap
+	UnfollowGap      time.Duration      // mean gap between two unfollows
+	FollowsPerDay    int                // follow cap per rolling day, counted from the follow history, 0 for no cap
+	FollowsPerWeek   int                // follow cap per rolling week, counted from the follow history, 0 for no cap
+	Pacing           string             // distribution of the gaps between follows
+	FollowGap        time.Duration      // mean gap between two follows
+	FollowJitter     float64            // spread of the gaps as a fraction of FollowGap
+	PauseChance      float64            // probability of a long pause after a follow
+	PauseMin         time.Duration      // shortest long pause
+	PauseMax         time.Duration      // longest long pause
+	ActiveHours      string             // local hours follows happen in, e.g. 09:00-22:00, empty for all day
+	ActiveDays       string             // days follows happen on, e.g. mon-fri, empty for every day
+	Targeting        TargetingRules     // filters every candidate must pass to be queued
+	MaxFollowRatio   float64            // most accounts I may follow per follower before follows pause, 0 disables
+	RatioUnfollow    bool               // queue unfollows while the follow ratio is exceeded
+	UnfollowMutuals  bool               // let unfollow campaigns and cleanup policies unfollow mutuals
+	RefollowCooldown time.Duration      // how long after either of us unfollowed the other a user may not be followed again, 0 disables
+	MaxErrorPercent  int                // percent of the last ErrorWindow follows that may fail before the account pauses, 0 disables
+	ErrorWindow      int                // number of recent follow attempts the error rate is measured over
+	FollowerDrop     int                // percent of my followers lost between two account checks that pauses the account, 0 disables
+	DiscordWebhook   string             // Discord webhook URL notifications are posted to
+	SlackWebhook     string             // Slack incoming webhook URL notifications are posted to
+	TelegramToken    string             // Telegram bot token notifications are sent with
+	TelegramChat     string             // Telegram chat ID notifications are sent to
+	ReportDir        string             // directory weekly growth reports are written to, empty to not write them
+
+	// Keys are the UI key bindings per action; an action listed here has
+	// its default keys replaced
+	Keys map[string][]string
+}
+
+// TargetingRules configure the filter chain candidates pass before they are
+// queued. Zero values leave a rule off.
+type TargetingRules struct {
+	MinFollowers  int
+	MaxFollowers  int
+	MaxRatio      float64       // most accounts followed per follower
+	MinAge        time.Duration // youngest account age
+	RequireAvatar bool
+	PostedWithin  time.Duration // longest time since the newest post, where known
+	BioInclude    []string      // the bio must mention at least one of these
+	BioExclude    []string      // the bio must mention none of these
+	Langs         []string      // at least one post seen must be in one of these, where known
+	ExcludeLabels []string      // the profile must carry none of these labels; "adult" stands for every adult-content label
 }
 
 // Session represents an authenticated Bluesky session
 type Session struct {
-	AccessJwt string    `json:"accessJwt"`
-	Did       string    `json:"did"`
-	Handle    string    `json:"handle"`
-	CreatedAt time.Time
+	AccessJwt  string    `json:"accessJwt"`
+	RefreshJwt string    `json:"refreshJwt"`
+	Did        string    `json:"did"`
+	Handle     string    `json:"handle"`
+	CreatedAt  time.Time `json:"-"`
+	ExpiresAt  time.Time `json:"-"` // access token expiry, zero if unknown
+
+	// mu guards the tokens and expiry once the session is shared, since a
+	// renewal replaces them while other jobs make requests
+	mu sync.RWMutex
+}
+
+// Bearer returns the Authorization header value for the access token
+func (s *Session) Bearer() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return "Bearer " + s.AccessJwt
+}
+
+// RefreshToken returns the refresh token
+func (s *Session) RefreshToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.RefreshJwt
+}
+
+// Expiry returns when the access token expires, zero if unknown
+func (s *Session) Expiry() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ExpiresAt
+}
+
+// Renew replaces the session's tokens and expiry with those of a renewed
+// session for the same account
+func (s *Session) Renew(renewed *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.AccessJwt = renewed.AccessJwt
+	s.RefreshJwt = renewed.RefreshJwt
+	s.ExpiresAt = renewed.ExpiresAt
 }
 
 // Profile represents a user's profile information
 type Profile struct {
-	FollowersCount int `json:"followersCount"`
+	Did            string        `json:"did"`
+	Handle         string        `json:"handle"`
+	FollowersCount int           `json:"followersCount"`
+	FollowsCount   int           `json:"followsCount"`
+	DisplayName    string        `json:"displayName"`
+	Description    string        `json:"description"`
+	Avatar         string        `json:"avatar"` // avatar image URL
+	PostsCount     int           `json:"postsCount"`
+	CreatedAt      time.Time     `json:"createdAt"` // account creation, zero if unknown
+	Labels         []Label       `json:"labels"`    // moderation and self labels on the account
+	Viewer         ProfileViewer `json:"viewer"`
+}
+
+// Label is a moderation label on an account, set by a labeler or by the
+// account itself
+type Label struct {
+	Src string `json:"src"` // DID of the labeler, or of the account for self labels
+	Val string `json:"val"` // label value, e.g. "porn"
+	Neg bool   `json:"neg"` // the label takes back an earlier one with the same value
+}
+
+// LabelValues returns the values of the labels in effect, leaving out those
+// taken back by a negating label
+func (p Profile) LabelValues() []string {
+	var values []string
+	for _, label := range p.Labels {
+		switch {
+		case label.Neg:
+			values = slices.DeleteFunc(values, func(v string) bool { return v == label.Val })
+		case !slices.Contains(values, label.Val):
+			values = append(values, label.Val)
+		}
+	}
+	return values
+}
+
+// ProfileViewer is the relationship between the session account and a profile
+type ProfileViewer struct {
+	Following      string         `json:"following"`      // URI of my follow record, empty if not following
+	FollowedBy     string         `json:"followedBy"`     // URI of their follow record, empty if not followed
+	BlockedBy      bool           `json:"blockedBy"`      // they block me
+	Blocking       string         `json:"blocking"`       // URI of my block record, empty if I don't block them
+	BlockingByList *ListReference `json:"blockingByList"` // moderation list of mine that blocks them, nil if none
+	KnownFollowers KnownFollowers `json:"knownFollowers"`
+}
+
+// ListReference names a list
+type ListReference struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+// KnownFollowers counts the accounts I follow that follow a profile
+type KnownFollowers struct {
+	Count int `json:"count"`
+}
+
+// Actor is an account reference as returned in graph listings
+type Actor struct {
+	Did         string `json:"did"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"displayName"`
+}
+
+// Post is a post as returned by post search
+type Post struct {
+	URI         string     `json:"uri"`
+	Author      Actor      `json:"author"`
+	Record      PostRecord `json:"record"`
+	LikeCount   int        `json:"likeCount"`
+	RepostCount int        `json:"repostCount"`
+	ReplyCount  int        `json:"replyCount"`
+	IndexedAt   time.Time  `json:"indexedAt"`
+}
+
+// PostRecord is the part of a post's record the app reads
+type PostRecord struct {
+	Langs []string `json:"langs"` // languages the author tagged the post with
+}
+
+// Engagement is the number of likes, reposts and replies a post received
+func (p Post) Engagement() int {
+	return p.LikeCount + p.RepostCount + p.ReplyCount
 }
 
 // FollowRecord represents a follow action
@@ -39,55 +244,386 @@ type TargetUser struct {
 	FollowDate  time.Time `json:"followDate"`
 	Priority    int       `json:"priority"`
 	Attempts    int       `json:"attempts"`
+	Source      string    `json:"source"`    // discovery source, e.g. "search:golang"
+	NotBefore   time.Time `json:"notBefore"` // scheduled follow time, zero to follow as soon as possible
+	FollowURI   string    `json:"followUri"` // my follow record, where known
+	Campaign    int64     `json:"campaign"`  // follow campaign that found the account, 0 for none
+
+	FollowedBack   bool      `json:"followedBack"`
+	FollowedBackAt time.Time `json:"followedBackAt"` // when the follow back was first seen
+	UnfollowedAt   time.Time `json:"unfollowedAt"`   // zero unless I unfollowed them
+	Mutual         bool      `json:"mutual"`         // we follow each other, as of the last reconciliation
+	UnfollowedMeAt time.Time `json:"unfollowedMeAt"` // when they last stopped following me back, zero if they never did
+
+	// Profile metadata as of LastChecked, for display and bio filters
+	DisplayName      string    `json:"displayName"`
+	Description      string    `json:"description"`
+	Avatar           string    `json:"avatar"`
+	PostsCount       int       `json:"postsCount"`
+	AccountCreatedAt time.Time `json:"accountCreatedAt"`
+	Following        int       `json:"following"`
+	LastPostAt       time.Time `json:"lastPostAt"` // newest post seen, zero if unknown
+	Langs            []string  `json:"langs"`      // languages of the posts seen
+	Labels           []string  `json:"labels"`     // labels on the profile when it was last fetched
+
+	// Rejection is why the targeting filters keep the user out of the queue,
+	// empty if it passed them
+	Rejection string `json:"rejection"`
+}
+
+// Rejections recorded when the server's block state rules a user out. Unlike
+// the targeting filters' reasons, they are kept when the filters change.
+const (
+	RejectBlockedBy = "blocks me"
+	RejectBlocking  = "blocked by me"
+)
+
+// IsBlockRejection reports whether a rejection comes from the server's block
+// state rather than the targeting filters
+func IsBlockRejection(reason string) bool {
+	return reason == RejectBlockedBy || reason == RejectBlocking
+}
+
+// RejectReview is recorded for a candidate a reviewer rejected, followed by
+// the reviewer's notes if any. Like the block rejections, it is kept when the
+// filters change.
+const RejectReview = "rejected in review"
+
+// IsReviewRejection reports whether a rejection was made in a review
+func IsReviewRejection(reason string) bool {
+	return strings.HasPrefix(reason, RejectReview)
+}
+
+// ApplyProfile copies a freshly fetched profile's handle, follower count and
+// metadata onto the user
+func (u *TargetUser) ApplyProfile(profile Profile) {
+	if profile.Handle != "" {
+		u.Handle = profile.Handle
+	}
+	u.Followers = profile.FollowersCount
+	u.DisplayName = profile.DisplayName
+	u.Description = profile.Description
+	u.Avatar = profile.Avatar
+	u.PostsCount = profile.PostsCount
+	u.AccountCreatedAt = profile.CreatedAt
+	u.Following = profile.FollowsCount
+	u.Labels = profile.LabelValues()
+}
+
+// ApplyPosts records the newest post time and the languages of posts seen
+// by the user, keeping what was already known
+func (u *TargetUser) ApplyPosts(posts []Post) {
+	for _, post := range posts {
+		if post.IndexedAt.After(u.LastPostAt) {
+			u.LastPostAt = post.IndexedAt
+		}
+		for _, lang := range post.Record.Langs {
+			if !slices.Contains(u.Langs, lang) {
+				u.Langs = append(u.Langs, lang)
+			}
+		}
+	}
+}
+
+// NotFollowingBack reports whether I followed the user at least the given
+// duration ago, still follow them, and they never followed back
+func (u TargetUser) NotFollowingBack(now time.Time, after time.Duration) bool {
+	return u.Followed && !u.FollowedBack && u.UnfollowedAt.IsZero() &&
+		!u.FollowDate.IsZero() && !u.FollowDate.After(now.Add(-after))
+}
+
+// CooldownUntil returns when the user may be followed again after I
+// unfollowed them or they stopped following me back, whichever was later;
+// zero if neither happened or cooldown is 0
+func (u TargetUser) CooldownUntil(cooldown time.Duration) time.Time {
+	last := u.UnfollowedAt
+	if u.UnfollowedMeAt.After(last) {
+		last = u.UnfollowedMeAt
+	}
+	if last.IsZero() || cooldown <= 0 {
+		return time.Time{}
+	}
+	return last.Add(cooldown)
+}
+
+// Discovery source kinds. Parameterized sources append ":" and the
+// parameter, e.g. "search:golang" or "followers-of:alice.bsky.social".
+const (
+	SourceTrending     = "trending"
+	SourceSuggestions  = "suggestions"
+	SourceSearch       = "search"
+	SourceList         = "list"
+	SourceFollowersOf  = "followers-of"
+	SourceSecondDegree = "second-degree" // followed by several of my followers
+	SourceImport       = "import"
+	SourceExisting     = "existing" // followed before the app was used, found by the backfill
+)
+
+// NewSource builds a parameterized discovery source such as "search:golang"
+func NewSource(kind, param string) string {
+	if param == "" {
+		return kind
+	}
+	return kind + ":" + param
+}
+
+// MergeUsers combines two records of the same account, keeping the handle and
+// follower count from the most recently checked row and the strongest
+// follow state from either
+func MergeUsers(a, b TargetUser) TargetUser {
+	merged := a
+	if b.LastChecked.After(a.LastChecked) {
+		merged = b
+	}
+
+	merged.Followed = a.Followed || b.Followed
+	merged.FollowDate = earliest(a.FollowDate, b.FollowDate)
+	merged.SavedOn = earliest(a.SavedOn, b.SavedOn)
+	merged.Priority = max(a.Priority, b.Priority)
+	merged.Attempts = max(a.Attempts, b.Attempts)
+	// The source that found the account first keeps the credit
+	merged.Source = a.Source
+	if a.Source == "" || (b.Source != "" && b.SavedOn.Before(a.SavedOn)) {
+		merged.Source = b.Source
+	}
+	merged.Campaign = a.Campaign
+	if a.Campaign == 0 {
+		merged.Campaign = b.Campaign
+	}
+	if b.NotBefore.After(a.NotBefore) {
+		merged.NotBefore = b.NotBefore
+	} else {
+		merged.NotBefore = a.NotBefore
+	}
+	merged.FollowedBack = a.FollowedBack || b.FollowedBack
+	merged.FollowedBackAt = earliest(a.FollowedBackAt, b.FollowedBackAt)
+	if b.UnfollowedAt.After(a.UnfollowedAt) {
+		merged.UnfollowedAt = b.UnfollowedAt
+	} else {
+		merged.UnfollowedAt = a.UnfollowedAt
+	}
+	if b.UnfollowedMeAt.After(a.UnfollowedMeAt) {
+		merged.UnfollowedMeAt = b.UnfollowedMeAt
+	} else {
+		merged.UnfollowedMeAt = a.UnfollowedMeAt
+	}
+	return merged
+}
+
+// earliest returns the earlier of two times, ignoring zero values
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// BlocklistEntry is an account that must never be queued or followed.
+// Subject is either a DID or a handle.
+type BlocklistEntry struct {
+	Subject string    `json:"subject"`
+	Reason  string    `json:"reason"`
+	AddedOn time.Time `json:"addedOn"`
+}
+
+// ProtectedAccount is an account that cleanup must never unfollow or prune.
+// It is stored like a blocklist entry.
+type ProtectedAccount = BlocklistEntry
+
+// Follow event actions
+const (
+	ActionFollow   = "follow"
+	ActionUnfollow = "unfollow"
+	ActionEvict    = "evict"  // dropped from a full follow queue
+	ActionExpire   = "expire" // dropped from the follow queue as stale
+
+	// ActionSkip and ActionBlock record a user deliberately not followed or
+	// unfollowed, and one added to the blocklist; their Error holds why
+	ActionSkip  = "skip"
+	ActionBlock = "block"
+
+	// ActionRateLimit records a wait on a limit, named by the event's
+	// Strategy; its Error holds when the wait ends
+	ActionRateLimit = "rate-limit"
+)
+
+// Follow event results
+const (
+	ResultSuccess   = "success"
+	ResultFailed    = "failed"
+	ResultSimulated = "dry-run" // would have been made, in dry-run mode
+)
+
+// FollowEvent records a single action taken for an account. Events are only
+// ever appended, so together they are the audit trail of what was done.
+type FollowEvent struct {
+	ID        int64     `json:"id"`
+	Actor     string    `json:"actor"` // DID of the account that acted, empty if unknown
+	DID       string    `json:"did"`
+	Handle    string    `json:"handle"`
+	Action    string    `json:"action"`
+	Strategy  string    `json:"strategy"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Pause sources
+const (
+	PauseManual    = "manual"
+	PauseAutomatic = "automatic" // set by a safety trigger
+)
+
+// AccountPause halts every write for an account until it is resumed or,
+// when Until is set, until that time passes
+type AccountPause struct {
+	DID      string    `json:"did"`
+	Reason   string    `json:"reason"`
+	Source   string    `json:"source"`
+	PausedAt time.Time `json:"pausedAt"`
+	Until    time.Time `json:"until"` // zero pauses until resumed
+}
+
+// Active reports whether the pause is still in effect at the given time
+func (p *AccountPause) Active(now time.Time) bool {
+	return p != nil && (p.Until.IsZero() || now.Before(p.Until))
+}
+
+// Unfollow target states
+const (
+	UnfollowPending  = "pending"
+	UnfollowDone     = "unfollowed" // record deleted, awaiting verification
+	UnfollowVerified = "verified"
+	UnfollowFailed   = "failed"
+	UnfollowSkipped  = "skipped"
+)
+
+// UnfollowCampaign is a named batch of accounts to unfollow, processed with
+// its own pacing until every target is verified, failed or skipped
+type UnfollowCampaign struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompletedAt time.Time `json:"completedAt"` // zero while the campaign is running
+}
+
+// Follow campaign states
+const (
+	CampaignScheduled = "scheduled" // waiting for its start date
+	CampaignActive    = "active"
+	CampaignCompleted = "completed" // target reached
+	CampaignEnded     = "ended"     // end date passed before the target was reached
+	CampaignStopped   = "stopped"   // stopped by hand
+)
+
+// Campaign is a follow run with a goal: follow Target accounts found by its
+// own strategies between StartsAt and EndsAt, e.g. 500 accounts from a
+// hashtag search over two weeks
+type Campaign struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Strategies    []string  `json:"strategies"` // strategy specs such as "search:#golang" or "followers-of:alice"
+	Target        int       `json:"target"`     // follows to make
+	StartsAt      time.Time `json:"startsAt"`
+	EndsAt        time.Time `json:"endsAt"`        // zero for no end date
+	FollowsPerDay int       `json:"followsPerDay"` // 0 spreads the rest of the target over the days left
+	CreatedAt     time.Time `json:"createdAt"`
+	FinishedAt    time.Time `json:"finishedAt"` // zero while the campaign is scheduled or active
+	Outcome       string    `json:"outcome"`    // completed, ended or stopped once finished
+}
+
+// Status returns the campaign's state at the given time
+func (c Campaign) Status(now time.Time) string {
+	switch {
+	case c.Outcome != "":
+		return c.Outcome
+	case now.Before(c.StartsAt):
+		return CampaignScheduled
+	case !c.EndsAt.IsZero() && !now.Before(c.EndsAt):
+		return CampaignEnded
+	}
+	return CampaignActive
+}
+
+// DailyLimit returns how many follows the campaign may make in a day once it
+// made followed of them: FollowsPerDay if set, otherwise the rest of the
+// target spread evenly over the days left, or 0 for no limit if the campaign
+// has no end date
+func (c Campaign) DailyLimit(followed int, now time.Time) int {
+	if c.FollowsPerDay > 0 {
+		return c.FollowsPerDay
+	}
+	if c.EndsAt.IsZero() {
+		return 0
+	}
+	from := now
+	if from.Before(c.StartsAt) {
+		from = c.StartsAt
+	}
+	days := max(int(math.Ceil(c.EndsAt.Sub(from).Hours()/24)), 1)
+	left := max(c.Target-followed, 1)
+	return (left + days - 1) / days
+}
+
+// UnfollowTarget is the progress of one account within an unfollow campaign
+type UnfollowTarget struct {
+	CampaignID    int64     `json:"campaignId"`
+	DID           string    `json:"did"`
+	Handle        string    `json:"handle"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	Verifications int       `json:"verifications,omitempty"` // failed lookups of the follow record after the unfollow
+	Error         string    `json:"error,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// FollowerObservation is a target's follower count at a point in time
+type FollowerObservation struct {
+	DID        string    `json:"did"`
+	Followers  int       `json:"followers"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// DailyStats is a once-a-day snapshot of account growth and bot activity.
+// Day is midnight local time of the day the snapshot covers.
+type DailyStats struct {
+	Day              time.Time `json:"day"`
+	Followers        int       `json:"followers"`
+	Following        int       `json:"following"`
+	QueueDepth       int       `json:"queueDepth"`
+	FollowsPerformed int       `json:"followsPerformed"`
+	RecordedAt       time.Time `json:"recordedAt"`
+}
+
+// HealthCheck is the outcome of one health check
+type HealthCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Health reports the daemon's dependencies. Live is false only when the
+// daemon is stuck and should be restarted; Ready is true when every check
+// passes.
+type Health struct {
+	Live     bool        `json:"live"`
+	Ready    bool        `json:"ready"`
+	Database HealthCheck `json:"database"`
+	Session  HealthCheck `json:"session"`
+	Queue    HealthCheck `json:"queue"`
 }
 
 // FollowQueueItem represents an item in the follow queue
 type FollowQueueItem struct {
-	User      TargetUser
-	Priority  int
-	Attempts  int
-	NextTry   time.Time
-	Index     int // for heap implementation
-}
-
-// FollowQueue implements heap.Interface for priority queue
-type FollowQueue []*FollowQueueItem
-
-// Len returns the length of the queue
-func (pq FollowQueue) Len() int { return len(pq) }
-
-// Less compares two items in the queue
-func (pq FollowQueue) Less(i, j int) bool {
-	// First compare by priority (higher priority first)
-	if pq[i].Priority != pq[j].Priority {
-		return pq[i].Priority > pq[j].Priority
-	}
-	// Then compare by next try time (earlier time first)
-	return pq[i].NextTry.Before(pq[j].NextTry)
-}
-
-// Swap swaps two items in the queue
-func (pq FollowQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].Index = i
-	pq[j].Index = j
-}
-
-// Push adds an item to the queue
-func (pq *FollowQueue) Push(x interface{}) {
-	n := len(*pq)
-	item := x.(*FollowQueueItem)
-	item.Index = n
-	*pq = append(*pq, item)
-}
-
-// Pop removes and returns the highest priority item
-func (pq *FollowQueue) Pop() interface{} {
-	old := *pq
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = nil  // avoid memory leak
-	item.Index = -1 // for safety
-	*pq = old[0 : n-1]
-	return item
-} 
\ No newline at end of file
+	User     TargetUser
+	Priority int
+	Attempts int
+	NextTry  time.Time
+	Decayed  int // priority steps lost to age
+}
+
+// SchedulePriority orders queue items, highest priority first
+func (item *FollowQueueItem) SchedulePriority() int { return item.Priority }
+
+// ScheduleNotBefore orders queue items of the same priority, earliest next
+// try first
+func (item *FollowQueueItem) ScheduleNotBefore() time.Time { return item.NextTry }
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Notifier sends a short plain text message where a person will see it
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, message string) error
+}
+
+// FromConfig returns a notifier for every integration the configuration
+// sets up, none if there are none
+func FromConfig(config *models.Config) []Notifier {
+	client := &http.Client{Timeout: config.Timeout}
+	var notifiers []Notifier
+	if config.DiscordWebhook != "" {
+		notifiers = append(notifiers, &Discord{WebhookURL: config.DiscordWebhook, client: client})
+	}
+	if config.SlackWebhook != "" {
+		notifiers = append(notifiers, &Slack{WebhookURL: config.SlackWebhook, client: client})
+	}
+	if config.TelegramToken != "" && config.TelegramChat != "" {
+		notifiers = append(notifiers, &Telegram{Token: config.TelegramToken, ChatID: config.TelegramChat, client: client})
+	}
+	return notifiers
+}
+
+// SendAll sends a message through every notifier, returning the errors of
+// those that failed
+func SendAll(ctx context.Context, notifiers []Notifier, message string) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Send(ctx, message); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Discord posts to a Discord webhook
+type Discord struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+func (d *Discord) Name() string { return "discord" }
+
+// discordLimit is the most characters a Discord message may have
+const discordLimit = 2000
+
+func (d *Discord) Send(ctx context.Context, message string) error {
+	return postJSON(ctx, d.client, d.WebhookURL, map[string]string{"content": truncate(message, discordLimit)})
+}
+
+// Slack posts to a Slack incoming webhook
+type Slack struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+func (s *Slack) Name() string { return "slack" }
+
+func (s *Slack) Send(ctx context.Context, message string) error {
+	return postJSON(ctx, s.client, s.WebhookURL, map[string]string{"text": message})
+}
+
+// Telegram sends through a Telegram bot to one chat
+type Telegram struct {
+	Token  string
+	ChatID string
+	client *http.Client
+}
+
+func (t *Telegram) Name() string { return "telegram" }
+
+// telegramLimit is the most characters a Telegram message may have
+const telegramLimit = 4096
+
+func (t *Telegram) Send(ctx context.Context, message string) error {
+	endpoint := "https://api.telegram.org/bot" + url.PathEscape(t.Token) + "/sendMessage"
+	return postJSON(ctx, t.client, endpoint, map[string]string{"chat_id": t.ChatID, "text": truncate(message, telegramLimit)})
+}
+
+// postJSON posts a JSON body and fails on any non-2xx response
+func postJSON(ctx context.Context, client *http.Client, endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// truncate shortens a message to at most limit characters
+func truncate(message string, limit int) string {
+	runes := []rune(message)
+	if len(runes) <= limit {
+		return message
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,275 @@
+// Package pqueue provides a generic scheduling priority queue: values that
+// are ready come out highest priority first and, within a priority, earliest
+// not-before time first, ahead of the values still waiting for their
+// not-before time, which come out earliest first.
+package pqueue
+
+import (
+	"container/heap"
+	"time"
+)
+
+// Schedulable is a value a Heap can order
+type Schedulable interface {
+	comparable
+	// SchedulePriority is the value's priority, higher first
+	SchedulePriority() int
+	// ScheduleNotBefore is the earliest time the value should be taken
+	ScheduleNotBefore() time.Time
+}
+
+// Heap is a priority queue of distinct values, kept as a heap of ready
+// values and a heap of values waiting for their not-before time, so a
+// high-priority value scheduled for later never sits ahead of ready ones.
+// Readiness is judged as of the time last passed to Advance. It is not safe
+// for concurrent use. A value whose priority or not-before time changes
+// while it is queued must be passed to Fix, or Init after changing many.
+type Heap[T Schedulable] struct {
+	ready   entries[T]
+	waiting entries[T]
+	now     time.Time
+}
+
+// New creates an empty heap
+func New[T Schedulable]() *Heap[T] {
+	return &Heap[T]{
+		ready:   entries[T]{index: make(map[T]int), less: byPriority[T]},
+		waiting: entries[T]{index: make(map[T]int), less: byNotBefore[T]},
+	}
+}
+
+// Len returns the number of queued values
+func (q *Heap[T]) Len() int {
+	return len(q.ready.items) + len(q.waiting.items)
+}
+
+// Now returns the time readiness is judged by, as last passed to Advance
+func (q *Heap[T]) Now() time.Time {
+	return q.now
+}
+
+// Advance moves the clock on to now, making the values whose not-before
+// time has come ready. A clock moved back is ignored.
+func (q *Heap[T]) Advance(now time.Time) {
+	if now.After(q.now) {
+		q.now = now
+	}
+	for len(q.waiting.items) > 0 && !q.waiting.items[0].ScheduleNotBefore().After(q.now) {
+		heap.Push(&q.ready, heap.Pop(&q.waiting))
+	}
+}
+
+// side returns the heap a value belongs in as of the clock
+func (q *Heap[T]) side(v T) *entries[T] {
+	if v.ScheduleNotBefore().After(q.now) {
+		return &q.waiting
+	}
+	return &q.ready
+}
+
+// Push adds a value. Pushing a value that is already queued only fixes its
+// position.
+func (q *Heap[T]) Push(v T) {
+	if q.Fix(v) {
+		return
+	}
+	heap.Push(q.side(v), v)
+}
+
+// first returns the heap the next value comes from, nil if both are empty
+func (q *Heap[T]) first() *entries[T] {
+	switch {
+	case len(q.ready.items) > 0:
+		return &q.ready
+	case len(q.waiting.items) > 0:
+		return &q.waiting
+	}
+	return nil
+}
+
+// Pop removes and returns the first value: the first ready value or, when
+// none is ready, the one due soonest. It reports false if the heap is empty.
+func (q *Heap[T]) Pop() (T, bool) {
+	e := q.first()
+	if e == nil {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(e).(T), true
+}
+
+// Peek returns the value Pop would return without removing it, reporting
+// false if the heap is empty
+func (q *Heap[T]) Peek() (T, bool) {
+	e := q.first()
+	if e == nil {
+		var zero T
+		return zero, false
+	}
+	return e.items[0], true
+}
+
+// Remove removes a value and reports whether it was queued
+func (q *Heap[T]) Remove(v T) bool {
+	for _, e := range []*entries[T]{&q.ready, &q.waiting} {
+		if i, ok := e.index[v]; ok {
+			heap.Remove(e, i)
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether a value is queued
+func (q *Heap[T]) Contains(v T) bool {
+	_, ready := q.ready.index[v]
+	_, waiting := q.waiting.index[v]
+	return ready || waiting
+}
+
+// Fix restores the order after a queued value's priority or not-before time
+// changed, reporting whether it was queued
+func (q *Heap[T]) Fix(v T) bool {
+	if !q.Remove(v) {
+		return false
+	}
+	heap.Push(q.side(v), v)
+	return true
+}
+
+// Init restores the order after the priorities or not-before times of many
+// queued values changed
+func (q *Heap[T]) Init() {
+	items := q.Items()
+	q.ready.reset(nil)
+	q.waiting.reset(nil)
+	for _, v := range items {
+		e := q.side(v)
+		e.index[v] = len(e.items)
+		e.items = append(e.items, v)
+	}
+	heap.Init(&q.ready)
+	heap.Init(&q.waiting)
+}
+
+// Retain keeps the values for which keep returns true and returns the
+// others, removed from the heap
+func (q *Heap[T]) Retain(keep func(T) bool) []T {
+	removed := append(q.ready.retain(keep), q.waiting.retain(keep)...)
+	if len(removed) == 0 {
+		return nil
+	}
+	return removed
+}
+
+// Items returns the queued values in no particular order
+func (q *Heap[T]) Items() []T {
+	items := make([]T, 0, q.Len())
+	items = append(items, q.ready.items...)
+	return append(items, q.waiting.items...)
+}
+
+// Sorted returns the queued values in the order Pop would return them,
+// leaving the heap untouched
+func (q *Heap[T]) Sorted() []T {
+	c := New[T]()
+	c.now = q.now
+	c.ready.reset(q.ready.items)
+	c.waiting.reset(q.waiting.items)
+
+	sorted := make([]T, 0, c.Len())
+	for c.Len() > 0 {
+		v, _ := c.Pop()
+		sorted = append(sorted, v)
+	}
+	return sorted
+}
+
+// byPriority orders ready values highest priority first, then earliest
+// not-before time
+func byPriority[T Schedulable](a, b T) bool {
+	if a.SchedulePriority() != b.SchedulePriority() {
+		return a.SchedulePriority() > b.SchedulePriority()
+	}
+	return a.ScheduleNotBefore().Before(b.ScheduleNotBefore())
+}
+
+// byNotBefore orders waiting values earliest not-before time first, then
+// highest priority
+func byNotBefore[T Schedulable](a, b T) bool {
+	if !a.ScheduleNotBefore().Equal(b.ScheduleNotBefore()) {
+		return a.ScheduleNotBefore().Before(b.ScheduleNotBefore())
+	}
+	return a.SchedulePriority() > b.SchedulePriority()
+}
+
+// entries implements heap.Interface, tracking each value's position so
+// values can be fixed and removed without a search
+type entries[T Schedulable] struct {
+	items []T
+	index map[T]int
+	less  func(a, b T) bool
+}
+
+// reset replaces the values with a copy of items, which must already be in
+// heap order
+func (e *entries[T]) reset(items []T) {
+	e.items = append([]T(nil), items...)
+	clear(e.index)
+	for i, v := range e.items {
+		e.index[v] = i
+	}
+}
+
+// retain keeps the values for which keep returns true and returns the others
+func (e *entries[T]) retain(keep func(T) bool) []T {
+	var removed []T
+	kept := e.items[:0]
+	for _, v := range e.items {
+		if keep(v) {
+			kept = append(kept, v)
+			continue
+		}
+		removed = append(removed, v)
+		delete(e.index, v)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	clear(e.items[len(kept):])
+	e.items = kept
+	for i, v := range kept {
+		e.index[v] = i
+	}
+	heap.Init(e)
+	return removed
+}
+
+func (e entries[T]) Len() int { return len(e.items) }
+
+func (e entries[T]) Less(i, j int) bool {
+	return e.less(e.items[i], e.items[j])
+}
+
+func (e entries[T]) Swap(i, j int) {
+	e.items[i], e.items[j] = e.items[j], e.items[i]
+	e.index[e.items[i]] = i
+	e.index[e.items[j]] = j
+}
+
+func (e *entries[T]) Push(x any) {
+	v := x.(T)
+	e.index[v] = len(e.items)
+	e.items = append(e.items, v)
+}
+
+func (e *entries[T]) Pop() any {
+	n := len(e.items)
+	v := e.items[n-1]
+	var zero T
+	e.items[n-1] = zero // avoid memory leak
+	e.items = e.items[:n-1]
+	delete(e.index, v)
+	return v
+}
@@ -0,0 +1,268 @@
+package pqueue
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// job is a test value; pointers are distinct even with equal fields
+type job struct {
+	name      string
+	priority  int
+	notBefore time.Time
+}
+
+func (j *job) SchedulePriority() int        { return j.priority }
+func (j *job) ScheduleNotBefore() time.Time { return j.notBefore }
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return epoch.Add(time.Duration(minutes) * time.Minute)
+}
+
+// names returns the names of jobs in order
+func names(jobs []*job) []string {
+	out := make([]string, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.name
+	}
+	return out
+}
+
+// drain pops every job in order
+func drain(q *Heap[*job]) []string {
+	var out []string
+	for {
+		j, ok := q.Pop()
+		if !ok {
+			return out
+		}
+		out = append(out, j.name)
+	}
+}
+
+// newHeap queues jobs with the clock past every test time, so all are ready
+func newHeap(jobs ...*job) *Heap[*job] {
+	q := New[*job]()
+	q.Advance(at(60))
+	for _, j := range jobs {
+		q.Push(j)
+	}
+	return q
+}
+
+func TestPopPriorityOrder(t *testing.T) {
+	q := newHeap(
+		&job{name: "low", priority: 1},
+		&job{name: "high", priority: 9},
+		&job{name: "mid", priority: 5},
+		&job{name: "negative", priority: -2},
+	)
+	want := []string{"high", "mid", "low", "negative"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+}
+
+func TestPopNotBeforeBreaksTies(t *testing.T) {
+	q := newHeap(
+		&job{name: "late", priority: 3, notBefore: at(30)},
+		&job{name: "early", priority: 3, notBefore: at(10)},
+		&job{name: "other", priority: 1, notBefore: at(0)},
+		&job{name: "middle", priority: 3, notBefore: at(20)},
+	)
+	want := []string{"early", "middle", "late", "other"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+}
+
+func TestReadyBeforeWaiting(t *testing.T) {
+	q := New[*job]()
+	q.Advance(at(0))
+	q.Push(&job{name: "tomorrow", priority: 3, notBefore: at(24 * 60)})
+	q.Push(&job{name: "soon", priority: 9, notBefore: at(10)})
+	q.Push(&job{name: "ready", priority: 1, notBefore: at(0)})
+
+	if got, _ := q.Peek(); got.name != "ready" {
+		t.Fatalf("Peek = %s, want the ready job ahead of higher priorities waiting", got.name)
+	}
+	want := []string{"ready", "soon", "tomorrow"}
+	if got := names(q.Sorted()); !slices.Equal(got, want) {
+		t.Errorf("Sorted = %v, want %v", got, want)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	q := New[*job]()
+	q.Advance(at(0))
+	q.Push(&job{name: "later", priority: 5, notBefore: at(20)})
+	q.Push(&job{name: "sooner", priority: 9, notBefore: at(10)})
+	q.Push(&job{name: "ready", priority: 1, notBefore: at(0)})
+
+	q.Advance(at(10))
+	q.Advance(at(5)) // the clock never moves back
+	if !q.Now().Equal(at(10)) {
+		t.Errorf("Now = %v, want %v", q.Now(), at(10))
+	}
+	want := []string{"sooner", "ready", "later"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+}
+
+func TestFixMovesBetweenReadyAndWaiting(t *testing.T) {
+	a := &job{name: "a", priority: 5, notBefore: at(0)}
+	b := &job{name: "b", priority: 1, notBefore: at(0)}
+	q := New[*job]()
+	q.Advance(at(0))
+	q.Push(a)
+	q.Push(b)
+
+	// a is put off, as a retry backoff does
+	a.notBefore = at(30)
+	q.Fix(a)
+	if got, _ := q.Peek(); got != b {
+		t.Fatalf("Peek = %s after putting a off, want b", got.name)
+	}
+
+	a.notBefore = at(0)
+	q.Init()
+	if got, _ := q.Peek(); got != a {
+		t.Errorf("Peek = %s after making a ready again, want a", got.name)
+	}
+	if q.Len() != 2 {
+		t.Errorf("Len = %d, want 2", q.Len())
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	q := New[*job]()
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop on an empty heap reported a value")
+	}
+	if _, ok := q.Peek(); ok {
+		t.Error("Peek on an empty heap reported a value")
+	}
+}
+
+func TestPushTwiceFixes(t *testing.T) {
+	a := &job{name: "a", priority: 1}
+	b := &job{name: "b", priority: 2}
+	q := newHeap(a, b)
+
+	a.priority = 3
+	q.Push(a)
+	if q.Len() != 2 {
+		t.Fatalf("Len = %d after pushing a queued value again, want 2", q.Len())
+	}
+	if got, _ := q.Peek(); got != a {
+		t.Errorf("Peek = %s, want a", got.name)
+	}
+}
+
+func TestFix(t *testing.T) {
+	a := &job{name: "a", priority: 5, notBefore: at(0)}
+	b := &job{name: "b", priority: 4, notBefore: at(0)}
+	c := &job{name: "c", priority: 3, notBefore: at(0)}
+	q := newHeap(a, b, c)
+
+	c.priority = 10
+	if !q.Fix(c) {
+		t.Fatal("Fix reported c not queued")
+	}
+	a.notBefore = at(5)
+	a.priority = 4
+	if !q.Fix(a) {
+		t.Fatal("Fix reported a not queued")
+	}
+
+	want := []string{"c", "b", "a"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+	if q.Fix(a) {
+		t.Error("Fix reported a popped value as queued")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	a := &job{name: "a", priority: 3}
+	b := &job{name: "b", priority: 2}
+	c := &job{name: "c", priority: 1}
+	q := newHeap(a, b, c)
+
+	if !q.Remove(b) {
+		t.Fatal("Remove reported b not queued")
+	}
+	if q.Contains(b) {
+		t.Error("Contains reported b queued after removing it")
+	}
+	if q.Remove(b) {
+		t.Error("Remove reported b queued twice")
+	}
+	if !q.Remove(a) {
+		t.Fatal("Remove reported a not queued")
+	}
+
+	want := []string{"c"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+}
+
+func TestRetain(t *testing.T) {
+	var jobs []*job
+	for i := 0; i < 8; i++ {
+		jobs = append(jobs, &job{name: string(rune('a' + i)), priority: i})
+	}
+	q := newHeap(jobs...)
+
+	removed := q.Retain(func(j *job) bool { return j.priority%2 == 0 })
+	slices.SortFunc(removed, func(a, b *job) int { return a.priority - b.priority })
+	if got, want := names(removed), []string{"b", "d", "f", "h"}; !slices.Equal(got, want) {
+		t.Errorf("removed = %v, want %v", got, want)
+	}
+	for _, j := range removed {
+		if q.Contains(j) {
+			t.Errorf("Contains reported removed %s queued", j.name)
+		}
+	}
+
+	// The kept values must still be indexed for Fix
+	jobs[0].priority = 100
+	if !q.Fix(jobs[0]) {
+		t.Fatal("Fix reported a kept value not queued")
+	}
+	want := []string{"a", "g", "e", "c"}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+
+	if removed := q.Retain(func(*job) bool { return false }); removed != nil {
+		t.Errorf("Retain on an empty heap removed %v", names(removed))
+	}
+}
+
+func TestSorted(t *testing.T) {
+	q := newHeap(
+		&job{name: "c", priority: 1, notBefore: at(0)},
+		&job{name: "a", priority: 2, notBefore: at(1)},
+		&job{name: "d", priority: 0, notBefore: at(0)},
+		&job{name: "b", priority: 1, notBefore: at(-1)},
+	)
+	want := []string{"a", "b", "c", "d"}
+	if got := names(q.Sorted()); !slices.Equal(got, want) {
+		t.Errorf("Sorted = %v, want %v", got, want)
+	}
+
+	// Sorted leaves the heap as it was
+	if q.Len() != len(want) {
+		t.Fatalf("Len = %d after Sorted, want %d", q.Len(), len(want))
+	}
+	if got := drain(q); !slices.Equal(got, want) {
+		t.Errorf("pop order after Sorted = %v, want %v", got, want)
+	}
+}
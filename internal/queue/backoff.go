@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes capped exponential retry delays with random jitter
+type Backoff struct {
+	Base time.Duration // delay before the first retry
+	Cap  time.Duration // upper bound on any delay
+}
+
+// Delay returns how long to wait before retry number attempts, starting at
+// 1. The delay doubles with each attempt up to Cap, and a random part of up
+// to half of it is dropped so items that failed together, e.g. during an
+// outage, don't all retry at the same moment. A nil rng uses the shared
+// source.
+func (b Backoff) Delay(attempts int, rng *rand.Rand) time.Duration {
+	delay := b.Base
+	for i := 1; i < attempts && delay < b.Cap; i++ {
+		delay *= 2
+	}
+	if b.Cap > 0 && delay > b.Cap {
+		delay = b.Cap
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	var jitter int64
+	if rng != nil {
+		jitter = rng.Int63n(half + 1)
+	} else {
+		jitter = rand.Int63n(half + 1)
+	}
+	return delay - time.Duration(jitter)
+}
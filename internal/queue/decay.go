@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Decay lowers the priority of items whose user was saved long ago, since
+// stale targeting data is worth less, and eventually expires them. Age is
+// measured from the user's SavedOn time.
+type Decay struct {
+	After  time.Duration // age before priority starts to drop, 0 disables decay
+	Every  time.Duration // one priority step is lost per this much age past After
+	Expire time.Duration // age at which items are dropped, 0 keeps them
+}
+
+// Expired reports whether a user saved at savedOn is too old to queue
+func (d Decay) Expired(savedOn, now time.Time) bool {
+	return d.Expire > 0 && !savedOn.IsZero() && now.Sub(savedOn) >= d.Expire
+}
+
+// steps returns how many priority steps a user saved at savedOn has lost
+func (d Decay) steps(savedOn, now time.Time) int {
+	if d.After <= 0 || d.Every <= 0 || savedOn.IsZero() {
+		return 0
+	}
+	past := now.Sub(savedOn) - d.After
+	if past < 0 {
+		return 0
+	}
+	return int(past/d.Every) + 1
+}
+
+// settle applies the decay policy to every queued item, lowering priorities
+// and removing expired items, which it returns. The caller must hold the lock.
+func (q *Queue) settle(now time.Time) []*models.FollowQueueItem {
+	var expired []*models.FollowQueueItem
+	for _, l := range q.lanes {
+		for _, item := range l.items.Retain(func(item *models.FollowQueueItem) bool {
+			return !q.decay.Expired(item.User.SavedOn, now)
+		}) {
+			expired = append(expired, item)
+			q.emit(EventExpired, item)
+		}
+
+		changed := false
+		for _, item := range l.items.Items() {
+			if steps := q.decay.steps(item.User.SavedOn, now); steps > item.Decayed {
+				item.Priority -= steps - item.Decayed
+				item.Decayed = steps
+				changed = true
+			}
+		}
+		if changed {
+			l.items.Init()
+		}
+	}
+	return expired
+}
+
+// notifyExpired reports expired items to the OnExpire callback. It must be
+// called without the lock held.
+func (q *Queue) notifyExpired(expired []*models.FollowQueueItem) {
+	if q.onExpire == nil {
+		return
+	}
+	for _, item := range expired {
+		q.onExpire(*item)
+	}
+}
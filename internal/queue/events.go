@@ -0,0 +1,68 @@
+package queue
+
+import (
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Queue event kinds
+const (
+	EventPushed       = "pushed"
+	EventPopped       = "popped"
+	EventRetried      = "retried"       // requeued after a failure
+	EventDeadLettered = "dead-lettered" // dropped after its last retry
+	EventEvicted      = "evicted"       // dropped from a full queue
+	EventExpired      = "expired"       // dropped by the decay policy
+	EventRemoved      = "removed"
+	EventUpdated      = "updated" // priority or next try changed in place
+)
+
+// eventBuffer is how many events a slow subscriber may fall behind by
+// before further events are dropped for it
+const eventBuffer = 64
+
+// Event is a change to the queue
+type Event struct {
+	Kind string
+	Item models.FollowQueueItem
+	At   time.Time
+}
+
+// Subscribe returns a channel receiving every queue event from now on and a
+// function that ends the subscription and closes the channel. Events are
+// never waited on: a subscriber that falls behind misses events rather than
+// stalling the queue.
+func (q *Queue) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+
+	q.mu.Lock()
+	q.subscribers = append(q.subscribers, ch)
+	q.mu.Unlock()
+
+	return ch, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for i, sub := range q.subscribers {
+			if sub == ch {
+				q.subscribers = append(q.subscribers[:i], q.subscribers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}
+}
+
+// emit sends an event to every subscriber. The caller must hold the lock.
+func (q *Queue) emit(kind string, item *models.FollowQueueItem) {
+	if len(q.subscribers) == 0 {
+		return
+	}
+	event := Event{Kind: kind, Item: *item, At: time.Now()}
+	for _, ch := range q.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
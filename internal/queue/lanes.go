@@ -0,0 +1,172 @@
+package queue
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/pqueue"
+)
+
+// DefaultLane is the named queue for users whose source has no queue of its own
+const DefaultLane = "default"
+
+// lane is one named queue. Lanes are drained in proportion to their weights
+// with smooth weighted round robin, so a large lane cannot starve a small one.
+type lane struct {
+	name    string
+	items   *pqueue.Heap[*models.FollowQueueItem]
+	weight  int
+	current int       // running weight for round robin
+	held    time.Time // nothing is popped from the lane until then
+}
+
+// newLane creates an empty lane
+func newLane(name string, weight int) *lane {
+	return &lane{name: name, items: pqueue.New[*models.FollowQueueItem](), weight: weight}
+}
+
+// head returns the lane's first item, its highest priority ready item or,
+// when none is ready, the item due soonest, or nil if it is empty
+func (l *lane) head() *models.FollowQueueItem {
+	item, _ := l.items.Peek()
+	return item
+}
+
+// newLanes creates a lane per weighted source kind plus the default lane,
+// sorted by name so draining is deterministic
+func newLanes(weights map[string]int) []*lane {
+	lanes := []*lane{newLane(DefaultLane, 1)}
+	for name, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		if name == DefaultLane {
+			lanes[0].weight = weight
+			continue
+		}
+		lanes = append(lanes, newLane(name, weight))
+	}
+	sort.Slice(lanes, func(i, j int) bool { return lanes[i].name < lanes[j].name })
+	return lanes
+}
+
+// Lane returns the name of the lane a user belongs in
+func (q *Queue) Lane(user models.TargetUser) string {
+	return q.laneFor(user).name
+}
+
+// Hold keeps the named lane from being drained until a time, leaving its
+// items queued; the other lanes share the turns meanwhile. Unknown lanes are
+// ignored.
+func (q *Queue) Hold(name string, until time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, l := range q.lanes {
+		if l.name == name && until.After(l.held) {
+			l.held = until
+		}
+	}
+}
+
+// nextTry returns when the lane's head may be popped, zero if it is empty
+func (l *lane) nextTry() time.Time {
+	head := l.head()
+	if head == nil {
+		return time.Time{}
+	}
+	if l.held.After(head.NextTry) {
+		return l.held
+	}
+	return head.NextTry
+}
+
+// advance makes the items whose next try has come by now ready in every
+// lane. The caller must hold the lock.
+func (q *Queue) advance(now time.Time) {
+	for _, l := range q.lanes {
+		l.items.Advance(now)
+	}
+}
+
+// laneFor returns the lane a user belongs in: the lane named after the kind
+// of its discovery source, or the default lane
+func (q *Queue) laneFor(user models.TargetUser) *lane {
+	kind, _, _ := strings.Cut(user.Source, ":")
+	for _, l := range q.lanes {
+		if l.name == kind {
+			return l
+		}
+	}
+	for _, l := range q.lanes {
+		if l.name == DefaultLane {
+			return l
+		}
+	}
+	return q.lanes[0]
+}
+
+// pickLane chooses the next lane to drain among those eligible, advancing
+// the running weights. It returns nil if no lane is eligible.
+func pickLane(lanes []*lane, eligible func(*lane) bool) *lane {
+	var chosen *lane
+	total := 0
+	for _, l := range lanes {
+		if !eligible(l) {
+			continue
+		}
+		l.current += l.weight
+		total += l.weight
+		if chosen == nil || l.current > chosen.current {
+			chosen = l
+		}
+	}
+	if chosen != nil {
+		chosen.current -= total
+	}
+	return chosen
+}
+
+// peekLane is pickLane without advancing the running weights
+func peekLane(lanes []*lane, eligible func(*lane) bool) *lane {
+	saved := make([]int, len(lanes))
+	for i, l := range lanes {
+		saved[i] = l.current
+	}
+	chosen := pickLane(lanes, eligible)
+	for i, l := range lanes {
+		l.current = saved[i]
+	}
+	return chosen
+}
+
+// nonEmpty is a lane filter for lanes with any item
+func nonEmpty(l *lane) bool {
+	return l.items.Len() > 0
+}
+
+// due returns a lane filter for lanes with an item ready by now that are not
+// held. The lanes must have been advanced to now.
+func due(now time.Time) func(*lane) bool {
+	return func(l *lane) bool {
+		return l.head() != nil && !l.nextTry().After(now)
+	}
+}
+
+// copyLanes deep copies lanes so they can be drained without touching the queue
+func copyLanes(lanes []*lane) []*lane {
+	copied := make([]*lane, len(lanes))
+	for i, l := range lanes {
+		c := newLane(l.name, l.weight)
+		c.current = l.current
+		c.held = l.held
+		c.items.Advance(l.items.Now())
+		for _, item := range l.items.Items() {
+			dup := *item
+			c.items.Push(&dup)
+		}
+		copied[i] = c
+	}
+	return copied
+}
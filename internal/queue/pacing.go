@@ -0,0 +1,68 @@
+package queue
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Gap distributions, as used in BSKY_PACING
+const (
+	PaceFixed       = "fixed"
+	PaceUniform     = "uniform"
+	PaceNormal      = "normal"
+	PaceExponential = "exponential"
+)
+
+// PaceDistributions lists every gap distribution
+var PaceDistributions = []string{PaceFixed, PaceUniform, PaceNormal, PaceExponential}
+
+// minPaceGap keeps a drawn gap from collapsing two follows into one burst
+const minPaceGap = 5 * time.Second
+
+// Pacer draws randomized gaps between follows, so they don't come in a
+// fixed rhythm that is easy to tell from a person's
+type Pacer struct {
+	Distribution string        // one of PaceDistributions, fixed if unknown
+	Gap          time.Duration // mean gap between two follows
+	Jitter       float64       // spread as a fraction of Gap: half-width for uniform, standard deviation for normal
+	PauseChance  float64       // probability that a long pause is added to a gap
+	PauseMin     time.Duration // shortest long pause
+	PauseMax     time.Duration // longest long pause
+}
+
+// Next returns how long to wait after a follow before the next one: a gap
+// drawn from the distribution, now and then with a long pause on top. A nil
+// rng uses the shared source.
+func (p Pacer) Next(rng *rand.Rand) time.Duration {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	mean := float64(p.Gap)
+	var gap float64
+	switch p.Distribution {
+	case PaceUniform:
+		gap = mean * (1 + p.Jitter*(2*rng.Float64()-1))
+	case PaceNormal:
+		gap = mean * (1 + p.Jitter*rng.NormFloat64())
+	case PaceExponential:
+		gap = mean * rng.ExpFloat64()
+	default:
+		gap = mean
+	}
+	next := max(time.Duration(gap), min(p.Gap, minPaceGap))
+
+	if p.PauseChance > 0 && rng.Float64() < p.PauseChance {
+		next += p.PauseMin
+		if spread := int64(p.PauseMax - p.PauseMin); spread > 0 {
+			next += time.Duration(rng.Int63n(spread + 1))
+		}
+	}
+	return next
+}
+
+// Expected returns the mean wait between two follows, long pauses included
+func (p Pacer) Expected() time.Duration {
+	pause := p.PauseMin + max(p.PauseMax-p.PauseMin, 0)/2
+	return p.Gap + time.Duration(p.PauseChance*float64(pause))
+}
@@ -1,61 +1,354 @@
 package queue
 
 import (
-	"container/heap"
+	"math"
+	"math/rand"
+	"sync"
 	"time"
 
 	"bsky_follower/internal/models"
 )
 
-// Queue represents a priority queue for follow operations
+// Queue represents a priority queue for follow operations, made of named
+// lanes per discovery source kind that are drained by weight. It is safe for
+// concurrent use; items returned by Peek, PeekN and Snapshot are copies.
 type Queue struct {
-	items models.FollowQueue
+	mu          sync.Mutex
+	lanes       []*lane
+	maxSize     int // 0 for unbounded
+	decay       Decay
+	onExpire    func(models.FollowQueueItem)
+	done        []time.Time // when items were processed, oldest first, for Stats
+	deadLetters int
+	subscribers []chan Event
+}
+
+// Options configure a queue
+type Options struct {
+	// MaxSize is the most items the queue holds, 0 for any number
+	MaxSize int
+	// Weights gives each listed source kind its own lane, drained in
+	// proportion to the weight; everything else shares the default lane,
+	// weighted by Weights["default"] or 1
+	Weights map[string]int
+	// Decay ages items, evaluated whenever an item is popped
+	Decay Decay
+	// OnExpire is called for each item the decay policy drops
+	OnExpire func(models.FollowQueueItem)
 }
 
 // NewQueue creates a new follow queue
-func NewQueue() *Queue {
-	pq := make(models.FollowQueue, 0)
-	heap.Init(&pq)
+func NewQueue(opts Options) *Queue {
 	return &Queue{
-		items: pq,
+		lanes:    newLanes(opts.Weights),
+		maxSize:  opts.MaxSize,
+		decay:    opts.Decay,
+		onExpire: opts.OnExpire,
 	}
 }
 
-// Push adds a new item to the queue
-func (q *Queue) Push(user models.TargetUser, priority int) {
+// Push adds a new item to the queue. It is ready at once unless the user
+// carries a later not-before time. When the queue is over its size limit the
+// lowest priority items are evicted, oldest saved first, and returned; that
+// may be the new item itself.
+func (q *Queue) Push(user models.TargetUser, priority int) []*models.FollowQueueItem {
 	item := &models.FollowQueueItem{
 		User:     user,
 		Priority: priority,
 		Attempts: user.Attempts,
 		NextTry:  time.Now(),
 	}
-	heap.Push(&q.items, item)
+	if user.NotBefore.After(item.NextTry) {
+		item.NextTry = user.NotBefore
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.laneFor(user).items.Push(item)
+	q.emit(EventPushed, item)
+	return q.evict()
+}
+
+// evict removes items while the queue is over its size limit. The caller
+// must hold the lock.
+func (q *Queue) evict() []*models.FollowQueueItem {
+	var evicted []*models.FollowQueueItem
+	for q.maxSize > 0 && q.len() > q.maxSize {
+		var victim *models.FollowQueueItem
+		var from *lane
+		for _, l := range q.lanes {
+			for _, item := range l.items.Items() {
+				if victim == nil || item.Priority < victim.Priority ||
+					(item.Priority == victim.Priority && item.User.SavedOn.Before(victim.User.SavedOn)) {
+					victim, from = item, l
+				}
+			}
+		}
+		from.items.Remove(victim)
+		evicted = append(evicted, victim)
+		q.emit(EventEvicted, victim)
+	}
+	return evicted
+}
+
+// Requeue puts a popped item back, keeping its attempts and next try time
+func (q *Queue) Requeue(item *models.FollowQueueItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.laneFor(item.User).items.Push(item)
+	q.emit(EventRetried, item)
 }
 
-// Pop removes and returns the highest priority item
+// Pop removes and returns the highest priority ready item of the next lane,
+// or its item due soonest when none is ready
 func (q *Queue) Pop() *models.FollowQueueItem {
-	if q.items.Len() == 0 {
+	q.mu.Lock()
+	now := time.Now()
+	q.advance(now)
+	expired := q.settle(now)
+	var item *models.FollowQueueItem
+	if l := pickLane(q.lanes, nonEmpty); l != nil {
+		item, _ = l.items.Pop()
+		q.emit(EventPopped, item)
+	}
+	q.mu.Unlock()
+
+	q.notifyExpired(expired)
+	return item
+}
+
+// PopReady removes and returns the highest priority item that is ready by
+// now from the next lane with one, or nil if no lane has a ready item. Items
+// scheduled for later wait apart, so they never hold up ready ones. Checking
+// and popping under one lock keeps a concurrent Pop from slipping in between.
+func (q *Queue) PopReady(now time.Time) *models.FollowQueueItem {
+	q.mu.Lock()
+	q.advance(now)
+	expired := q.settle(now)
+	var item *models.FollowQueueItem
+	if l := pickLane(q.lanes, due(now)); l != nil {
+		item, _ = l.items.Pop()
+		q.emit(EventPopped, item)
+	}
+	q.mu.Unlock()
+
+	q.notifyExpired(expired)
+	return item
+}
+
+// PopWeighted removes a random ready item from the highest priority band of
+// the next lane with one. Items are weighted by follower count on a log
+// scale, so larger accounts are favored without the order becoming
+// predictable. It returns nil if no lane has a ready item in its top band.
+func (q *Queue) PopWeighted(rng *rand.Rand, now time.Time) *models.FollowQueueItem {
+	q.mu.Lock()
+	q.advance(now)
+	expired := q.settle(now)
+	item := q.popWeighted(rng, now)
+	if item != nil {
+		q.emit(EventPopped, item)
+	}
+	q.mu.Unlock()
+
+	q.notifyExpired(expired)
+	return item
+}
+
+// popWeighted is PopWeighted without decay. The caller must hold the lock.
+func (q *Queue) popWeighted(rng *rand.Rand, now time.Time) *models.FollowQueueItem {
+	l := pickLane(q.lanes, func(l *lane) bool {
+		return !l.held.After(now) && len(topBand(l, now)) > 0
+	})
+	if l == nil {
+		return nil
+	}
+
+	candidates := topBand(l, now)
+	var total float64
+	for _, item := range candidates {
+		total += weight(item)
+	}
+
+	pick := rng.Float64() * total
+	chosen := candidates[len(candidates)-1]
+	for _, item := range candidates {
+		pick -= weight(item)
+		if pick < 0 {
+			chosen = item
+			break
+		}
+	}
+
+	l.items.Remove(chosen)
+	return chosen
+}
+
+// topBand returns the ready items sharing the highest priority of a lane
+func topBand(l *lane, now time.Time) []*models.FollowQueueItem {
+	head := l.head()
+	if head == nil {
 		return nil
 	}
-	return heap.Pop(&q.items).(*models.FollowQueueItem)
+	band := head.Priority
+	var ready []*models.FollowQueueItem
+	for _, item := range l.items.Items() {
+		if item.Priority == band && !item.NextTry.After(now) {
+			ready = append(ready, item)
+		}
+	}
+	return ready
 }
 
-// Update modifies the priority and next try time of an item
+// weight is the sampling weight of an item within its band
+func weight(item *models.FollowQueueItem) float64 {
+	return 1 + math.Log10(1+float64(max(item.User.Followers, 0)))
+}
+
+// Update modifies the priority and next try time of a queued item. Items
+// that are no longer queued are ignored.
 func (q *Queue) Update(item *models.FollowQueueItem, priority int, nextTry time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	l := q.laneFor(item.User)
+	if !l.items.Contains(item) {
+		return
+	}
 	item.Priority = priority
 	item.NextTry = nextTry
-	heap.Fix(&q.items, item.Index)
+	l.items.Fix(item)
+}
+
+// Reprioritize sets the priority of every queued user that matches to
+// priority in place, keeping the difference between each item's queue
+// priority and its user's priority (such as a source offset), and returns how
+// many items changed
+func (q *Queue) Reprioritize(match func(models.TargetUser) bool, priority int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	changed := 0
+	for _, l := range q.lanes {
+		before := changed
+		for _, item := range l.items.Items() {
+			if !match(item.User) || item.User.Priority == priority {
+				continue
+			}
+			item.Priority += priority - item.User.Priority
+			item.User.Priority = priority
+			changed++
+		}
+		if changed > before {
+			l.items.Init()
+		}
+	}
+	return changed
+}
+
+// Modify applies change to every queued item for a DID, restores the order
+// and reports whether any was found
+func (q *Queue) Modify(did string, change func(*models.FollowQueueItem)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	found := false
+	for _, l := range q.lanes {
+		for _, item := range l.items.Items() {
+			if item.User.DID != did {
+				continue
+			}
+			change(item)
+			l.items.Fix(item)
+			q.emit(EventUpdated, item)
+			found = true
+		}
+	}
+	return found
+}
+
+// Remove removes every queued item for a DID and reports whether any was found
+func (q *Queue) Remove(did string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	removed := false
+	for _, l := range q.lanes {
+		for _, item := range l.items.Retain(func(item *models.FollowQueueItem) bool { return item.User.DID != did }) {
+			q.emit(EventRemoved, item)
+			removed = true
+		}
+	}
+	return removed
 }
 
 // Len returns the number of items in the queue
 func (q *Queue) Len() int {
-	return q.items.Len()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.len()
 }
 
-// Peek returns the highest priority item without removing it
+// len counts the items in every lane. The caller must hold the lock.
+func (q *Queue) len() int {
+	n := 0
+	for _, l := range q.lanes {
+		n += l.items.Len()
+	}
+	return n
+}
+
+// Snapshot returns copies of all queued items in the order Pop would return
+// them, leaving the queue untouched
+func (q *Queue) Snapshot() []models.FollowQueueItem {
+	return q.upcoming(-1)
+}
+
+// PeekN returns copies of the next n items in the order Pop would return
+// them, leaving the queue untouched
+func (q *Queue) PeekN(n int) []models.FollowQueueItem {
+	if n <= 0 {
+		return nil
+	}
+	return q.upcoming(n)
+}
+
+// upcoming drains copies of the lanes, returning up to limit items, or all
+// of them if limit is negative
+func (q *Queue) upcoming(limit int) []models.FollowQueueItem {
+	q.mu.Lock()
+	q.advance(time.Now())
+	lanes := copyLanes(q.lanes)
+	q.mu.Unlock()
+
+	var items []models.FollowQueueItem
+	for l := pickLane(lanes, nonEmpty); l != nil && len(items) != limit; l = pickLane(lanes, nonEmpty) {
+		item, _ := l.items.Pop()
+		items = append(items, *item)
+	}
+	return items
+}
+
+// Peek returns a copy of the item PopReady would return now without removing
+// it, or, when nothing is due, of the lane head that is due soonest. The
+// copy's next try accounts for a hold on its lane.
 func (q *Queue) Peek() *models.FollowQueueItem {
-	if q.items.Len() == 0 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.advance(now)
+	next := peekLane(q.lanes, due(now))
+	if next == nil {
+		for _, l := range q.lanes {
+			if l.head() != nil && (next == nil || l.nextTry().Before(next.nextTry())) {
+				next = l
+			}
+		}
+	}
+	if next == nil {
 		return nil
 	}
-	return q.items[0]
-} 
\ No newline at end of file
+	item := *next.head()
+	item.NextTry = next.nextTry()
+	return &item
+}
@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// throughputWindow is how far back Stats counts processed items
+const throughputWindow = time.Hour
+
+// Stats is a point-in-time summary of the queue
+type Stats struct {
+	Depth         int                  `json:"depth"`
+	Ready         int                  `json:"ready"`          // items due now
+	Bands         map[int]int          `json:"bands"`          // items per priority
+	Lanes         map[string]int       `json:"lanes"`          // items per named lane
+	Held          map[string]time.Time `json:"held,omitempty"` // lanes held back, until when
+	OldestNextTry time.Time            `json:"oldestNextTry"`  // earliest next try, zero when empty
+	PerHour       int                  `json:"perHour"`        // items processed in the last hour
+	DeadLetters   int                  `json:"deadLetters"`    // items dropped after their last retry
+}
+
+// Done records a successfully processed item for the throughput count
+func (q *Queue) Done(at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.done = append(q.trimDone(at), at)
+}
+
+// DeadLetter records an item that was dropped after its last retry
+func (q *Queue) DeadLetter(item *models.FollowQueueItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.deadLetters++
+	q.emit(EventDeadLettered, item)
+}
+
+// Stats summarizes the queue as of now
+func (q *Queue) Stats(now time.Time) Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := Stats{
+		Depth:       q.len(),
+		Bands:       make(map[int]int),
+		Lanes:       make(map[string]int),
+		DeadLetters: q.deadLetters,
+	}
+	for _, l := range q.lanes {
+		stats.Lanes[l.name] = l.items.Len()
+		if l.held.After(now) {
+			if stats.Held == nil {
+				stats.Held = make(map[string]time.Time)
+			}
+			stats.Held[l.name] = l.held
+		}
+		for _, item := range l.items.Items() {
+			stats.Bands[item.Priority]++
+			if !item.NextTry.After(now) {
+				stats.Ready++
+			}
+			if stats.OldestNextTry.IsZero() || item.NextTry.Before(stats.OldestNextTry) {
+				stats.OldestNextTry = item.NextTry
+			}
+		}
+	}
+	q.done = q.trimDone(now)
+	stats.PerHour = len(q.done)
+	return stats
+}
+
+// trimDone drops processed times outside the throughput window. The caller
+// must hold the lock.
+func (q *Queue) trimDone(now time.Time) []time.Time {
+	cutoff := now.Add(-throughputWindow)
+	i := 0
+	for i < len(q.done) && !q.done[i].After(cutoff) {
+		i++
+	}
+	return q.done[i:]
+}
@@ -0,0 +1,281 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// slowShare is the share of the server's budget left below which the
+	// rate is lowered, in proportion to how little is left
+	slowShare = 0.25
+
+	// minShare is the lowest fraction of the configured rate the controller
+	// slows down to
+	minShare = 0.1
+
+	// recoverInterval is how long the rate must go without pressure from the
+	// server before it is raised one step
+	recoverInterval = 15 * time.Minute
+
+	// recoverStep is how much of the configured rate each recovery adds back
+	recoverStep = 0.1
+
+	// defaultBackoff is how long requests stop after a 429 that didn't say
+	// when to retry
+	defaultBackoff = 15 * time.Minute
+
+	// burstShare is the share of an hour's requests the bucket holds, so
+	// requests saved up while idle don't go out all at once
+	burstShare = 0.1
+)
+
+// Observation is what the server reported about its rate limit on one
+// response
+type Observation struct {
+	Limit     int       `json:"limit"`     // points allowed in the current window, 0 if unknown
+	Remaining int       `json:"remaining"` // points left in the current window
+	Reset     time.Time `json:"reset"`     // when the window resets, zero if unknown
+}
+
+// Controller is a token bucket whose rate adapts to the server's feedback.
+// It starts at the configured rate per hour and never exceeds it; as the
+// server reports its budget running low the rate is lowered, a 429 halves it
+// and stops requests until the server allows them again, and a quiet spell
+// brings it back up step by step. It is safe for concurrent use.
+type Controller struct {
+	mu       sync.Mutex
+	max      float64   // configured requests per hour
+	rate     float64   // current requests per hour
+	tokens   float64   // requests that may be made now
+	refilled time.Time // when tokens were last refilled
+	blocked  time.Time // no requests until then
+	pressure time.Time // when the server last pushed back, or the rate last recovered
+	last     Observation
+}
+
+// New returns a controller allowing perHour requests per hour, with a full
+// bucket
+func New(perHour int) *Controller {
+	now := time.Now()
+	c := &Controller{
+		max:      float64(perHour),
+		rate:     float64(perHour),
+		refilled: now,
+		pressure: now,
+	}
+	c.tokens = c.burst()
+	return c
+}
+
+// burst returns how many requests the bucket holds at the current rate
+func (c *Controller) burst() float64 {
+	return math.Max(1, c.rate*burstShare)
+}
+
+// floor returns the lowest rate the controller slows down to
+func (c *Controller) floor() float64 {
+	return math.Max(1, c.max*minShare)
+}
+
+// refill adds the tokens earned since the last refill and raises the rate a
+// step if the server hasn't pushed back for recoverInterval
+func (c *Controller) refill(now time.Time) {
+	if c.rate < c.max && now.Sub(c.pressure) >= recoverInterval {
+		c.rate = math.Min(c.max, c.rate+c.max*recoverStep)
+		c.pressure = now
+	}
+	if elapsed := now.Sub(c.refilled); elapsed > 0 {
+		c.tokens = math.Min(c.burst(), c.tokens+c.rate*elapsed.Hours())
+		c.refilled = now
+	}
+}
+
+// Next returns when the next request may be made, which is now if one may
+// be made already. A controller configured with no rate only stops requests
+// the server refused.
+func (c *Controller) Next(now time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.available(now)
+}
+
+// Reserve spends a token on a request if one may be made now and returns
+// now; otherwise it spends nothing and returns when to try again. It lets
+// concurrent callers share the limit without overshooting it.
+func (c *Controller) Reserve(now time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := c.available(now)
+	if !next.After(now) {
+		c.tokens--
+	}
+	return next
+}
+
+// available returns when the next request may be made, refilling first
+func (c *Controller) available(now time.Time) time.Time {
+	c.refill(now)
+	if c.blocked.After(now) {
+		return c.blocked
+	}
+	if c.max <= 0 || c.tokens >= 1 {
+		return now
+	}
+	return now.Add(time.Duration((1 - c.tokens) / c.rate * float64(time.Hour)))
+}
+
+// Take spends a token on a request made now
+func (c *Controller) Take(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refill(now)
+	c.tokens--
+}
+
+// Observe adjusts the rate to what the server reported. With less than
+// slowShare of its budget left the rate drops in proportion, so the rest is
+// spread until the reset; with none left, requests stop until the reset.
+func (c *Controller) Observe(o Observation, now time.Time) {
+	if o.Limit <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = o
+	c.refill(now)
+	if o.Remaining <= 0 && o.Reset.After(now) {
+		c.block(o.Reset)
+	}
+	if c.max <= 0 {
+		return
+	}
+	share := float64(o.Remaining) / float64(o.Limit)
+	if share >= slowShare {
+		return
+	}
+	target := math.Max(c.floor(), c.max*share/slowShare)
+	if target < c.rate {
+		c.rate = target
+		c.tokens = math.Min(c.tokens, c.burst())
+	}
+	c.pressure = now
+}
+
+// Throttle handles a 429: the rate is halved and requests stop for
+// retryAfter, or defaultBackoff if the server didn't say
+func (c *Controller) Throttle(retryAfter time.Duration, now time.Time) {
+	if retryAfter <= 0 {
+		retryAfter = defaultBackoff
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refill(now)
+	if c.max > 0 {
+		c.rate = math.Max(c.floor(), c.rate/2)
+	}
+	c.tokens = 0
+	c.block(now.Add(retryAfter))
+	c.pressure = now
+}
+
+// block stops requests until a time, unless they are stopped for longer
+func (c *Controller) block(until time.Time) {
+	if until.After(c.blocked) {
+		c.blocked = until
+	}
+}
+
+// SetMax changes the configured rate to perHour requests per hour. A rate at
+// the old maximum moves to the new one; a rate slowed down by the server
+// stays where it is unless it is above the new maximum.
+func (c *Controller) SetMax(perHour int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refill(now)
+	atMax := c.rate >= c.max
+	c.max = float64(perHour)
+	if atMax || c.rate > c.max {
+		c.rate = c.max
+	}
+	c.tokens = math.Min(c.tokens, c.burst())
+}
+
+// Status is a snapshot of a controller
+type Status struct {
+	Max     int         `json:"max"`     // configured requests per hour
+	Rate    int         `json:"rate"`    // current requests per hour
+	Blocked time.Time   `json:"blocked"` // requests stop until then, zero if they don't
+	Server  Observation `json:"server"`  // the server's last report
+}
+
+// Status returns the controller's current state
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := Status{
+		Max:    int(c.max),
+		Rate:   int(math.Round(c.rate)),
+		Server: c.last,
+	}
+	if c.blocked.After(time.Now()) {
+		status.Blocked = c.blocked
+	}
+	return status
+}
+
+// State is what a controller has learned, saved so a restart carries on with
+// the same rate, tokens and waits instead of starting afresh
+type State struct {
+	Rate     float64     `json:"rate"`
+	Tokens   float64     `json:"tokens"`
+	Refilled time.Time   `json:"refilled"`
+	Blocked  time.Time   `json:"blocked"`
+	Pressure time.Time   `json:"pressure"`
+	Server   Observation `json:"server"`
+}
+
+// State returns the controller's state for saving
+func (c *Controller) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return State{
+		Rate:     c.rate,
+		Tokens:   c.tokens,
+		Refilled: c.refilled,
+		Blocked:  c.blocked,
+		Pressure: c.pressure,
+		Server:   c.last,
+	}
+}
+
+// Restore carries on from a saved state. The rate is kept within the
+// configured one, which may have changed since, and the tokens earned since
+// the state was saved are added.
+func (c *Controller) Restore(state State, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if state.Rate > 0 && c.max > 0 {
+		c.rate = math.Max(c.floor(), math.Min(c.max, state.Rate))
+	}
+	c.tokens = math.Min(c.burst(), state.Tokens)
+	if !state.Refilled.IsZero() && state.Refilled.Before(now) {
+		c.refilled = state.Refilled
+	}
+	if !state.Pressure.IsZero() && state.Pressure.Before(now) {
+		c.pressure = state.Pressure
+	}
+	c.block(state.Blocked)
+	c.last = state.Server
+	c.refill(now)
+}
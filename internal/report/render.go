@@ -0,0 +1,250 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Report formats
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the report formats
+var Formats = []string{FormatMarkdown, FormatHTML}
+
+// chartWidth and chartHeight size the follower chart of HTML reports
+const (
+	chartWidth  = 600
+	chartHeight = 160
+)
+
+// sparks are the bars of a Markdown sparkline, lowest first
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		return t.Local().Format("Jan 2, 2006")
+	},
+	"day": func(t time.Time) string {
+		return t.Local().Format("Mon Jan 2")
+	},
+	"when": func(t time.Time) string {
+		return t.Local().Format("Mon Jan 2 15:04")
+	},
+	"percent": func(f float64) string {
+		return fmt.Sprintf("%.1f%%", f*100)
+	},
+	"signed": func(n int) string {
+		return fmt.Sprintf("%+d", n)
+	},
+	"cell": func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	},
+	"first": func(values []int) int {
+		return values[0]
+	},
+	"last": func(values []int) int {
+		return values[len(values)-1]
+	},
+	"sparkline": sparkline,
+	"chart": func(values []int) string {
+		return chartPoints(values, chartWidth, chartHeight)
+	},
+	"chartWidth":  func() int { return chartWidth },
+	"chartHeight": func() int { return chartHeight },
+}
+
+var markdown = template.Must(template.New("markdown").Funcs(funcs).Parse(`# Growth report: {{date .From}} – {{date .To}}
+
+## Followers
+{{if .Growth}}
+{{first .Followers}} → {{last .Followers}} followers ({{signed .FollowerGain}})
+
+` + "`{{sparkline .Followers}}`" + `
+
+| Day | Followers | Following | Follows |
+|-----|----------:|----------:|--------:|
+{{- range .Growth}}
+| {{day .Day}} | {{.Followers}} | {{.Following}} | {{.FollowsPerformed}} |
+{{- end}}
+{{else}}
+No daily snapshots were recorded in this period.
+{{end}}
+## Follows and follow-backs
+
+| | |
+|-|-:|
+| Follows | {{.Follows}} |
+| Failed follows | {{.FollowsFailed}} |
+| Unfollows | {{.Unfollows}} |
+| Followed back | {{.FollowedBack}} of {{.Tracked}} ({{percent .FollowBackRate}}) |
+
+## Best sources
+{{if .Sources}}
+| Source | Followed | Followed back | Rate | Median time |
+|--------|---------:|--------------:|-----:|------------:|
+{{- range .Sources}}
+| {{cell .Group}} | {{.Followed}} | {{.FollowedBack}} | {{percent .Rate}} | {{.Median}} |
+{{- end}}
+{{else}}
+No follows were made in this period.
+{{end}}
+## Rate-limit incidents
+{{if .RateLimits}}
+| When | Limit | Detail |
+|------|-------|--------|
+{{- range .RateLimits}}
+| {{when .CreatedAt}} | {{cell .Strategy}} | {{cell .Error}} |
+{{- end}}
+{{else}}
+No limits were reached in this period.
+{{end}}`))
+
+var html = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Growth report: {{date .From}} – {{date .To}}</title>
+<style>
+body { font-family: sans-serif; max-width: 760px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #ddd; text-align: left; }
+td.n, th.n { text-align: right; }
+svg { background: #f6f8fa; }
+</style>
+</head>
+<body>
+<h1>Growth report: {{date .From}} – {{date .To}}</h1>
+
+<h2>Followers</h2>
+{{- if .Growth}}
+<p>{{signed .FollowerGain}} followers over the period.</p>
+<svg width="{{chartWidth}}" height="{{chartHeight}}" viewBox="0 0 {{chartWidth}} {{chartHeight}}">
+<polyline fill="none" stroke="#0085ff" stroke-width="2" points="{{chart .Followers}}"/>
+</svg>
+<table>
+<tr><th>Day</th><th class="n">Followers</th><th class="n">Following</th><th class="n">Follows</th></tr>
+{{- range .Growth}}
+<tr><td>{{day .Day}}</td><td class="n">{{.Followers}}</td><td class="n">{{.Following}}</td><td class="n">{{.FollowsPerformed}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No daily snapshots were recorded in this period.</p>
+{{- end}}
+
+<h2>Follows and follow-backs</h2>
+<table>
+<tr><td>Follows</td><td class="n">{{.Follows}}</td></tr>
+<tr><td>Failed follows</td><td class="n">{{.FollowsFailed}}</td></tr>
+<tr><td>Unfollows</td><td class="n">{{.Unfollows}}</td></tr>
+<tr><td>Followed back</td><td class="n">{{.FollowedBack}} of {{.Tracked}} ({{percent .FollowBackRate}})</td></tr>
+</table>
+
+<h2>Best sources</h2>
+{{- if .Sources}}
+<table>
+<tr><th>Source</th><th class="n">Followed</th><th class="n">Followed back</th><th class="n">Rate</th><th class="n">Median time</th></tr>
+{{- range .Sources}}
+<tr><td>{{.Group}}</td><td class="n">{{.Followed}}</td><td class="n">{{.FollowedBack}}</td><td class="n">{{percent .Rate}}</td><td class="n">{{.Median}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No follows were made in this period.</p>
+{{- end}}
+
+<h2>Rate-limit incidents</h2>
+{{- if .RateLimits}}
+<table>
+<tr><th>When</th><th>Limit</th><th>Detail</th></tr>
+{{- range .RateLimits}}
+<tr><td>{{when .CreatedAt}}</td><td>{{.Strategy}}</td><td>{{.Error}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No limits were reached in this period.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// Render writes a report in one of Formats
+func Render(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(w, r)
+	case FormatHTML:
+		return HTML(w, r)
+	}
+	return fmt.Errorf("unknown report format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Markdown writes a report as Markdown, charting followers as a sparkline
+func Markdown(w io.Writer, r *Report) error {
+	if err := markdown.Execute(w, r); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+// HTML writes a report as a standalone HTML page, charting followers as an
+// inline SVG line
+func HTML(w io.Writer, r *Report) error {
+	if err := html.Execute(w, r); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+// sparkline draws values as a row of bars scaled between their minimum and
+// maximum
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low, high = min(low, v), max(high, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = (v - low) * (len(sparks) - 1) / (high - low)
+		}
+		sb.WriteRune(sparks[level])
+	}
+	return sb.String()
+}
+
+// chartPoints lays values out as SVG polyline points across a width by height
+// box, the highest value at the top
+func chartPoints(values []int, width, height int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low, high = min(low, v), max(high, v)
+	}
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := width / 2
+		if len(values) > 1 {
+			x = i * width / (len(values) - 1)
+		}
+		y := height / 2
+		if high > low {
+			y = height - (v-low)*height/(high-low)
+		}
+		points[i] = fmt.Sprintf("%d,%d", x, y)
+	}
+	return strings.Join(points, " ")
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/stats"
+)
+
+const (
+	// eventLimit bounds how much history a single report reads
+	eventLimit = 100000
+
+	// maxSources is how many sources the report ranks
+	maxSources = 10
+)
+
+// Report is the account's growth over a period
+type Report struct {
+	From time.Time
+	To   time.Time
+
+	// Growth holds the daily snapshots within the period, oldest first
+	Growth []models.DailyStats
+
+	// Activity within the period
+	Follows       int
+	FollowsFailed int
+	Unfollows     int
+
+	// Of the accounts followed within the period, how many are tracked and
+	// how many of them followed back so far
+	Tracked      int
+	FollowedBack int
+
+	// Sources ranks where the period's follows came from, best follow-back
+	// rate first
+	Sources []stats.FollowBackStats
+
+	// RateLimits holds the waits on a limit within the period, oldest first
+	RateLimits []models.FollowEvent
+}
+
+// Followers returns the follower counts of the daily snapshots, oldest first
+func (r Report) Followers() []int {
+	counts := make([]int, len(r.Growth))
+	for i, snapshot := range r.Growth {
+		counts[i] = snapshot.Followers
+	}
+	return counts
+}
+
+// FollowerGain returns the change in followers across the period's
+// snapshots, 0 with fewer than two
+func (r Report) FollowerGain() int {
+	if len(r.Growth) < 2 {
+		return 0
+	}
+	return r.Growth[len(r.Growth)-1].Followers - r.Growth[0].Followers
+}
+
+// FollowBackRate returns the share of the period's tracked follows that
+// followed back
+func (r Report) FollowBackRate() float64 {
+	if r.Tracked == 0 {
+		return 0
+	}
+	return float64(r.FollowedBack) / float64(r.Tracked)
+}
+
+// Build collects the report for the period between from and to
+func Build(ctx context.Context, store db.Store, from, to time.Time) (*Report, error) {
+	report := &Report{From: from, To: to}
+
+	snapshots, err := store.LoadDailyStats(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Day.After(to) {
+			break
+		}
+		report.Growth = append(report.Growth, snapshot)
+	}
+
+	events, err := store.LoadEvents(ctx, "", eventLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	for _, event := range events {
+		if event.CreatedAt.Before(from) {
+			// Events are newest first
+			break
+		}
+		if event.CreatedAt.After(to) {
+			continue
+		}
+		switch {
+		case event.Action == models.ActionFollow && event.Result == models.ResultSuccess:
+			report.Follows++
+		case event.Action == models.ActionFollow:
+			report.FollowsFailed++
+		case event.Action == models.ActionUnfollow && event.Result == models.ResultSuccess:
+			report.Unfollows++
+		case event.Action == models.ActionRateLimit:
+			report.RateLimits = append(report.RateLimits, event)
+		}
+	}
+	for i, j := 0, len(report.RateLimits)-1; i < j; i, j = i+1, j-1 {
+		report.RateLimits[i], report.RateLimits[j] = report.RateLimits[j], report.RateLimits[i]
+	}
+
+	users, err := store.LoadUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	var followed []models.TargetUser
+	for _, user := range users {
+		if user.FollowDate.Before(from) || user.FollowDate.After(to) {
+			continue
+		}
+		followed = append(followed, user)
+	}
+
+	sources := stats.FollowBacks(followed, func(user models.TargetUser) string {
+		if user.Source == "" {
+			return "unknown"
+		}
+		return user.Source
+	})
+	for _, source := range sources {
+		report.Tracked += source.Followed
+		report.FollowedBack += source.FollowedBack
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Rate() > sources[j].Rate()
+	})
+	if len(sources) > maxSources {
+		sources = sources[:maxSources]
+	}
+	report.Sources = sources
+
+	return report, nil
+}
@@ -0,0 +1,190 @@
+package review
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"bsky_follower/internal/models"
+)
+
+const profileBase = "https://bsky.app/profile/"
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Reviewer decisions
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+	DecisionBlock   = "block" // reject and add to the blocklist
+)
+
+var csvHeader = []string{"handle", "did", "profile_url", "followers", "priority", "decision", "notes"}
+
+// Candidate is a pending target as seen by an external reviewer
+type Candidate struct {
+	Handle     string `json:"handle"`
+	DID        string `json:"did"`
+	ProfileURL string `json:"profileUrl"`
+	Followers  int    `json:"followers"`
+	Priority   int    `json:"priority"`
+	Decision   string `json:"decision"`
+	Notes      string `json:"notes"`
+}
+
+// Decision is a reviewer verdict on a single candidate
+type Decision struct {
+	Handle   string
+	Approve  bool
+	Block    bool // rejected and to be blocklisted
+	Priority int  // 0 keeps the current priority
+	Notes    string
+}
+
+// FormatFromPath guesses the file format from a file extension
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatCSV
+}
+
+// ProfileURL returns the public profile link for a handle
+func ProfileURL(handle string) string {
+	return profileBase + handle
+}
+
+// ExportCandidates writes users that have not been followed yet in the given format,
+// leaving the decision column blank for the reviewer to fill in
+func ExportCandidates(w io.Writer, users []models.TargetUser, format string) error {
+	var candidates []Candidate
+	for _, user := range users {
+		if user.Followed {
+			continue
+		}
+		candidates = append(candidates, Candidate{
+			Handle:     user.Handle,
+			DID:        user.DID,
+			ProfileURL: ProfileURL(user.Handle),
+			Followers:  user.Followers,
+			Priority:   user.Priority,
+		})
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(candidates); err != nil {
+			return fmt.Errorf("failed to encode candidates: %w", err)
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		for _, c := range candidates {
+			record := []string{
+				c.Handle,
+				c.DID,
+				c.ProfileURL,
+				strconv.Itoa(c.Followers),
+				strconv.Itoa(c.Priority),
+				c.Decision,
+				c.Notes,
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write csv record: %w", err)
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// ReadDecisions reads a reviewed candidate file. Rows without a decision are skipped.
+func ReadDecisions(r io.Reader, format string) ([]Decision, error) {
+	var candidates []Candidate
+
+	switch format {
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&candidates); err != nil {
+			return nil, fmt.Errorf("failed to decode decisions: %w", err)
+		}
+	case FormatCSV:
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+
+		columns := make(map[string]int)
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, ok := columns["handle"]; !ok {
+			return nil, fmt.Errorf("csv is missing a handle column")
+		}
+		if _, ok := columns["decision"]; !ok {
+			return nil, fmt.Errorf("csv is missing a decision column")
+		}
+
+		field := func(record []string, name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		for _, record := range records[1:] {
+			c := Candidate{
+				Handle:   field(record, "handle"),
+				Decision: field(record, "decision"),
+				Notes:    field(record, "notes"),
+			}
+			if p := field(record, "priority"); p != "" {
+				priority, err := strconv.Atoi(p)
+				if err != nil {
+					return nil, fmt.Errorf("invalid priority %q for %s", p, c.Handle)
+				}
+				c.Priority = priority
+			}
+			candidates = append(candidates, c)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	var decisions []Decision
+	for _, c := range candidates {
+		if c.Handle == "" || c.Decision == "" {
+			continue
+		}
+		switch strings.ToLower(c.Decision) {
+		case DecisionApprove, "yes", "y":
+			decisions = append(decisions, Decision{Handle: c.Handle, Approve: true, Priority: c.Priority, Notes: c.Notes})
+		case DecisionReject, "no", "n":
+			decisions = append(decisions, Decision{Handle: c.Handle, Notes: c.Notes})
+		case DecisionBlock:
+			decisions = append(decisions, Decision{Handle: c.Handle, Block: true, Notes: c.Notes})
+		default:
+			return nil, fmt.Errorf("unknown decision %q for %s", c.Decision, c.Handle)
+		}
+	}
+
+	return decisions, nil
+}
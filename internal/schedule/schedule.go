@@ -0,0 +1,235 @@
+package schedule
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+const (
+	// Day and Week are the windows of the daily and weekly caps
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Limits are the pacing rules the queue processor follows
+type Limits struct {
+	PerHour int           // follows allowed per rolling hour
+	PerDay  int           // follows allowed per rolling day, 0 for no cap
+	PerWeek int           // follows allowed per rolling week, 0 for no cap
+	Gap     time.Duration // expected gap between two follows, long pauses included
+	Active  Window        // when follows may happen
+}
+
+// CapReset returns the earliest time from now on at which the daily and
+// weekly caps allow another follow, given when the earlier follows were
+// made, oldest first
+func (l Limits) CapReset(made []time.Time, now time.Time) time.Time {
+	next := now
+	caps := []struct {
+		n      int
+		window time.Duration
+	}{{l.PerDay, Day}, {l.PerWeek, Week}}
+	for _, c := range caps {
+		if c.n <= 0 {
+			continue
+		}
+		first := sort.Search(len(made), func(i int) bool { return made[i].After(now.Add(-c.window)) })
+		if len(made)-first < c.n {
+			continue
+		}
+		// The window frees up once all but c.n-1 of its follows age out
+		if reset := made[len(made)-c.n].Add(c.window); reset.After(next) {
+			next = reset
+		}
+	}
+	return next
+}
+
+// Slot is one hour of the plan
+type Slot struct {
+	Start   time.Time
+	Handles []string // planned follows in queue order
+}
+
+// Count returns the number of follows planned in the slot
+func (s Slot) Count() int {
+	return len(s.Handles)
+}
+
+// Plan is the expected follow schedule over a horizon, one slot per hour
+type Plan struct {
+	From  time.Time
+	To    time.Time
+	Slots []Slot
+}
+
+// Total returns the number of follows planned over the whole horizon
+func (p *Plan) Total() int {
+	total := 0
+	for _, slot := range p.Slots {
+		total += slot.Count()
+	}
+	return total
+}
+
+// Build simulates the queue processor over the items, given in the order
+// they would be popped, and returns the follows it would make between from
+// and from+horizon. Like the processor, each follow takes the first item in
+// that order that is ready by then, so an item that is not ready yet lets
+// the ones behind it go first. Recent are the follows made before from,
+// oldest first, which count toward the daily and weekly caps.
+func Build(items []models.FollowQueueItem, limits Limits, recent []time.Time, from time.Time, horizon time.Duration) *Plan {
+	start := from.Truncate(time.Hour)
+	plan := &Plan{From: from, To: from.Add(horizon)}
+	for t := start; t.Before(plan.To); t = t.Add(time.Hour) {
+		plan.Slots = append(plan.Slots, Slot{Start: t})
+	}
+
+	now := from
+	windowStart := from
+	windowCount := 0
+	var last time.Time
+	made := append([]time.Time(nil), recent...)
+	pending := append([]models.FollowQueueItem(nil), items...)
+
+	for len(pending) > 0 {
+		if limits.PerHour > 0 && windowCount >= limits.PerHour {
+			if reset := windowStart.Add(time.Hour); reset.After(now) {
+				now = reset
+			}
+			windowStart = now
+			windowCount = 0
+		}
+		now = limits.CapReset(made, now)
+		if !last.IsZero() && now.Before(last.Add(limits.Gap)) {
+			now = last.Add(limits.Gap)
+		}
+		now = limits.Active.Next(now)
+		if !now.Before(plan.To) {
+			break
+		}
+
+		// Take the first ready item, or wait for the one due soonest
+		next := -1
+		var soonest time.Time
+		for i, item := range pending {
+			if !item.NextTry.After(now) {
+				next = i
+				break
+			}
+			if soonest.IsZero() || item.NextTry.Before(soonest) {
+				soonest = item.NextTry
+			}
+		}
+		if next < 0 {
+			now = soonest
+			continue
+		}
+		item := pending[next]
+		pending = append(pending[:next], pending[next+1:]...)
+
+		i := int(now.Sub(start) / time.Hour)
+		plan.Slots[i].Handles = append(plan.Slots[i].Handles, item.User.Handle)
+		windowCount++
+		last = now
+		made = append(made, now)
+	}
+
+	return plan
+}
+
+// WriteText writes a per-day, per-hour summary of the plan. Hours without
+// planned follows are left out.
+func WriteText(w io.Writer, plan *Plan) error {
+	fmt.Fprintf(w, "Planned follows %s to %s: %d\n",
+		plan.From.Format("2006-01-02 15:04"), plan.To.Format("2006-01-02 15:04"), plan.Total())
+
+	day := ""
+	for _, slot := range plan.Slots {
+		if slot.Count() == 0 {
+			continue
+		}
+		if d := slot.Start.Format("Mon 2006-01-02"); d != day {
+			day = d
+			fmt.Fprintf(w, "\n%s\n", day)
+		}
+		fmt.Fprintf(w, "  %s  %3d  %s\n", slot.Start.Format("15:04"), slot.Count(), strings.Join(slot.Handles, ", "))
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// icalTime is the UTC date-time format used in iCalendar files
+const icalTime = "20060102T150405Z"
+
+// WriteICal writes the plan as an iCalendar file with one event per hour
+// that has planned follows
+func WriteICal(w io.Writer, plan *Plan) error {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(fmt.Sprintf(format, args...) + "\r\n")
+	}
+
+	stamp := time.Now().UTC().Format(icalTime)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//bsky_follower//schedule preview//EN")
+	for _, slot := range plan.Slots {
+		if slot.Count() == 0 {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:%d@bsky_follower", slot.Start.Unix())
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART:%s", slot.Start.UTC().Format(icalTime))
+		line("DTEND:%s", slot.Start.Add(time.Hour).UTC().Format(icalTime))
+		line("SUMMARY:%d planned follows", slot.Count())
+		line("DESCRIPTION:%s", escapeICal(strings.Join(slot.Handles, "\n")))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICal escapes text for an iCalendar property value
+func escapeICal(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// ParseTime parses a not-before time for scheduled follows, in local time.
+// It accepts a duration from now ("+12h"), a time of day ("09:00", the next
+// occurrence), a date ("2006-01-02", midnight), a date and time
+// ("2006-01-02 09:00") or RFC 3339.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "+") {
+		d, err := time.ParseDuration(value[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected +duration, HH:MM, YYYY-MM-DD [HH:MM] or RFC 3339", value)
+}
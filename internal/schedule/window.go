@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays maps day abbreviations to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is when follows may happen: a span of local time on some days of
+// the week. A span that ends before it starts runs past midnight and belongs
+// to the day it starts on. The zero Window is always active.
+type Window struct {
+	Start time.Duration // offset from midnight the span starts at
+	End   time.Duration // offset from midnight the span ends at, equal to Start for the whole day
+	Days  [7]bool       // active days indexed by time.Weekday, none set for every day
+}
+
+// ParseWindow parses active hours such as "09:00-22:00" (empty for the whole
+// day) and active days such as "mon-fri" or "sat,sun" (empty for every day)
+func ParseWindow(hours, days string) (Window, error) {
+	var w Window
+	if hours = strings.TrimSpace(hours); hours != "" {
+		from, to, ok := strings.Cut(hours, "-")
+		if !ok {
+			return w, fmt.Errorf("invalid active hours %q, expected HH:MM-HH:MM", hours)
+		}
+		var err error
+		if w.Start, err = parseClock(from); err != nil {
+			return w, err
+		}
+		if w.End, err = parseClock(to); err != nil {
+			return w, err
+		}
+	}
+
+	for _, part := range strings.Split(strings.ToLower(days), ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[strings.TrimSpace(from)]
+		if !ok {
+			return w, fmt.Errorf("invalid active day %q, expected mon, tue, wed, thu, fri, sat or sun", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[strings.TrimSpace(to)]; !ok {
+				return w, fmt.Errorf("invalid active day %q, expected mon, tue, wed, thu, fri, sat or sun", to)
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			w.Days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return w, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight, accepting 24:00
+func parseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Always reports whether the window never closes
+func (w Window) Always() bool {
+	return w.Start == w.End && w.Days == [7]bool{}
+}
+
+// Contains reports whether follows may happen at t
+func (w Window) Contains(t time.Time) bool {
+	day := t.Weekday()
+	offset := t.Sub(midnight(t, 0))
+	switch {
+	case w.Start == w.End:
+		return w.on(day)
+	case w.Start < w.End:
+		return w.on(day) && offset >= w.Start && offset < w.End
+	case offset >= w.Start:
+		return w.on(day)
+	default:
+		return offset < w.End && w.on((day+6)%7)
+	}
+}
+
+// Next returns t if the window is open at t, otherwise when it next opens
+func (w Window) Next(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	for i := 0; i <= 7; i++ {
+		start := midnight(t, i).Add(w.Start)
+		if start.After(t) && w.on(start.Weekday()) {
+			return start
+		}
+	}
+	return t
+}
+
+// on reports whether a span may start on a day
+func (w Window) on(day time.Weekday) bool {
+	return w.Days == [7]bool{} || w.Days[day]
+}
+
+// midnight returns the start of the day days after t's, in t's location
+func midnight(t time.Time, days int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+days, 0, 0, 0, 0, t.Location())
+}
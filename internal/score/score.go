@@ -0,0 +1,178 @@
+// Package score turns what is known about a candidate account into a
+// follow priority
+package score
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// recencyWindow is how long after a post an account still counts as active
+const recencyWindow = 30 * 24 * time.Hour
+
+// fullGrowth is the relative follower growth per day that scores 1
+const fullGrowth = 0.05
+
+// Weight names, as used in BSKY_SCORE_WEIGHTS
+const (
+	Followers  = "followers"
+	Ratio      = "ratio"
+	Recency    = "recency"
+	Mutuals    = "mutuals"
+	Engagement = "engagement"
+	Growth     = "growth"
+	Source     = "source"
+)
+
+// Names lists every weight name
+var Names = []string{Followers, Ratio, Recency, Mutuals, Engagement, Growth, Source}
+
+// Weights scale each signal's contribution to the priority. Every signal is
+// scored from 0 to 1 first, so with the default weights of 1 a candidate
+// gets between 0 and 6 on top of its base priority.
+type Weights struct {
+	Followers  float64
+	Ratio      float64
+	Recency    float64
+	Mutuals    float64
+	Engagement float64
+	Growth     float64
+	Source     float64 // scales the BSKY_SOURCE_PRIORITY offset
+}
+
+// DefaultWeights weighs every signal equally
+var DefaultWeights = Weights{Followers: 1, Ratio: 1, Recency: 1, Mutuals: 1, Engagement: 1, Growth: 1, Source: 1}
+
+// FromMap overrides the default weights with the named ones
+func FromMap(named map[string]float64) (Weights, error) {
+	w := DefaultWeights
+	for name, value := range named {
+		switch name {
+		case Followers:
+			w.Followers = value
+		case Ratio:
+			w.Ratio = value
+		case Recency:
+			w.Recency = value
+		case Mutuals:
+			w.Mutuals = value
+		case Engagement:
+			w.Engagement = value
+		case Growth:
+			w.Growth = value
+		case Source:
+			w.Source = value
+		default:
+			return w, fmt.Errorf("unknown score weight %q", name)
+		}
+	}
+	return w, nil
+}
+
+// Signals is what is known about a candidate. Zero values score 0.
+type Signals struct {
+	Followers  int
+	Following  int
+	Mutuals    int       // accounts I follow that follow the candidate
+	LastPost   time.Time // zero if unknown
+	Engagement float64   // average likes, reposts and replies per post seen
+	Growth     float64   // followers gained per day relative to the follower count
+}
+
+// Score returns the weighted sum of the candidate's signals, rounded
+func (w Weights) Score(s Signals, now time.Time) int {
+	return int(math.Round(w.Breakdown(s, now).Total()))
+}
+
+// Breakdown is each signal's weighted contribution to a score
+type Breakdown struct {
+	Followers  float64 `json:"followers"`
+	Ratio      float64 `json:"ratio"`
+	Recency    float64 `json:"recency"`
+	Mutuals    float64 `json:"mutuals"`
+	Engagement float64 `json:"engagement"`
+	Growth     float64 `json:"growth"`
+}
+
+// Total returns the unrounded score
+func (b Breakdown) Total() float64 {
+	return b.Followers + b.Ratio + b.Recency + b.Mutuals + b.Engagement + b.Growth
+}
+
+// String lists the signals that add to the score, e.g.
+// "followers 0.75, recency 0.40"
+func (b Breakdown) String() string {
+	var parts []string
+	for _, part := range []struct {
+		name  string
+		value float64
+	}{
+		{Followers, b.Followers},
+		{Ratio, b.Ratio},
+		{Recency, b.Recency},
+		{Mutuals, b.Mutuals},
+		{Engagement, b.Engagement},
+		{Growth, b.Growth},
+	} {
+		if part.value != 0 {
+			parts = append(parts, fmt.Sprintf("%s %.2f", part.name, part.value))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Breakdown returns what each of the candidate's signals adds to its score
+func (w Weights) Breakdown(s Signals, now time.Time) Breakdown {
+	return Breakdown{
+		Followers:  w.Followers * followers(s.Followers),
+		Ratio:      w.Ratio * ratio(s.Followers, s.Following),
+		Recency:    w.Recency * recency(s.LastPost, now),
+		Mutuals:    w.Mutuals * logScale(float64(s.Mutuals), 2),
+		Engagement: w.Engagement * logScale(s.Engagement, 2),
+		Growth:     w.Growth * clamp(s.Growth/fullGrowth),
+	}
+}
+
+// SourceOffset scales a discovery source's priority offset
+func (w Weights) SourceOffset(offset int) int {
+	return int(math.Round(w.Source * float64(offset)))
+}
+
+// followers scores audience size, reaching 1 at 10,000 followers
+func followers(n int) float64 {
+	return logScale(float64(n), 4)
+}
+
+// ratio scores followers per account followed: 0 at one in ten or less, 0.5
+// at one to one and 1 at ten to one or more
+func ratio(followers, following int) float64 {
+	if followers <= 0 {
+		return 0
+	}
+	r := float64(followers) / float64(max(following, 1))
+	return clamp((math.Log10(r) + 1) / 2)
+}
+
+// recency scores how recently the candidate posted, from 1 just now down to
+// 0 after recencyWindow
+func recency(lastPost, now time.Time) float64 {
+	if lastPost.IsZero() {
+		return 0
+	}
+	return clamp(1 - float64(now.Sub(lastPost))/float64(recencyWindow))
+}
+
+// logScale maps n onto 0 to 1 logarithmically, reaching 1 at 10^decades - 1
+func logScale(n float64, decades float64) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return clamp(math.Log10(1+n) / decades)
+}
+
+// clamp limits a score to 0 to 1
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"bsky_follower/internal/api"
+	"bsky_follower/internal/models"
+)
+
+const (
+	// accountCheckInterval is how often the daemon checks my own account for
+	// signs of trouble
+	accountCheckInterval = 30 * time.Minute
+
+	// accountFollowersSetting holds my follower count at the last account
+	// check, so a drop is noticed across restarts
+	accountFollowersSetting = "account_followers"
+)
+
+// troubleLabels are the moderation labels that mean my account is being
+// hidden or acted against
+var troubleLabels = map[string]bool{
+	"!takedown": true,
+	"!suspend":  true,
+	"!hide":     true,
+}
+
+// CheckAccount looks at my own session and profile for signs of trouble: the
+// account taken down, suspended or deactivated, a moderation label hiding it,
+// or more than BSKY_FOLLOWER_DROP_PERCENT of its followers lost since the
+// last check. It returns what looks wrong, or "" if nothing does; errors are
+// only returned when the account couldn't be checked.
+func (s *Service) CheckAccount(session *models.Session) (string, error) {
+	var status *api.AccountStatusError
+	if err := s.api.GetSession(session); err != nil {
+		if errors.As(err, &status) {
+			return status.Error(), nil
+		}
+		return "", fmt.Errorf("failed to check the session: %w", err)
+	}
+
+	profile, err := s.api.GetProfile(session, session.Did)
+	if err != nil {
+		if errors.As(err, &status) {
+			return status.Error(), nil
+		}
+		return "", fmt.Errorf("failed to fetch my profile: %w", err)
+	}
+	for _, value := range profile.LabelValues() {
+		if troubleLabels[value] {
+			return fmt.Sprintf("account %s is labeled %s", session.Handle, value), nil
+		}
+	}
+
+	stored, err := s.db.GetSetting(s.ctx, accountFollowersSetting)
+	if err != nil {
+		return "", err
+	}
+	if err := s.db.SetSetting(s.ctx, accountFollowersSetting, strconv.Itoa(profile.FollowersCount)); err != nil {
+		return "", err
+	}
+	previous, _ := strconv.Atoi(stored)
+	limit := s.config.FollowerDrop
+	if limit > 0 && previous > 0 && (previous-profile.FollowersCount)*100 > limit*previous {
+		return fmt.Sprintf("followers dropped from %d to %d since the last check", previous, profile.FollowersCount), nil
+	}
+	return "", nil
+}
+
+// RunAccountHealth checks my account every accountCheckInterval until Stop.
+// When something looks wrong, every write is paused until resumed by hand
+// and the notifiers are told why. A problem found while an automatic pause
+// is in place is only logged, so it doesn't alert on every check.
+func (s *Service) RunAccountHealth(session *models.Session) {
+	ticker := time.NewTicker(accountCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ensureSession(session); err != nil {
+			s.logger.Error("Failed to renew the session for the account check: %v", err)
+		} else if problem, err := s.CheckAccount(session); err != nil {
+			s.logger.Error("Failed to check my account: %v", err)
+		} else if problem != "" {
+			s.pauseForTrouble(session, problem)
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
+
+// pauseForTrouble pauses the account until resumed by hand and alerts,
+// unless an automatic pause already holds it
+func (s *Service) pauseForTrouble(session *models.Session, problem string) {
+	pause, err := s.AccountPause(session.Did)
+	if err != nil {
+		s.logger.Error("Failed to check pause state of %s: %v", session.Handle, err)
+	}
+	if pause != nil && pause.Source == models.PauseAutomatic && pause.Until.IsZero() {
+		s.logger.Error("Account check of %s: %s (already paused)", session.Handle, problem)
+		return
+	}
+
+	if err := s.PauseAccount(session.Did, problem, models.PauseAutomatic, 0); err != nil {
+		s.alert("Failed to pause %s after an account check: %v", session.Handle, err)
+		return
+	}
+	s.alert("Paused %s: %s. Run `resume` once the account is healthy.", session.Handle, problem)
+}
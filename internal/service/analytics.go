@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/stats"
+)
+
+// FollowBackAnalytics returns the follow-back rate, median time to follow
+// back and churn of my follows, grouped by stats.BySource, stats.ByStrategy
+// or stats.ByCampaign
+func (s *Service) FollowBackAnalytics(by string) ([]stats.FollowBackStats, error) {
+	if !slices.Contains(stats.Groupings, by) {
+		return nil, fmt.Errorf("unknown grouping %q, expected one of %s", by, strings.Join(stats.Groupings, ", "))
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	campaigns := make(map[int64]string)
+	if by == stats.ByCampaign {
+		loaded, err := s.db.LoadCampaigns(s.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, campaign := range loaded {
+			campaigns[campaign.ID] = campaign.Name
+		}
+	}
+
+	group := func(user models.TargetUser) string {
+		switch by {
+		case stats.ByStrategy:
+			kind, _, _ := strings.Cut(user.Source, ":")
+			return orDefault(kind, "unknown")
+		case stats.ByCampaign:
+			if user.Campaign == 0 {
+				return "none"
+			}
+			return orDefault(campaigns[user.Campaign], fmt.Sprintf("#%d", user.Campaign))
+		}
+		return orDefault(user.Source, "unknown")
+	}
+	return stats.FollowBacks(users, group), nil
+}
+
+// orDefault returns value, or fallback if value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// autoUnfollowPrefix names the campaigns created by the auto-unfollow policy
+const autoUnfollowPrefix = "auto-unfollow-"
+
+// QueueAutoUnfollows starts an unfollow campaign for every account I followed
+// more than BSKY_AUTO_UNFOLLOW_DAYS ago that has not followed back. Protected
+// accounts, mutuals and accounts already in a campaign are left out. It returns the
+// number of accounts queued, 0 when the policy is off.
+func (s *Service) QueueAutoUnfollows(session *models.Session) (int, error) {
+	after := s.config.UnfollowAfter
+	if after <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	followed, followedBack, unfollowed := true, false, false
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{
+		Followed:       &followed,
+		FollowedBack:   &followedBack,
+		Unfollowed:     &unfollowed,
+		FollowedBefore: now.Add(-after),
+	}, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	targeted, err := s.campaignTargets()
+	if err != nil {
+		return 0, err
+	}
+
+	var subjects []string
+	for _, user := range users {
+		if !user.NotFollowingBack(now, after) || targeted[user.DID] || s.followsMe(user.DID) {
+			continue
+		}
+		if s.IsProtected(user) || s.keepsMutual(user) {
+			continue
+		}
+		subjects = append(subjects, user.DID)
+	}
+	if len(subjects) == 0 {
+		return 0, nil
+	}
+
+	if _, _, err := s.StartUnfollowCampaign(session, autoUnfollowPrefix+now.Format("2006-01-02-1504"), subjects); err != nil {
+		return 0, err
+	}
+	return len(subjects), nil
+}
+
+// campaignTargets returns the DIDs targeted by any campaign, finished or not
+func (s *Service) campaignTargets() (map[string]bool, error) {
+	campaigns, err := s.db.LoadUnfollowCampaigns(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targeted := make(map[string]bool)
+	for _, campaign := range campaigns {
+		targets, err := s.db.LoadUnfollowTargets(s.ctx, campaign.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			targeted[target.DID] = true
+		}
+	}
+	return targeted, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// followsBackfilledSetting records when my follow graph was last backfilled
+const followsBackfilledSetting = "follows_backfilled_at"
+
+// BackfillReport summarizes one backfill of my follow graph
+type BackfillReport struct {
+	Follows int // accounts I follow
+	Marked  int // stored users newly marked as followed
+	Added   int // accounts I follow that were not stored yet
+}
+
+// BackfillFollows pages through every account I follow and marks it as
+// followed in the database with the URI of the follow record, adding the
+// accounts that were not stored yet with the source "existing". Their follow
+// date stays unknown, so auto-unfollow leaves follows made outside the app
+// alone. Nothing is recorded in the follow history.
+func (s *Service) BackfillFollows(session *models.Session) (*BackfillReport, error) {
+	var follows []models.Profile
+	cursor := ""
+	for {
+		page, next, err := s.api.GetFollows(session, session.Did, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch follows: %w", err)
+		}
+		follows = append(follows, page...)
+		if next == "" || len(page) == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	byDID := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		byDID[user.DID] = user
+	}
+
+	report := &BackfillReport{Follows: len(follows)}
+	now := time.Now()
+	var changed []models.TargetUser
+	for _, profile := range follows {
+		user, stored := byDID[profile.Did]
+		if stored && user.Followed && user.FollowURI == profile.Viewer.Following {
+			continue
+		}
+		if !stored {
+			user = models.TargetUser{
+				Handle:      profile.Handle,
+				DID:         profile.Did,
+				SavedOn:     now,
+				LastChecked: now,
+				Source:      models.SourceExisting,
+				DisplayName: profile.DisplayName,
+				Description: profile.Description,
+				Avatar:      profile.Avatar,
+			}
+			report.Added++
+		} else if !user.Followed {
+			report.Marked++
+		}
+		if profile.Handle != "" {
+			user.Handle = profile.Handle
+		}
+		user.Followed = true
+		user.UnfollowedAt = time.Time{}
+		user.FollowURI = profile.Viewer.Following
+		changed = append(changed, user)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, changed); err != nil {
+		return nil, err
+	}
+	if err := s.db.SetSetting(s.ctx, followsBackfilledSetting, now.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+
+	for _, user := range changed {
+		s.mu.Lock()
+		s.followed[user.DID] = true
+		s.mu.Unlock()
+		s.queue.Remove(user.DID)
+	}
+
+	s.logger.Info("Backfilled %d follows (%d marked followed, %d added)", report.Follows, report.Marked, report.Added)
+	return report, nil
+}
+
+// BackfillFollowsOnce backfills my follow graph unless it was done before
+// for this database, returning nil if it was
+func (s *Service) BackfillFollowsOnce(session *models.Session) (*BackfillReport, error) {
+	done, err := s.db.GetSetting(s.ctx, followsBackfilledSetting)
+	if err != nil {
+		return nil, err
+	}
+	if done != "" {
+		return nil, nil
+	}
+	return s.BackfillFollows(session)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/backup"
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// ExportState collects users, the follow queue and the account lists into a
+// single document. The queue is loaded from the store if it is empty.
+func (s *Service) ExportState() (*backup.State, error) {
+	state := &backup.State{
+		Version:    backup.Version,
+		ExportedAt: time.Now(),
+	}
+
+	var err error
+	if state.AccountDID, err = s.db.GetSetting(s.ctx, accountDIDSetting); err != nil {
+		return nil, err
+	}
+	if state.Users, err = s.db.LoadUsers(s.ctx); err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	if state.Blocklist, err = s.db.LoadBlocklist(s.ctx); err != nil {
+		return nil, err
+	}
+	if state.Protected, err = s.db.LoadProtected(s.ctx); err != nil {
+		return nil, err
+	}
+
+	if s.QueueLen() == 0 {
+		if err := s.LoadQueue(); err != nil {
+			return nil, err
+		}
+	}
+	items := s.queue.Snapshot()
+	for _, item := range items {
+		state.Queue = append(state.Queue, backup.QueueEntry{
+			DID:      item.User.DID,
+			Handle:   item.User.Handle,
+			Priority: item.Priority,
+			Attempts: item.Attempts,
+			NextTry:  item.NextTry,
+		})
+	}
+
+	return state, nil
+}
+
+// ImportState loads a state document into the store, resolving records that
+// already exist with the given conflict mode. A document exported from a
+// different account is refused once this database is bound to an account.
+func (s *Service) ImportState(state *backup.State, mode string) (*backup.ImportReport, error) {
+	if !backup.ValidConflictMode(mode) {
+		return nil, fmt.Errorf("unknown conflict mode %q", mode)
+	}
+
+	stored, err := s.db.GetSetting(s.ctx, accountDIDSetting)
+	if err != nil {
+		return nil, err
+	}
+	if stored != "" && state.AccountDID != "" && stored != state.AccountDID {
+		return nil, fmt.Errorf("%w: state belongs to %s, database belongs to %s",
+			ErrIdentityMismatch, state.AccountDID, stored)
+	}
+
+	report := &backup.ImportReport{}
+	if report.Users, err = s.importUsers(state, mode); err != nil {
+		return nil, err
+	}
+
+	blocklist, err := s.db.LoadBlocklist(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if report.Blocklist, err = importListEntries(s.ctx, blocklist, state.Blocklist, mode, s.db.AddToBlocklist); err != nil {
+		return nil, err
+	}
+
+	protected, err := s.db.LoadProtected(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if report.Protected, err = importListEntries(s.ctx, protected, state.Protected, mode, s.db.AddProtected); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Imported state exported at %s: %+v", state.ExportedAt.Format(time.RFC3339), *report)
+	return report, nil
+}
+
+// importUsers saves the document's users, carrying retry attempts over from
+// its queue entries
+func (s *Service) importUsers(state *backup.State, mode string) (backup.ImportResult, error) {
+	var result backup.ImportResult
+
+	existing, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load users: %w", err)
+	}
+	byDID := make(map[string]models.TargetUser, len(existing))
+	for _, user := range existing {
+		byDID[user.DID] = user
+	}
+
+	attempts := make(map[string]int, len(state.Queue))
+	for _, entry := range state.Queue {
+		attempts[entry.DID] = entry.Attempts
+	}
+
+	var save []models.TargetUser
+	for _, user := range state.Users {
+		if user.DID == "" {
+			result.Skipped++
+			continue
+		}
+		user.Attempts = max(user.Attempts, attempts[user.DID])
+
+		current, exists := byDID[user.DID]
+		switch {
+		case !exists:
+			result.Added++
+		case mode == backup.ConflictSkip:
+			result.Skipped++
+			continue
+		case mode == backup.ConflictMerge:
+			user = models.MergeUsers(current, user)
+			result.Updated++
+		default:
+			result.Updated++
+		}
+
+		save = append(save, user)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, save); err != nil {
+		return backup.ImportResult{}, err
+	}
+	return result, nil
+}
+
+// importListEntries adds imported list entries, resolving subjects that are
+// already listed with the conflict mode. Merging keeps the existing entry and
+// only fills in a missing reason.
+func importListEntries(ctx context.Context, existing, imported []models.BlocklistEntry, mode string, add func(context.Context, models.BlocklistEntry) error) (backup.ImportResult, error) {
+	var result backup.ImportResult
+	bySubject := make(map[string]models.BlocklistEntry, len(existing))
+	for _, entry := range existing {
+		bySubject[entry.Subject] = entry
+	}
+
+	for _, entry := range imported {
+		current, exists := bySubject[entry.Subject]
+		switch {
+		case !exists:
+		case mode == backup.ConflictSkip:
+			result.Skipped++
+			continue
+		case mode == backup.ConflictMerge:
+			if current.Reason != "" || entry.Reason == "" {
+				result.Skipped++
+				continue
+			}
+			current.Reason = entry.Reason
+			entry = current
+		}
+
+		if err := add(ctx, entry); err != nil {
+			if errors.Is(err, db.ErrMutual) {
+				result.Skipped++
+				continue
+			}
+			return result, err
+		}
+		if exists {
+			result.Updated++
+		} else {
+			result.Added++
+		}
+	}
+	return result, nil
+}
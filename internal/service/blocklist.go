@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// Block adds a handle or DID to the blocklist and drops it from the follow queue
+func (s *Service) Block(subject, reason string) error {
+	subject = normalizeSubject(subject)
+	if subject == "" {
+		return fmt.Errorf("blocklist subject must not be empty")
+	}
+
+	if err := s.db.AddToBlocklist(s.ctx, models.BlocklistEntry{
+		Subject: subject,
+		Reason:  reason,
+		AddedOn: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info("Added %s to blocklist", subject)
+	user := models.TargetUser{Handle: subject}
+	if strings.HasPrefix(subject, "did:") {
+		user = models.TargetUser{DID: subject}
+	}
+	s.recordDecision(user, models.ActionBlock, blocklistStrategy, reason)
+	s.Dequeue(subject)
+	return nil
+}
+
+// Unblock removes a handle or DID from the blocklist
+func (s *Service) Unblock(subject string) error {
+	subject = normalizeSubject(subject)
+	if err := s.db.RemoveFromBlocklist(s.ctx, subject); err != nil {
+		return err
+	}
+
+	s.logger.Info("Removed %s from blocklist", subject)
+	return nil
+}
+
+// Blocklist returns all blocklist entries
+func (s *Service) Blocklist() ([]models.BlocklistEntry, error) {
+	return s.db.LoadBlocklist(s.ctx)
+}
+
+// ImportBlocklist adds every entry from a list file to the blocklist
+func (s *Service) ImportBlocklist(r io.Reader, defaultReason string) (int, error) {
+	return importList(r, defaultReason, s.Block)
+}
+
+// ReadSubjects reads the handles or DIDs from a list file in the format
+// importList accepts, ignoring any reasons
+func ReadSubjects(r io.Reader) ([]string, error) {
+	var subjects []string
+	_, err := importList(r, "", func(subject, _ string) error {
+		subjects = append(subjects, normalizeSubject(subject))
+		return nil
+	})
+	return subjects, err
+}
+
+// importList reads a file with one handle or DID per line, optionally
+// followed by a comma and a reason, and passes each entry to add. Blank lines
+// and lines starting with # are ignored. Mutuals refused by add are skipped.
+// It returns the number of entries added.
+func importList(r io.Reader, defaultReason string, add func(subject, reason string) error) (int, error) {
+	scanner := bufio.NewScanner(r)
+	count := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		subject, reason, found := strings.Cut(line, ",")
+		reason = strings.TrimSpace(reason)
+		if !found || reason == "" {
+			reason = defaultReason
+		}
+
+		if err := add(subject, reason); err != nil {
+			if errors.Is(err, db.ErrMutual) {
+				continue
+			}
+			return count, err
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read list file: %w", err)
+	}
+
+	return count, nil
+}
+
+// isBlocked reports whether a user is on the blocklist. Lookup errors are
+// treated as blocked so a database problem can never cause a follow.
+func (s *Service) isBlocked(user models.TargetUser) bool {
+	blocked, err := s.db.IsBlocked(s.ctx, user.DID, user.Handle)
+	if err != nil {
+		s.logger.Error("Failed to check blocklist for %s, treating as blocked: %v", user.Handle, err)
+		return true
+	}
+	return blocked
+}
+
+// normalizeSubject trims whitespace and a leading @ from a handle or DID
+func normalizeSubject(subject string) string {
+	return strings.TrimPrefix(strings.TrimSpace(subject), "@")
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/dryrun"
+	"bsky_follower/internal/models"
+)
+
+// blockRejection checks the live relationship with a user and returns
+// models.RejectBlockedBy if they block me, models.RejectBlocking if I block
+// them directly or through one of my moderation lists, or an empty string
+func (s *Service) blockRejection(session *models.Session, user models.TargetUser) (string, error) {
+	if !s.awaitRead() {
+		return "", ErrStopped
+	}
+	profile, err := s.api.GetProfile(session, user.DID)
+	if err != nil {
+		return "", err
+	}
+
+	switch viewer := profile.Viewer; {
+	case viewer.BlockedBy:
+		return models.RejectBlockedBy, nil
+	case viewer.Blocking != "" || viewer.BlockingByList != nil:
+		return models.RejectBlocking, nil
+	}
+	return "", nil
+}
+
+// skipBlocked records why a user in a block relationship with me is not
+// followed, which also keeps them out of the queue from now on
+func (s *Service) skipBlocked(user models.TargetUser, reason string) error {
+	s.logger.Info("Skipping %s: %s", user.Handle, reason)
+	if s.DryRun() {
+		s.rejectSimulated(user, dryrun.FilterBlock, reason)
+		return nil
+	}
+	s.recordDecision(user, models.ActionSkip, queueStrategy, reason)
+	user.Rejection = reason
+	user.LastChecked = time.Now()
+	return s.db.SaveUser(s.ctx, user)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+	"bsky_follower/internal/schedule"
+)
+
+// laneWeights returns the queue lane weights: BSKY_QUEUE_WEIGHTS plus a lane
+// of weight 1 for every other source kind with a budget, so each budget can
+// be held back on its own
+func laneWeights(config *models.Config) map[string]int {
+	weights := make(map[string]int, len(config.QueueWeights)+len(config.SourceBudgets))
+	for name, weight := range config.QueueWeights {
+		weights[name] = weight
+	}
+	for name := range config.SourceBudgets {
+		if _, ok := weights[name]; !ok && name != queue.DefaultLane {
+			weights[name] = 1
+		}
+	}
+	return weights
+}
+
+// budgetHold returns the lane a user is queued in and when the lane may
+// follow again if it spent its share of the hourly or daily follows, or now
+// if it may follow. Lanes without a budget may always follow.
+func (s *Service) budgetHold(user models.TargetUser, now time.Time) (string, time.Time, error) {
+	lane := s.queue.Lane(user)
+	share, ok := s.config.SourceBudgets[lane]
+	if !ok {
+		return lane, now, nil
+	}
+
+	followed := true
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{Followed: &followed, FollowedAfter: now.Add(-schedule.Day)}, 0, 0)
+	if err != nil {
+		return lane, now, fmt.Errorf("failed to load recent follows: %w", err)
+	}
+	var made []time.Time
+	for _, u := range users {
+		if s.queue.Lane(u) == lane {
+			made = append(made, u.FollowDate)
+		}
+	}
+	sort.Slice(made, func(i, j int) bool { return made[i].Before(made[j]) })
+
+	limits := s.followLimits()
+	hold := now
+	for _, window := range []struct {
+		limit  int
+		length time.Duration
+	}{
+		{limits.PerHour, time.Hour},
+		{limits.PerDay, schedule.Day},
+	} {
+		if window.limit <= 0 {
+			continue
+		}
+		if until := spentUntil(made, budgetAllowance(share, window.limit), window.length, now); until.After(hold) {
+			hold = until
+		}
+	}
+	return lane, hold, nil
+}
+
+// budgetAllowance returns how many follows a percentage of a limit allows,
+// at least one so a small share is never starved
+func budgetAllowance(share, limit int) int {
+	return max(1, int(math.Ceil(float64(share*limit)/100)))
+}
+
+// spentUntil returns when the window before it holds fewer than allowance of
+// the follow times, oldest first, which is now if it already does
+func spentUntil(made []time.Time, allowance int, window time.Duration, now time.Time) time.Time {
+	first := sort.Search(len(made), func(i int) bool { return made[i].After(now.Add(-window)) })
+	if len(made)-first < allowance {
+		return now
+	}
+	return made[len(made)-allowance].Add(window)
+}
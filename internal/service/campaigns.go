@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+const (
+	// campaignCheckInterval is how often the daemon checks follow campaigns
+	// for their end and runs their due strategies
+	campaignCheckInterval = 10 * time.Minute
+
+	// campaignHoldInterval is how long a campaign's candidates wait once the
+	// campaign has made its follows for the day
+	campaignHoldInterval = time.Hour
+
+	// campaignPriority is the base priority of campaign candidates, a step
+	// above the configured strategies so campaigns keep to their pace
+	campaignPriority = DefaultTargetPriority + 1
+)
+
+// CampaignProgress is how far a follow campaign got
+type CampaignProgress struct {
+	Campaign     models.Campaign
+	Status       string
+	Followed     int // follows made of the campaign's candidates
+	FollowedBack int // of those, accounts that followed back
+	Pending      int // candidates waiting to be followed
+	Rejected     int // candidates the targeting filters rejected
+	Today        int // follows in the last 24 hours
+	DailyLimit   int // follows allowed per day, 0 for no limit
+	Expected     int // follows an even pace would have made by now, 0 without an end date
+}
+
+// FollowBackRate returns the share of the campaign's follows that followed back
+func (p CampaignProgress) FollowBackRate() float64 {
+	if p.Followed == 0 {
+		return 0
+	}
+	return float64(p.FollowedBack) / float64(p.Followed)
+}
+
+// campaignStrategy runs a strategy for a follow campaign, tagging what it
+// finds with the campaign
+type campaignStrategy struct {
+	Strategy
+	campaign models.Campaign
+	spec     string
+}
+
+func (c campaignStrategy) Name() string {
+	return c.campaign.Name + "/" + c.spec
+}
+
+func (c campaignStrategy) Discover(ctx context.Context, session *models.Session) ([]models.TargetUser, error) {
+	users, err := c.Strategy.Discover(ctx, session)
+	for i := range users {
+		users[i].Campaign = c.campaign.ID
+	}
+	return users, err
+}
+
+// campaignStrategy builds the strategy for a campaign's strategy spec,
+// "search:<keyword>" or "followers-of:<handle>"
+func (s *Service) campaignStrategy(campaign models.Campaign, spec string) (Strategy, error) {
+	filter := CandidateFilter{
+		MinFollowers: s.config.MinFollowers,
+		MaxFollowers: s.config.MaxFollowers,
+		MinPosts:     s.config.MinPosts,
+	}
+
+	kind, param, _ := strings.Cut(spec, ":")
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return nil, fmt.Errorf("invalid campaign strategy %q, expected search:<keyword> or followers-of:<handle>", spec)
+	}
+
+	var strategy Strategy
+	switch kind {
+	case models.SourceSearch:
+		strategy = s.KeywordStrategy([]string{param}, filter, campaignPriority, keywordBatch)
+	case models.SourceFollowersOf:
+		strategy = s.FollowersOfStrategy(param, filter, campaignPriority, followersOfBatch)
+	default:
+		return nil, fmt.Errorf("unknown campaign strategy kind %q, expected search or followers-of", kind)
+	}
+	return campaignStrategy{Strategy: strategy, campaign: campaign, spec: spec}, nil
+}
+
+// CreateCampaign checks and stores a new follow campaign. The daemon starts
+// running its strategies once its start date is reached.
+func (s *Service) CreateCampaign(campaign models.Campaign) (*models.Campaign, error) {
+	switch {
+	case campaign.Name == "":
+		return nil, fmt.Errorf("a campaign needs a name")
+	case campaign.Target <= 0:
+		return nil, fmt.Errorf("a campaign needs a target above 0")
+	case len(campaign.Strategies) == 0:
+		return nil, fmt.Errorf("a campaign needs at least one strategy")
+	case !campaign.EndsAt.IsZero() && !campaign.EndsAt.After(campaign.StartsAt):
+		return nil, fmt.Errorf("a campaign must end after it starts")
+	}
+	for _, spec := range campaign.Strategies {
+		if _, err := s.campaignStrategy(campaign, spec); err != nil {
+			return nil, err
+		}
+	}
+	if existing, err := s.Campaign(campaign.Name); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("a campaign named %s already exists", campaign.Name)
+	}
+
+	if err := s.db.CreateCampaign(s.ctx, &campaign); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Created follow campaign %d (%s): %d follows from %s",
+		campaign.ID, campaign.Name, campaign.Target, strings.Join(campaign.Strategies, ", "))
+	return &campaign, nil
+}
+
+// Campaigns returns every follow campaign, oldest first
+func (s *Service) Campaigns() ([]models.Campaign, error) {
+	return s.db.LoadCampaigns(s.ctx)
+}
+
+// Campaign returns the follow campaign with a name, or nil if there is none
+func (s *Service) Campaign(name string) (*models.Campaign, error) {
+	campaigns, err := s.db.LoadCampaigns(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, campaign := range campaigns {
+		if campaign.Name == name {
+			return &campaign, nil
+		}
+	}
+	return nil, nil
+}
+
+// StopCampaign finishes a follow campaign by hand. Its candidates stay queued
+// as ordinary candidates.
+func (s *Service) StopCampaign(name string) error {
+	campaign, err := s.Campaign(name)
+	if err != nil {
+		return err
+	}
+	if campaign == nil {
+		return fmt.Errorf("no campaign named %s", name)
+	}
+	if campaign.Outcome != "" {
+		return fmt.Errorf("campaign %s already %s", name, campaign.Outcome)
+	}
+	return s.db.FinishCampaign(s.ctx, campaign.ID, models.CampaignStopped)
+}
+
+// CampaignProgress counts how far a follow campaign got by now
+func (s *Service) CampaignProgress(campaign models.Campaign, now time.Time) (*CampaignProgress, error) {
+	followed, notFollowed, followedBack, rejected, notRejected := true, false, true, true, false
+	count := func(filter db.UserFilter) (int, error) {
+		filter.Campaign = campaign.ID
+		return s.db.CountUsers(s.ctx, filter)
+	}
+
+	progress := &CampaignProgress{Campaign: campaign, Status: campaign.Status(now)}
+	var err error
+	if progress.Followed, err = count(db.UserFilter{Followed: &followed}); err != nil {
+		return nil, err
+	}
+	if progress.FollowedBack, err = count(db.UserFilter{Followed: &followed, FollowedBack: &followedBack}); err != nil {
+		return nil, err
+	}
+	if progress.Pending, err = count(db.UserFilter{Followed: &notFollowed, Rejected: &notRejected}); err != nil {
+		return nil, err
+	}
+	if progress.Rejected, err = count(db.UserFilter{Followed: &notFollowed, Rejected: &rejected}); err != nil {
+		return nil, err
+	}
+	if progress.Today, err = count(db.UserFilter{Followed: &followed, FollowedAfter: now.Add(-24 * time.Hour)}); err != nil {
+		return nil, err
+	}
+
+	progress.DailyLimit = campaign.DailyLimit(progress.Followed, now)
+	if !campaign.EndsAt.IsZero() && now.After(campaign.StartsAt) {
+		elapsed := min(now.Sub(campaign.StartsAt), campaign.EndsAt.Sub(campaign.StartsAt))
+		progress.Expected = int(float64(campaign.Target) * float64(elapsed) / float64(campaign.EndsAt.Sub(campaign.StartsAt)))
+	}
+	return progress, nil
+}
+
+// RunCampaigns checks the follow campaigns now and then every
+// campaignCheckInterval until Stop: campaigns that reached their target or
+// end date are finished, and the strategies of active campaigns run on their
+// own schedules while the campaign still needs candidates
+func (s *Service) RunCampaigns(session *models.Session) {
+	ticker := time.NewTicker(campaignCheckInterval)
+	defer ticker.Stop()
+
+	lastRun := make(map[string]time.Time)
+	for {
+		if err := s.checkCampaigns(session, lastRun); err != nil && !s.stopped() {
+			s.alert("Follow campaign check failed: %v", err)
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
+
+// checkCampaigns makes one pass over the unfinished campaigns. lastRun holds
+// when each campaign strategy last ran.
+func (s *Service) checkCampaigns(session *models.Session, lastRun map[string]time.Time) error {
+	campaigns, err := s.db.LoadCampaigns(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, campaign := range campaigns {
+		if campaign.Outcome != "" {
+			continue
+		}
+		progress, err := s.CampaignProgress(campaign, now)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case progress.Followed >= campaign.Target:
+			if err := s.finishCampaign(campaign, models.CampaignCompleted, progress); err != nil {
+				return err
+			}
+			continue
+		case progress.Status == models.CampaignEnded:
+			if err := s.finishCampaign(campaign, models.CampaignEnded, progress); err != nil {
+				return err
+			}
+			continue
+		case progress.Status != models.CampaignActive:
+			continue
+		}
+
+		// Enough candidates are waiting to reach the target
+		if progress.Followed+progress.Pending >= campaign.Target {
+			continue
+		}
+		for _, spec := range campaign.Strategies {
+			strategy, err := s.campaignStrategy(campaign, spec)
+			if err != nil {
+				s.logger.Error("Skipping strategy of campaign %s: %v", campaign.Name, err)
+				continue
+			}
+			if time.Since(lastRun[strategy.Name()]) < strategy.Schedule() {
+				continue
+			}
+			lastRun[strategy.Name()] = time.Now()
+			if _, err := s.Discover(s.stop, session, strategy); err != nil && !s.stopped() {
+				s.alert("Discovery failed: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// finishCampaign records a campaign's outcome and announces it
+func (s *Service) finishCampaign(campaign models.Campaign, outcome string, progress *CampaignProgress) error {
+	if err := s.db.FinishCampaign(s.ctx, campaign.ID, outcome); err != nil {
+		return err
+	}
+	s.logger.Info("Follow campaign %s %s with %d of %d follows", campaign.Name, outcome, progress.Followed, campaign.Target)
+	s.notify(fmt.Sprintf("🎯 Follow campaign %q %s with %d of %d follows (%.0f%% followed back)",
+		campaign.Name, outcome, progress.Followed, campaign.Target, progress.FollowBackRate()*100))
+	return nil
+}
+
+// campaignHold returns when a user found by a follow campaign may be followed
+// next: now, unless the campaign made its follows for the day. Users of
+// finished campaigns are followed like any other candidate.
+func (s *Service) campaignHold(user models.TargetUser, now time.Time) (time.Time, error) {
+	if user.Campaign == 0 {
+		return now, nil
+	}
+	campaigns, err := s.db.LoadCampaigns(s.ctx)
+	if err != nil {
+		return now, err
+	}
+	for _, campaign := range campaigns {
+		if campaign.ID != user.Campaign {
+			continue
+		}
+		switch campaign.Status(now) {
+		case models.CampaignScheduled:
+			return campaign.StartsAt, nil
+		case models.CampaignActive:
+		default:
+			return now, nil
+		}
+		progress, err := s.CampaignProgress(campaign, now)
+		if err != nil {
+			return now, err
+		}
+		if progress.DailyLimit > 0 && progress.Today >= progress.DailyLimit {
+			return now.Add(campaignHoldInterval), nil
+		}
+	}
+	return now, nil
+}
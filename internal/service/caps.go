@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/ratelimit"
+	"bsky_follower/internal/schedule"
+)
+
+// FollowBudget is what the follow limits leave to spend, for showing why
+// queue processing waits. A limit of 0 is not set, and its left count is
+// meaningless.
+type FollowBudget struct {
+	PerHour  int // follows an hour the adaptive rate limit allows
+	HourLeft int // of those, how many the last hour left
+	PerDay   int
+	DayLeft  int
+	PerWeek  int
+	WeekLeft int
+
+	Server  ratelimit.Observation // the server's last report on writes
+	Blocked time.Time             // the server refused follows until then, zero if it didn't
+	Next    time.Time             // when the next follow may happen
+}
+
+// followLimits returns the pacing rules of the queue processor
+func (s *Service) followLimits() schedule.Limits {
+	s.settings.RLock()
+	defer s.settings.RUnlock()
+
+	return schedule.Limits{
+		PerHour: s.limiter.Status().Rate,
+		PerDay:  s.config.FollowsPerDay,
+		PerWeek: s.config.FollowsPerWeek,
+		Gap:     s.pacer.Expected(),
+		Active:  s.window,
+	}
+}
+
+// HourlyRates returns about how many follows and unfollows an hour the
+// pacing and the rate limits allow, 0 for no bound. The daily and weekly
+// caps and the active hours are left out.
+func (s *Service) HourlyRates() (follows, unfollows int) {
+	rate := func(limit int, gap time.Duration) int {
+		if gap > 0 && (limit <= 0 || int(time.Hour/gap) < limit) {
+			return int(time.Hour / gap)
+		}
+		return limit
+	}
+	return rate(s.limiter.Status().Rate, s.pacer.Expected()), rate(s.unfollows.Status().Rate, s.unfollowPacer.Expected())
+}
+
+// NextActive returns when follows may next happen: now inside the active
+// window set by BSKY_ACTIVE_HOURS and BSKY_ACTIVE_DAYS, otherwise when it
+// next opens
+func (s *Service) NextActive(now time.Time) time.Time {
+	s.settings.RLock()
+	defer s.settings.RUnlock()
+	return s.window.Next(now)
+}
+
+// capped reports whether a daily or weekly follow cap is configured
+func (s *Service) capped() bool {
+	s.settings.RLock()
+	defer s.settings.RUnlock()
+	return s.config.FollowsPerDay > 0 || s.config.FollowsPerWeek > 0
+}
+
+// recentFollows returns when the successful follows of the last week were
+// made, oldest first. They come from the follow history, so the caps hold
+// across restarts.
+func (s *Service) recentFollows(now time.Time) ([]time.Time, error) {
+	if !s.capped() {
+		return nil, nil
+	}
+	made, err := s.db.LoadEventTimes(s.ctx, models.ActionFollow, models.ResultSuccess, now.Add(-schedule.Week))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent follows: %w", err)
+	}
+	return made, nil
+}
+
+// followCapReset returns when the daily and weekly caps next allow a follow,
+// which is now if they allow one already
+func (s *Service) followCapReset(now time.Time) (time.Time, error) {
+	made, err := s.recentFollows(now)
+	if err != nil {
+		return now, err
+	}
+	return s.followLimits().CapReset(made, now), nil
+}
+
+// FollowBudget returns what the follow rate limit and caps leave to spend as
+// of now. The follows are counted from the follow history, like the caps.
+func (s *Service) FollowBudget(now time.Time) (FollowBudget, error) {
+	limits := s.followLimits()
+	status := s.limiter.Status()
+	budget := FollowBudget{
+		PerHour: limits.PerHour,
+		PerDay:  limits.PerDay,
+		PerWeek: limits.PerWeek,
+		Server:  status.Server,
+		Blocked: status.Blocked,
+	}
+
+	made, err := s.db.LoadEventTimes(s.ctx, models.ActionFollow, models.ResultSuccess, now.Add(-schedule.Week))
+	if err != nil {
+		return budget, fmt.Errorf("failed to load recent follows: %w", err)
+	}
+	since := func(window time.Duration) int {
+		n := 0
+		for _, at := range made {
+			if at.After(now.Add(-window)) {
+				n++
+			}
+		}
+		return n
+	}
+	budget.HourLeft = max(budget.PerHour-since(time.Hour), 0)
+	budget.DayLeft = max(budget.PerDay-since(schedule.Day), 0)
+	budget.WeekLeft = max(budget.PerWeek-len(made), 0)
+
+	budget.Next, err = s.NextFollow(now)
+	return budget, err
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/ratelimit"
+)
+
+// checkpointSetting stores the processors' progress
+const checkpointSetting = "processor_checkpoint"
+
+// checkpoint is the progress of the follow and unfollow processors that
+// isn't in the database otherwise. The daily and weekly caps count the
+// follow history, so they need no checkpoint.
+type checkpoint struct {
+	SavedAt      time.Time          `json:"savedAt"`
+	Follows      ratelimit.State    `json:"follows"`
+	Unfollows    ratelimit.State    `json:"unfollows"`
+	Reads        ratelimit.State    `json:"reads"`
+	LastFollow   time.Time          `json:"lastFollow"`
+	NextFollow   time.Time          `json:"nextFollow"`
+	NextUnfollow time.Time          `json:"nextUnfollow"`
+	InFlight     *models.TargetUser `json:"inFlight,omitempty"` // follow sent but not yet saved
+}
+
+// saveCheckpoint stores the processors' progress. Failures are logged rather
+// than returned; a lost checkpoint only costs a slower restart.
+func (s *Service) saveCheckpoint() {
+	s.checkpointing.Lock()
+	defer s.checkpointing.Unlock()
+
+	s.mu.Lock()
+	cp := checkpoint{
+		SavedAt:      time.Now(),
+		LastFollow:   s.lastFollow,
+		NextFollow:   s.nextFollow,
+		NextUnfollow: s.nextUnfollow,
+		InFlight:     s.inFlight,
+	}
+	s.mu.Unlock()
+	cp.Follows = s.limiter.State()
+	cp.Unfollows = s.unfollows.State()
+	cp.Reads = s.reads.State()
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		s.logger.Error("Failed to encode the processor checkpoint: %v", err)
+		return
+	}
+	if err := s.db.SetSetting(s.ctx, checkpointSetting, string(data)); err != nil {
+		s.logger.Error("Failed to save the processor checkpoint: %v", err)
+	}
+}
+
+// setInFlight records the follow about to be sent, or clears it with nil,
+// and saves the checkpoint
+func (s *Service) setInFlight(user *models.TargetUser) {
+	s.mu.Lock()
+	s.inFlight = user
+	s.mu.Unlock()
+	s.saveCheckpoint()
+}
+
+// ResumeCheckpoint restores the processors' progress from the last
+// checkpoint: the adaptive rate limits, the paced times of the next follow
+// and unfollow, and a follow that was sent when the process died. That
+// follow is looked up on the server and saved as made if its record exists,
+// so it is neither lost nor sent twice. Call it before LoadQueue.
+func (s *Service) ResumeCheckpoint(session *models.Session) error {
+	value, err := s.db.GetSetting(s.ctx, checkpointSetting)
+	if err != nil {
+		return fmt.Errorf("failed to load the processor checkpoint: %w", err)
+	}
+	if value == "" {
+		return nil
+	}
+	var cp checkpoint
+	if err := json.Unmarshal([]byte(value), &cp); err != nil {
+		return fmt.Errorf("failed to decode the processor checkpoint: %w", err)
+	}
+
+	now := time.Now()
+	s.limiter.Restore(cp.Follows, now)
+	s.unfollows.Restore(cp.Unfollows, now)
+	s.reads.Restore(cp.Reads, now)
+	s.mu.Lock()
+	s.lastFollow, s.nextFollow, s.nextUnfollow = cp.LastFollow, cp.NextFollow, cp.NextUnfollow
+	s.mu.Unlock()
+	s.logger.Info("Resumed the processor checkpoint of %s", cp.SavedAt.Format(time.RFC3339))
+
+	if cp.InFlight != nil {
+		if err := s.settleInFlight(session, *cp.InFlight, cp.SavedAt); err != nil {
+			return err
+		}
+		s.setInFlight(nil)
+	}
+	return nil
+}
+
+// settleInFlight checks whether a follow interrupted before it was saved
+// reached the server, and saves it as made if it did. Otherwise the user
+// stays queued and is followed again.
+func (s *Service) settleInFlight(session *models.Session, user models.TargetUser, sentAt time.Time) error {
+	uri, err := s.api.GetFollowURI(session, user.DID)
+	if err != nil {
+		return fmt.Errorf("failed to check the interrupted follow of %s: %w", user.Handle, err)
+	}
+	if uri == "" {
+		s.logger.Info("Interrupted follow of %s did not reach the server, it stays queued", user.Handle)
+		return nil
+	}
+
+	s.logger.Info("Interrupted follow of %s was made, saving it", user.Handle)
+	s.recordEvent(user, models.ActionFollow, queueStrategy, nil)
+	user.Followed = true
+	user.FollowDate = sentAt
+	user.FollowURI = uri
+	return s.db.SaveUser(s.ctx, user)
+}
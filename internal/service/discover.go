@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/score"
+)
+
+const (
+	// followersOfInterval is how often the daemon looks for new followers of
+	// the configured accounts
+	followersOfInterval = 24 * time.Hour
+	// followersOfBatch bounds how many accounts one daemon run queues per account
+	followersOfBatch = 50
+)
+
+// CandidateFilter decides which discovered accounts are worth following.
+// Zero values leave a field unconstrained.
+type CandidateFilter struct {
+	MinFollowers int
+	MaxFollowers int
+	MinPosts     int
+}
+
+// Matches reports whether a user passes the filter
+func (f CandidateFilter) Matches(user models.TargetUser) bool {
+	if f.MinFollowers > 0 && user.Followers < f.MinFollowers {
+		return false
+	}
+	if f.MaxFollowers > 0 && user.Followers > f.MaxFollowers {
+		return false
+	}
+	return user.PostsCount >= f.MinPosts
+}
+
+// DiscoveryResult counts what a discovery run did with the accounts it saw
+type DiscoveryResult struct {
+	Scanned  int // accounts looked at
+	Added    int // new candidates saved and queued
+	Skipped  int // already stored, followed, blocklisted or me
+	Filtered int // rejected by the candidate filter or the targeting filters
+}
+
+// followersOfStrategy discovers the followers of one account
+type followersOfStrategy struct {
+	s        *Service
+	handle   string
+	filter   CandidateFilter
+	priority int
+	limit    int
+	counts   DiscoveryResult // of the last run, before the service's checks
+}
+
+// FollowersOfStrategy returns a strategy that pages through the followers of
+// an account and returns up to limit (0 for all) that pass the filter, with
+// the source "followers-of:<handle>" and their profile score added to the
+// priority
+func (s *Service) FollowersOfStrategy(handle string, filter CandidateFilter, priority, limit int) Strategy {
+	return &followersOfStrategy{s: s, handle: normalizeSubject(handle), filter: filter, priority: priority, limit: limit}
+}
+
+func (f *followersOfStrategy) Name() string {
+	return models.NewSource(models.SourceFollowersOf, f.handle)
+}
+
+func (f *followersOfStrategy) Schedule() time.Duration {
+	return followersOfInterval
+}
+
+func (f *followersOfStrategy) Discover(ctx context.Context, session *models.Session) ([]models.TargetUser, error) {
+	f.counts = DiscoveryResult{}
+	if f.handle == "" {
+		return nil, fmt.Errorf("no account given")
+	}
+
+	known, err := f.s.knownAccounts(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.TargetUser
+	scored := func(profile models.Profile) int {
+		return f.priority + f.s.weights.Score(profileSignals(profile), time.Now())
+	}
+	cursor := ""
+	for f.limit <= 0 || len(users) < f.limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, next, err := f.s.api.GetFollowers(session, f.handle, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch followers of %s: %w", f.handle, err)
+		}
+
+		var dids []string
+		for _, actor := range page {
+			f.counts.Scanned++
+			if known[actor.Did] || f.s.isBlocked(models.TargetUser{DID: actor.Did, Handle: actor.Handle}) {
+				f.counts.Skipped++
+				continue
+			}
+			known[actor.Did] = true
+			dids = append(dids, actor.Did)
+		}
+
+		limit := 0
+		if f.limit > 0 {
+			limit = f.limit - len(users)
+		}
+		found, err := f.s.profileCandidates(session, dids, f.filter, f.Name(), scored, limit, &f.counts)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, found...)
+		if next == "" || len(page) == 0 {
+			break
+		}
+		cursor = next
+	}
+	return users, nil
+}
+
+// DiscoverFollowersOf runs a followers-of strategy once, saving and queuing
+// up to limit (0 for all) followers of the account that pass the filter
+func (s *Service) DiscoverFollowersOf(session *models.Session, handle string, filter CandidateFilter, priority, limit int) (*DiscoveryResult, error) {
+	strategy := &followersOfStrategy{s: s, handle: normalizeSubject(handle), filter: filter, priority: priority, limit: limit}
+	result, err := s.Discover(s.stop, session, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.counts.merge(result), nil
+}
+
+// merge combines the counts a strategy kept while discovering with the
+// service's result of admitting what it returned
+func (counts DiscoveryResult) merge(result *DiscoveryResult) *DiscoveryResult {
+	return &DiscoveryResult{
+		Scanned:  counts.Scanned,
+		Added:    result.Added,
+		Skipped:  counts.Skipped + result.Skipped,
+		Filtered: counts.Filtered + result.Filtered,
+	}
+}
+
+// profileSignals returns the scoring signals a profile carries
+func profileSignals(profile models.Profile) score.Signals {
+	return score.Signals{
+		Followers: profile.FollowersCount,
+		Following: profile.FollowsCount,
+		Mutuals:   profile.Viewer.KnownFollowers.Count,
+	}
+}
+
+// knownAccounts returns the DIDs discovery should never add again: every
+// stored user and the account itself
+func (s *Service) knownAccounts(session *models.Session) (map[string]bool, error) {
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	known := make(map[string]bool, len(users)+1)
+	for _, user := range users {
+		known[user.DID] = true
+	}
+	known[session.Did] = true
+	return known, nil
+}
+
+// profileCandidates fetches the profiles of new accounts and returns those
+// that pass the filter and that I do not follow yet, with the priority given
+// for each profile, at most limit of them (0 for no limit). Accounts left out
+// are counted in counts.
+func (s *Service) profileCandidates(session *models.Session, dids []string, filter CandidateFilter, source string, priority func(models.Profile) int, limit int, counts *DiscoveryResult) ([]models.TargetUser, error) {
+	var users []models.TargetUser
+	full := func() bool { return limit > 0 && len(users) >= limit }
+	for start := 0; start < len(dids) && !full(); start += profilesPerRequest {
+		profiles, err := s.api.GetProfiles(session, dids[start:min(start+profilesPerRequest, len(dids))])
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch profiles: %w", err)
+		}
+
+		now := time.Now()
+		for _, profile := range profiles {
+			if full() {
+				break
+			}
+			if profile.Viewer.Following != "" {
+				counts.Skipped++
+				continue
+			}
+
+			user := models.TargetUser{
+				Handle:      profile.Handle,
+				DID:         profile.Did,
+				SavedOn:     now,
+				LastChecked: now,
+				Priority:    priority(profile),
+				Source:      source,
+			}
+			user.ApplyProfile(profile)
+			if !filter.Matches(user) {
+				counts.Filtered++
+				continue
+			}
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// admit saves new candidates, records their follower counts and queues those
+// that pass the targeting filters, returning how many did not
+func (s *Service) admit(users []models.TargetUser) (int, error) {
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	rejected := s.screen(users)
+	if err := s.db.SaveUsers(s.ctx, users); err != nil {
+		return 0, err
+	}
+	observations := make([]models.FollowerObservation, len(users))
+	for i, user := range users {
+		observations[i] = models.FollowerObservation{DID: user.DID, Followers: user.Followers, ObservedAt: user.LastChecked}
+	}
+	if err := s.db.RecordFollowerCounts(s.ctx, observations); err != nil {
+		return 0, err
+	}
+	for _, user := range users {
+		if user.Rejection == "" {
+			s.AddToQueue(user, user.Priority)
+		}
+	}
+	return rejected, nil
+}
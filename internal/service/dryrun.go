@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bsky_follower/internal/dryrun"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/score"
+)
+
+// dryRunExtensions are the file extensions dry-run reports are written with,
+// per format
+var dryRunExtensions = map[string]string{
+	dryrun.FormatJSON:  ".json",
+	dryrun.FormatTable: ".txt",
+}
+
+// DryRun reports whether follows and unfollows are only simulated
+func (s *Service) DryRun() bool {
+	return s.dryRun.Load()
+}
+
+// SetDryRun turns dry-run mode on or off. Turning it on starts a fresh report.
+func (s *Service) SetDryRun(on bool) {
+	if on && !s.dryRun.Load() {
+		s.mu.Lock()
+		s.simulated = dryrun.Report{}
+		s.mu.Unlock()
+	}
+	s.dryRun.Store(on)
+}
+
+// Simulated returns the dry-run report: every follow and unfollow that would
+// have been made, in order, and every candidate the filters rejected
+func (s *Service) Simulated() dryrun.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.simulated.Copy()
+}
+
+// simulate adds an action to the dry-run report instead of the follow history.
+// Follows carry the user's priority and what its stored signals add to it;
+// mutuals and engagement are only known during discovery, so they show as 0.
+func (s *Service) simulate(user models.TargetUser, action, strategy string) {
+	now := time.Now()
+	entry := dryrun.Action{
+		Action:   action,
+		DID:      user.DID,
+		Handle:   user.Handle,
+		Source:   user.Source,
+		Strategy: strategy,
+		At:       now,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if action == models.ActionFollow {
+		entry.Priority = user.Priority
+		entry.Score = s.weights.Breakdown(score.Signals{
+			Followers: user.Followers,
+			Following: user.Following,
+			LastPost:  user.LastPostAt,
+			Growth:    s.growth[user.DID],
+		}, now)
+	}
+	s.simulated.Act(entry)
+}
+
+// rejectSimulated adds a candidate kept out by a filter to the dry-run
+// report. Outside dry runs it does nothing.
+func (s *Service) rejectSimulated(user models.TargetUser, filter, reason string) {
+	if !s.DryRun() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simulated.Reject(dryrun.Rejection{
+		DID:    user.DID,
+		Handle: user.Handle,
+		Source: user.Source,
+		Filter: filter,
+		Reason: reason,
+		At:     time.Now(),
+	})
+}
+
+// SaveDryRunReport writes the dry-run report into the report directory in
+// every format and returns the files' paths. Without a report directory it
+// writes nothing.
+func (s *Service) SaveDryRunReport() ([]string, error) {
+	if s.config.ReportDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(s.config.ReportDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the report directory: %w", err)
+	}
+
+	r := s.Simulated()
+	name := "dry-run-" + time.Now().Format("2006-01-02-150405")
+	var paths []string
+	for _, format := range dryrun.Formats {
+		path := filepath.Join(s.config.ReportDir, name+dryRunExtensions[format])
+		if err := writeDryRunReport(path, r, format); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeDryRunReport writes a dry-run report to a file in one format
+func writeDryRunReport(path string, r dryrun.Report, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := dryrun.Render(f, r, format); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"bsky_follower/internal/models"
+)
+
+// enrichProgressEvery is how many resolved targets pass between progress logs
+const enrichProgressEvery = 100
+
+// targetResolution is the outcome of resolving one import target
+type targetResolution struct {
+	user *models.TargetUser
+	err  error
+}
+
+// resolveTargets looks up the DIDs and profiles of targets with
+// BSKY_ENRICH_WORKERS lookups at a time, all sharing the lookup rate limit.
+// The results are in the order of the targets. Targets not reached before
+// Stop fail with ErrStopped.
+func (s *Service) resolveTargets(session *models.Session, targets []TargetSpec) []targetResolution {
+	results := make([]targetResolution, len(targets))
+	jobs := make(chan int)
+	var done atomic.Int64
+	var workers sync.WaitGroup
+	for w := 0; w < max(s.config.EnrichWorkers, 1); w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				results[i].user, results[i].err = s.resolveTarget(session, targets[i].Subject)
+				if n := done.Add(1); n%enrichProgressEvery == 0 {
+					s.logger.Info("Resolved %d of %d targets", n, len(targets))
+				}
+			}
+		}()
+	}
+
+	for i := range targets {
+		if s.stopped() {
+			results[i].err = ErrStopped
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	workers.Wait()
+	return results
+}
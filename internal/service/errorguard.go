@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// errorsResetSetting records when the account was last resumed, so the
+// failures that paused it don't pause it again right away
+const errorsResetSetting = "errors_reset_at"
+
+// recentFailures returns how many of the last BSKY_ERROR_WINDOW follow
+// attempts since the account was last resumed failed, and how many attempts
+// that window holds
+func (s *Service) recentFailures() (failed, attempts int, err error) {
+	filter := db.EventFilter{Action: models.ActionFollow}
+	reset, err := s.db.GetSetting(s.ctx, errorsResetSetting)
+	if err != nil {
+		return 0, 0, err
+	}
+	if reset != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, reset); err != nil {
+			return 0, 0, fmt.Errorf("invalid %s setting %q: %w", errorsResetSetting, reset, err)
+		}
+	}
+
+	events, err := s.db.QueryEvents(s.ctx, filter, s.config.ErrorWindow)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, event := range events {
+		if event.Result == models.ResultFailed {
+			failed++
+		}
+	}
+	return failed, len(events), nil
+}
+
+// checkErrorSpike pauses the account until it is resumed by hand when more
+// than BSKY_MAX_ERROR_PERCENT of the last BSKY_ERROR_WINDOW follow attempts
+// failed, and says so through every notifier. The rate is only judged once
+// the window is full, so a single failure after a resume doesn't trip it.
+func (s *Service) checkErrorSpike(session *models.Session) {
+	limit := s.config.MaxErrorPercent
+	if limit <= 0 {
+		return
+	}
+
+	failed, attempts, err := s.recentFailures()
+	if err != nil {
+		s.logger.Error("Failed to check the recent follow error rate: %v", err)
+		return
+	}
+	if attempts < s.config.ErrorWindow || failed*100 <= limit*attempts {
+		return
+	}
+
+	reason := fmt.Sprintf("%d of the last %d follows failed", failed, attempts)
+	if err := s.PauseAccount(session.Did, reason, models.PauseAutomatic, 0); err != nil {
+		s.alert("Failed to pause %s after a spike in follow errors: %v", session.Handle, err)
+		return
+	}
+	s.alert("Paused %s: %s. Run `resume` once the cause is fixed.", session.Handle, reason)
+}
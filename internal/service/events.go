@@ -0,0 +1,97 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/events"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+)
+
+// SubscribeEvents returns a channel receiving every service event from now
+// on and a function that ends the subscription. A subscriber that falls
+// behind misses events.
+func (s *Service) SubscribeEvents() (<-chan events.Event, func()) {
+	return s.events.Subscribe()
+}
+
+// forwardQueueEvents publishes the follow queue's events on the service's
+// bus until the queue subscription is ended by Close
+func (s *Service) forwardQueueEvents(queued <-chan queue.Event) {
+	for event := range queued {
+		s.events.Publish(events.TypeQueue, events.QueueChange{
+			Kind:     event.Kind,
+			DID:      event.Item.User.DID,
+			Handle:   event.Item.User.Handle,
+			Priority: event.Item.Priority,
+			Attempts: event.Item.Attempts,
+		})
+	}
+}
+
+// rateLimited publishes that a limit was reached and the service waits until
+// the given time. Each wait is recorded once in the history as a rate-limit
+// incident, however often the waiting loop reports it.
+func (s *Service) rateLimited(limit string, until time.Time) {
+	s.events.Publish(events.TypeRateLimit, events.RateLimit{Limit: limit, Until: until})
+
+	s.mu.Lock()
+	recorded := s.limited[limit].Equal(until)
+	s.limited[limit] = until
+	s.mu.Unlock()
+	if recorded {
+		return
+	}
+
+	event := models.FollowEvent{
+		Actor:     s.eventActor(),
+		Action:    models.ActionRateLimit,
+		Strategy:  limit,
+		Result:    models.ResultFailed,
+		Error:     "waiting until " + until.Format(time.RFC3339),
+		CreatedAt: time.Now(),
+	}
+	if err := s.db.RecordEvent(s.ctx, event); err != nil {
+		s.logger.Error("Failed to record the %s rate limit: %v", limit, err)
+	}
+}
+
+// recordDecision appends a user deliberately skipped or blocklisted to the
+// history, with the reason as the event's Error. Dry runs keep their skips
+// in the dry-run report instead.
+func (s *Service) recordDecision(user models.TargetUser, action, strategy, reason string) {
+	if s.DryRun() && action == models.ActionSkip {
+		return
+	}
+	event := models.FollowEvent{
+		Actor:     s.eventActor(),
+		DID:       user.DID,
+		Handle:    user.Handle,
+		Action:    action,
+		Strategy:  strategy,
+		Result:    models.ResultSuccess,
+		Error:     reason,
+		CreatedAt: time.Now(),
+	}
+	if err := s.db.RecordEvent(s.ctx, event); err != nil {
+		s.logger.Error("Failed to record %s event for %s: %v", action, user.Handle, err)
+	}
+	s.events.Publish(events.TypeAction, event)
+}
+
+// eventActor returns the DID of the account events are recorded for: the
+// verified session's, or the one the database is bound to before a login
+func (s *Service) eventActor() string {
+	s.mu.Lock()
+	actor := s.actor
+	s.mu.Unlock()
+	if actor != "" {
+		return actor
+	}
+
+	actor, err := s.accountDID()
+	if err != nil {
+		s.logger.Error("Failed to load the account DID for the history: %v", err)
+	}
+	return actor
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// reconcileInterval is how often the daemon checks who followed back
+const reconcileInterval = 6 * time.Hour
+
+// FollowBackReport summarizes one follow-back reconciliation
+type FollowBackReport struct {
+	Followers    int // accounts following me
+	Followed     int // accounts I followed that were checked
+	FollowedBack int // of those, how many follow me now
+	New          int // newly seen following back
+	Lost         int // followed back before but no longer do
+	Mutuals      int // accounts we follow each other with, still followed by me
+}
+
+// ReconcileFollowBacks syncs my followers and marks every account I followed
+// as following back or not, and as a mutual while I still follow them. The
+// first time a follow back is seen is kept in FollowedBackAt even if the
+// account later stops following me.
+func (s *Service) ReconcileFollowBacks(session *models.Session) (*FollowBackReport, error) {
+	count, err := s.SyncFollowers(session)
+	if err != nil {
+		return nil, err
+	}
+
+	followed := true
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{Followed: &followed}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FollowBackReport{Followers: count, Followed: len(users)}
+	now := time.Now()
+	var changed []models.TargetUser
+	for _, user := range users {
+		following := s.followsMe(user.DID)
+		if following {
+			report.FollowedBack++
+		}
+		mutual := following && user.UnfollowedAt.IsZero()
+		if mutual {
+			report.Mutuals++
+		}
+		if following == user.FollowedBack && mutual == user.Mutual {
+			continue
+		}
+
+		if following != user.FollowedBack {
+			if following {
+				report.New++
+				if user.FollowedBackAt.IsZero() {
+					user.FollowedBackAt = now
+				}
+			} else {
+				report.Lost++
+				user.UnfollowedMeAt = now
+			}
+		}
+		user.FollowedBack = following
+		user.Mutual = mutual
+		changed = append(changed, user)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, changed); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("%d of %d followed accounts follow back (%d new, %d lost), %d mutuals",
+		report.FollowedBack, report.Followed, report.New, report.Lost, report.Mutuals)
+	return report, nil
+}
+
+// followsMe reports whether a DID is in the followers snapshot
+func (s *Service) followsMe(did string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.followers[did]
+}
+
+// RunFollowBackReconciliation reconciles follow backs every few hours and
+// then queues auto-unfollows from the fresh state. The daemon reconciles on
+// startup, so the first run waits a full interval.
+func (s *Service) RunFollowBackReconciliation(session *models.Session) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for s.wait(ticker) {
+		if _, err := s.ReconcileFollowBacks(session); err != nil {
+			s.alert("Failed to reconcile follow backs: %v", err)
+			continue
+		}
+		if _, err := s.QueueAutoUnfollows(session); err != nil {
+			s.alert("Failed to queue auto-unfollows: %v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+const (
+	// queueStallGrace is how long past its expected return the follow queue
+	// processor may be before it counts as stuck, covering a slow follow
+	queueStallGrace = 5 * time.Minute
+
+	// sessionCheckInterval is how long a session check is reused, so frequent
+	// probes don't each call the API
+	sessionCheckInterval = time.Minute
+
+	// healthTimeout bounds the database check of a health probe
+	healthTimeout = 5 * time.Second
+)
+
+// heartbeat records that the follow queue processor is running and will be
+// back within d
+func (s *Service) heartbeat(d time.Duration) {
+	s.mu.Lock()
+	s.queueDeadline = time.Now().Add(d + queueStallGrace)
+	s.mu.Unlock()
+}
+
+// idle is sleep for the follow queue processor, which tells the health
+// check how long it will be away
+func (s *Service) idle(d time.Duration) bool {
+	s.heartbeat(d)
+	return s.sleep(d)
+}
+
+// Health checks the database, the session the follow queue processor uses
+// and whether the processor is still going round. The daemon is live unless
+// the database can't be reached or the processor is stuck; it is ready once
+// every check passes.
+func (s *Service) Health() models.Health {
+	health := models.Health{
+		Database: s.checkDatabase(),
+		Session:  s.checkSession(),
+		Queue:    models.HealthCheck{OK: true},
+	}
+
+	s.mu.Lock()
+	deadline := s.queueDeadline
+	s.mu.Unlock()
+
+	started := !deadline.IsZero()
+	stalled := started && time.Now().After(deadline) && !s.stopped()
+	switch {
+	case !started:
+		health.Queue = models.HealthCheck{Error: "follow queue processor not started"}
+	case stalled:
+		health.Queue = models.HealthCheck{Error: "follow queue processor stalled since " + deadline.Format(time.RFC3339)}
+	}
+
+	health.Live = health.Database.OK && !stalled
+	health.Ready = health.Database.OK && health.Session.OK && health.Queue.OK
+	return health
+}
+
+// checkDatabase pings the store
+func (s *Service) checkDatabase() models.HealthCheck {
+	ctx, cancel := context.WithTimeout(s.ctx, healthTimeout)
+	defer cancel()
+
+	if err := s.db.Ping(ctx); err != nil {
+		return models.HealthCheck{Error: err.Error()}
+	}
+	return models.HealthCheck{OK: true}
+}
+
+// checkSession reports whether the follow queue processor's session is still
+// accepted. The API is asked at most every sessionCheckInterval.
+func (s *Service) checkSession() models.HealthCheck {
+	s.mu.Lock()
+	session, check, at := s.session, s.sessionCheck, s.sessionAt
+	s.mu.Unlock()
+
+	if session == nil {
+		return models.HealthCheck{Error: "not logged in"}
+	}
+	switch expiry := session.Expiry(); {
+	case !expiry.IsZero() && time.Now().After(expiry):
+		return models.HealthCheck{Error: "access token expired at " + expiry.Format(time.RFC3339)}
+	case time.Since(at) < sessionCheckInterval:
+		return check
+	}
+
+	check = models.HealthCheck{OK: true}
+	if err := s.api.GetSession(session); err != nil {
+		check = models.HealthCheck{Error: err.Error()}
+	}
+
+	s.mu.Lock()
+	s.sessionCheck, s.sessionAt = check, time.Now()
+	s.mu.Unlock()
+	return check
+}
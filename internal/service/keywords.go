@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/score"
+)
+
+const (
+	// keywordInterval is how often the daemon searches the configured keywords
+	keywordInterval = 6 * time.Hour
+	// keywordWindow is how far back a keyword search looks
+	keywordWindow = 24 * time.Hour
+	// keywordPages bounds how many pages of posts one keyword search reads
+	keywordPages = 3
+	// keywordBatch bounds how many authors one daemon run queues per keyword
+	keywordBatch = 50
+)
+
+// authorActivity is what a keyword search saw of one author
+type authorActivity struct {
+	posts      int           // matching posts
+	engagement int           // likes, reposts and replies on them
+	latest     time.Time     // newest matching post
+	seen       []models.Post // the matching posts
+}
+
+// signals returns the scoring signals of an author's profile and posts
+func (a authorActivity) signals(profile models.Profile) score.Signals {
+	signals := profileSignals(profile)
+	signals.LastPost = a.latest
+	if a.posts > 0 {
+		signals.Engagement = float64(a.engagement) / float64(a.posts)
+	}
+	return signals
+}
+
+// keywordStrategy discovers the authors of recent posts about keywords
+type keywordStrategy struct {
+	s        *Service
+	keywords []string
+	filter   CandidateFilter
+	base     int
+	limit    int
+	counts   DiscoveryResult // of the last run, before the service's checks
+}
+
+// KeywordStrategy returns a strategy that searches the posts of the last day
+// for each keyword or hashtag and returns their authors that pass the filter,
+// with the source "search:<keyword>" and their score, from their profile and matching posts,
+// added to the base priority. Authors with the most engagement come first,
+// and at most limit are returned per keyword (0 for all of them).
+func (s *Service) KeywordStrategy(keywords []string, filter CandidateFilter, base, limit int) Strategy {
+	return &keywordStrategy{s: s, keywords: keywords, filter: filter, base: base, limit: limit}
+}
+
+func (k *keywordStrategy) Name() string {
+	return models.SourceSearch
+}
+
+func (k *keywordStrategy) Schedule() time.Duration {
+	return keywordInterval
+}
+
+func (k *keywordStrategy) Discover(ctx context.Context, session *models.Session) ([]models.TargetUser, error) {
+	k.counts = DiscoveryResult{}
+	known, err := k.s.knownAccounts(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.TargetUser
+	since := time.Now().Add(-keywordWindow)
+	for _, keyword := range k.keywords {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		activity, err := k.s.searchAuthors(session, keyword, since)
+		if err != nil {
+			return nil, err
+		}
+
+		var dids []string
+		for did := range activity {
+			k.counts.Scanned++
+			if known[did] || k.s.isBlocked(models.TargetUser{DID: did}) {
+				k.counts.Skipped++
+				continue
+			}
+			known[did] = true
+			dids = append(dids, did)
+		}
+		sort.Slice(dids, func(i, j int) bool {
+			return activity[dids[i]].engagement > activity[dids[j]].engagement
+		})
+
+		priority := func(profile models.Profile) int {
+			return k.base + k.s.weights.Score(activity[profile.Did].signals(profile), time.Now())
+		}
+		found, err := k.s.profileCandidates(session, dids, k.filter, models.NewSource(models.SourceSearch, keyword), priority, k.limit, &k.counts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].ApplyPosts(activity[found[i].DID].seen)
+		}
+		k.s.logger.Info("Found %d new authors posting about %s", len(found), keyword)
+		users = append(users, found...)
+	}
+	return users, nil
+}
+
+// DiscoverByKeywords runs a keyword strategy once, saving and queuing up to
+// limit (0 for all) authors per keyword that pass the filter
+func (s *Service) DiscoverByKeywords(session *models.Session, keywords []string, filter CandidateFilter, base, limit int) (*DiscoveryResult, error) {
+	strategy := &keywordStrategy{s: s, keywords: keywords, filter: filter, base: base, limit: limit}
+	result, err := s.Discover(s.stop, session, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.counts.merge(result), nil
+}
+
+// searchAuthors reads up to keywordPages pages of recent posts for a keyword
+// and sums them up per author DID
+func (s *Service) searchAuthors(session *models.Session, keyword string, since time.Time) (map[string]authorActivity, error) {
+	activity := make(map[string]authorActivity)
+	cursor := ""
+	for page := 0; page < keywordPages; page++ {
+		posts, next, err := s.api.SearchPosts(session, keyword, since, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search posts for %s: %w", keyword, err)
+		}
+		for _, post := range posts {
+			a := activity[post.Author.Did]
+			a.posts++
+			a.engagement += post.Engagement()
+			if post.IndexedAt.After(a.latest) {
+				a.latest = post.IndexedAt
+			}
+			a.seen = append(a.seen, post)
+			activity[post.Author.Did] = a
+		}
+		if next == "" || len(posts) == 0 {
+			break
+		}
+		cursor = next
+	}
+	return activity, nil
+}
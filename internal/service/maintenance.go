@@ -0,0 +1,41 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/db"
+)
+
+// maintenanceInterval is how often the daemon maintains the database
+const maintenanceInterval = 6 * time.Hour
+
+// MaintainDatabase refreshes planner statistics and vacuums the database
+// when enough of it is free space
+func (s *Service) MaintainDatabase() (*db.MaintenanceReport, error) {
+	start := time.Now()
+	report, err := s.db.Maintain(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Vacuumed {
+		s.logger.Info("Vacuumed database (%d of %d pages free) in %s",
+			report.FreePages, report.TotalPages, time.Since(start).Round(time.Millisecond))
+	} else {
+		s.logger.Debug("Analyzed database in %s", time.Since(start).Round(time.Millisecond))
+	}
+	return report, nil
+}
+
+// RunMaintenance maintains the database every few hours. The first run
+// waits a full interval so it stays out of the way of startup work.
+func (s *Service) RunMaintenance() {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for s.wait(ticker) {
+		if _, err := s.MaintainDatabase(); err != nil {
+			s.logger.Error("Failed to maintain database: %v", err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// AccountLookup is an account looked up to be added by hand, with what is
+// known about my relationship with it
+type AccountLookup struct {
+	User      models.TargetUser
+	Stored    bool   // the account was already in the database
+	Following bool   // I follow it already
+	FollowsMe bool   // it follows me
+	Blocked   string // a models.IsBlockRejection reason, empty if neither blocks the other
+}
+
+// LookUpAccount resolves a handle or DID and fetches its profile, for adding
+// the account by hand. A stored account keeps its state and gets the fresh
+// profile; a new one is treated as an imported target.
+func (s *Service) LookUpAccount(session *models.Session, subject string) (*AccountLookup, error) {
+	subject = normalizeSubject(subject)
+	if subject == "" {
+		return nil, fmt.Errorf("no account given")
+	}
+
+	profile, err := s.resolveProfile(session, subject)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up %s: %w", subject, err)
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	now := time.Now()
+	lookup := &AccountLookup{
+		User: models.TargetUser{
+			Handle:   subject,
+			DID:      profile.Did,
+			SavedOn:  now,
+			Source:   models.SourceImport,
+			Priority: DefaultTargetPriority,
+		},
+	}
+	for _, user := range users {
+		if user.DID == profile.Did {
+			lookup.User, lookup.Stored = user, true
+			break
+		}
+	}
+	lookup.User.ApplyProfile(*profile)
+	lookup.User.LastChecked = now
+
+	s.mu.Lock()
+	lookup.Following = s.followed[profile.Did] || profile.Viewer.Following != ""
+	s.mu.Unlock()
+	lookup.FollowsMe = profile.Viewer.FollowedBy != ""
+	switch viewer := profile.Viewer; {
+	case viewer.BlockedBy:
+		lookup.Blocked = models.RejectBlockedBy
+	case viewer.Blocking != "" || viewer.BlockingByList != nil:
+		lookup.Blocked = models.RejectBlocking
+	}
+	return lookup, nil
+}
+
+// AddAccount queues a looked-up account, or follows it right away when now is
+// set. Unlike discovered accounts, it skips the targeting filters, since it
+// was picked by hand; the blocklist, blocks and refollow cooldown still apply.
+// A follow made now skips the pacing between follows, but not the account
+// pause, the ratio guard, the active hours, the rate limit or the caps, and
+// counts toward them.
+func (s *Service) AddAccount(session *models.Session, lookup AccountLookup, now bool) error {
+	user := lookup.User
+	switch {
+	case lookup.Following || user.Followed:
+		return fmt.Errorf("%s is already followed", user.Handle)
+	case s.isBlocked(user):
+		return fmt.Errorf("%s is blocklisted", user.Handle)
+	case lookup.Blocked != "":
+		return fmt.Errorf("%s can't be followed: %s", user.Handle, lookup.Blocked)
+	}
+	if until := user.CooldownUntil(s.config.RefollowCooldown); until.After(time.Now()) {
+		return fmt.Errorf("%s was unfollowed recently, cooling down until %s", user.Handle, until.Format("2006-01-02 15:04"))
+	}
+	if now {
+		if err := s.manualFollowHeld(session); err != nil {
+			return err
+		}
+	}
+
+	user.Rejection = ""
+	user.NotBefore = time.Time{}
+	if err := s.db.SaveUser(s.ctx, user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+	s.queue.Remove(user.DID)
+
+	if !now {
+		priority := user.Priority + s.growthBonus(user.DID)
+		for _, item := range s.queue.Push(user, priority) {
+			s.logger.Info("Queue is full, evicted %s (priority: %d)", item.User.Handle, item.Priority)
+			s.recordEvent(item.User, models.ActionEvict, queueStrategy, nil)
+			if item.User.DID == user.DID {
+				return fmt.Errorf("the queue is full of higher priority users")
+			}
+		}
+		s.logger.Info("Added %s to the queue by hand (priority: %d)", user.Handle, priority)
+		return nil
+	}
+
+	s.logger.Info("Following %s by hand", user.Handle)
+	skipped, err := s.processFollowItem(session, &models.FollowQueueItem{User: user, Priority: user.Priority})
+	if err == nil && skipped != "" {
+		return fmt.Errorf("%s can't be followed: %s", user.Handle, skipped)
+	}
+	return err
+}
+
+// manualFollowHeld returns why a follow by hand can't be made now, or nil if
+// it can
+func (s *Service) manualFollowHeld(session *models.Session) error {
+	now := time.Now()
+	if s.isPaused(session) {
+		return fmt.Errorf("account is paused; resume it to follow")
+	}
+	if s.ratioGuardTripped(session) {
+		return fmt.Errorf("follows are held while I follow too many accounts per follower")
+	}
+	if next := s.NextActive(now); next.After(now) {
+		return fmt.Errorf("outside active hours; follows resume %s", next.Format("Mon 15:04"))
+	}
+	if next := s.limiter.Next(now); next.After(now) {
+		return fmt.Errorf("follow rate limit reached; try again in %s", next.Sub(now).Round(time.Second))
+	}
+	reset, err := s.followCapReset(now)
+	if err != nil {
+		return fmt.Errorf("failed to check the follow caps: %w", err)
+	}
+	if reset.After(now) {
+		return fmt.Errorf("daily or weekly follow cap reached; follows resume %s", reset.Format("Mon 15:04"))
+	}
+	return nil
+}
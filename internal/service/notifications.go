@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/digest"
+	"bsky_follower/internal/events"
+	"bsky_follower/internal/notify"
+	"bsky_follower/internal/stats"
+)
+
+const (
+	// alertInterval is how long the same kind of error stays quiet after it
+	// was sent, so a failing loop doesn't flood the channel
+	alertInterval = time.Hour
+
+	// summaryInterval is the period each daily summary covers
+	summaryInterval = 24 * time.Hour
+
+	// summaryCheckInterval is how often the daemon checks whether the daily
+	// summary is due
+	summaryCheckInterval = time.Hour
+
+	// summarySentSetting records when the last daily summary was sent
+	summarySentSetting = "summary_sent_at"
+)
+
+// Notifies reports whether any notification integration is configured
+func (s *Service) Notifies() bool {
+	return len(s.notifiers) > 0
+}
+
+// notify sends a message through every configured notifier. Failures are
+// logged and otherwise ignored.
+func (s *Service) notify(message string) {
+	if len(s.notifiers) == 0 {
+		return
+	}
+	if err := notify.SendAll(s.ctx, s.notifiers, message); err != nil {
+		s.logger.Error("Failed to send notification: %v", err)
+	}
+}
+
+// alert logs an error, publishes it on the event stream and sends it as a
+// notification, unless an error with the same format was sent within
+// alertInterval
+func (s *Service) alert(format string, args ...interface{}) {
+	s.logger.Error(format, args...)
+	s.events.Publish(events.TypeAlert, events.Alert{Message: fmt.Sprintf(format, args...)})
+	if len(s.notifiers) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	last := s.alerted[format]
+	due := time.Since(last) >= alertInterval
+	if due {
+		s.alerted[format] = time.Now()
+	}
+	s.mu.Unlock()
+
+	if due {
+		s.notify("⚠️ " + fmt.Sprintf(format, args...))
+	}
+}
+
+// SendDailySummary renders the configured digest template over the last day
+// and sends it through every notifier
+func (s *Service) SendDailySummary() error {
+	tmpl, err := digest.Load(s.config.DigestTemplate)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	summary, err := stats.Compute(s.ctx, s.db, now.Add(-summaryInterval))
+	if err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if err := digest.Render(&text, tmpl, summary); err != nil {
+		return err
+	}
+	if err := notify.SendAll(s.ctx, s.notifiers, text.String()); err != nil {
+		return err
+	}
+	return s.db.SetSetting(s.ctx, summarySentSetting, now.Format(time.RFC3339))
+}
+
+// RunDailySummary sends the daily summary whenever a day has passed since
+// the last one, checking every summaryCheckInterval until Stop. The time of
+// the last summary is stored, so restarts don't send extra ones.
+func (s *Service) RunDailySummary() {
+	ticker := time.NewTicker(summaryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.due(summarySentSetting, summaryInterval) {
+			if err := s.SendDailySummary(); err != nil {
+				s.logger.Error("Failed to send the daily summary: %v", err)
+			}
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
+
+// due reports whether interval has passed since the time stored in a setting,
+// or the setting was never stored
+func (s *Service) due(setting string, interval time.Duration) bool {
+	sent, err := s.db.GetSetting(s.ctx, setting)
+	if err != nil {
+		s.logger.Error("Failed to load setting %s: %v", setting, err)
+		return false
+	}
+	if sent == "" {
+		return true
+	}
+	at, err := time.Parse(time.RFC3339, sent)
+	return err != nil || time.Since(at) >= interval
+}
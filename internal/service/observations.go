@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/score"
+	"bsky_follower/internal/stats"
+)
+
+const (
+	// observationInterval is how often the daemon refreshes follower counts
+	observationInterval = time.Hour
+	// observationBatch bounds how many targets one refresh observes
+	observationBatch = 100
+	// observationAge is how long a follower count is considered fresh
+	observationAge = 24 * time.Hour
+	// profilesPerRequest is the API's getProfiles batch limit
+	profilesPerRequest = 25
+	// growthWindow is how far back the follower growth that adds to a
+	// pending target's priority is measured
+	growthWindow = 7 * 24 * time.Hour
+)
+
+// RefreshFollowerCounts re-fetches the profiles of up to limit pending
+// targets checked more than a day ago, highest priority first, updating the
+// users and recording each follower count in the follower history.
+// It returns the number of targets observed, and ErrStopped if Stop cut the
+// refresh short.
+func (s *Service) RefreshFollowerCounts(session *models.Session, limit int) (int, error) {
+	n, err := s.RefreshProfiles(s.stop, session, limit, nil)
+	if err != nil && s.stopped() {
+		return n, ErrStopped
+	}
+	return n, err
+}
+
+// RefreshProfiles is RefreshFollowerCounts with a context and a progress
+// callback, called with how many of the targets were fetched after each
+// batch. Each batch is saved as it arrives, so cancelling the context keeps
+// the work done so far; the count so far is returned with the context's
+// error.
+func (s *Service) RefreshProfiles(ctx context.Context, session *models.Session, limit int, progress func(done, total int)) (int, error) {
+	pending := false
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{
+		Followed:      &pending,
+		CheckedBefore: time.Now().Add(-observationAge),
+	}, limit, 0)
+	if err != nil {
+		return 0, err
+	}
+	if progress != nil {
+		progress(0, len(users))
+	}
+
+	observed := 0
+	for start := 0; start < len(users); start += profilesPerRequest {
+		if err := ctx.Err(); err != nil {
+			s.logger.Info("Observed follower counts of %d targets before stopping", observed)
+			return observed, err
+		}
+
+		batch := users[start:min(start+profilesPerRequest, len(users))]
+		actors := make([]string, len(batch))
+		byDID := make(map[string]models.TargetUser, len(batch))
+		for i, user := range batch {
+			actors[i] = user.DID
+			byDID[user.DID] = user
+		}
+
+		profiles, err := s.api.GetProfiles(session, actors)
+		if err != nil {
+			return observed, fmt.Errorf("failed to fetch profiles: %w", err)
+		}
+
+		now := time.Now()
+		var updated []models.TargetUser
+		var observations []models.FollowerObservation
+		for _, profile := range profiles {
+			user, ok := byDID[profile.Did]
+			if !ok {
+				continue
+			}
+			user.ApplyProfile(profile)
+			user.LastChecked = now
+			updated = append(updated, user)
+			observations = append(observations, models.FollowerObservation{
+				DID:        user.DID,
+				Followers:  profile.FollowersCount,
+				ObservedAt: now,
+			})
+		}
+
+		if err := s.db.SaveUsers(s.ctx, updated); err != nil {
+			return observed, err
+		}
+		if err := s.db.RecordFollowerCounts(s.ctx, observations); err != nil {
+			return observed, err
+		}
+		observed += len(observations)
+		if progress != nil {
+			progress(start+len(batch), len(users))
+		}
+	}
+
+	s.logger.Info("Observed follower counts of %d targets", observed)
+	if err := s.refreshGrowth(); err != nil {
+		s.logger.Error("Failed to score follower growth: %v", err)
+	}
+	return observed, nil
+}
+
+// refreshGrowth measures the follower growth of every observed target over
+// growthWindow and moves the queued ones by the change in what their growth
+// adds to their priority
+func (s *Service) refreshGrowth() error {
+	now := time.Now()
+	observations, err := s.db.LoadFollowerHistory(s.ctx, "", now.Add(-growthWindow))
+	if err != nil {
+		return fmt.Errorf("failed to load follower history: %w", err)
+	}
+	growth := make(map[string]float64)
+	for _, r := range stats.RisingTargets(observations) {
+		growth[r.DID] = r.GrowthPerDay
+	}
+
+	s.mu.Lock()
+	previous := s.growth
+	s.growth = growth
+	s.mu.Unlock()
+
+	changed := make(map[string]int)
+	for did, perDay := range growth {
+		changed[did] = s.growthScore(perDay) - s.growthScore(previous[did])
+	}
+	for did, perDay := range previous {
+		if _, ok := growth[did]; !ok {
+			changed[did] = -s.growthScore(perDay)
+		}
+	}
+	moved := 0
+	for did, change := range changed {
+		if change == 0 {
+			continue
+		}
+		if s.queue.Modify(did, func(item *models.FollowQueueItem) { item.Priority += change }) {
+			moved++
+		}
+	}
+	s.logger.Debug("Scored the follower growth of %d targets, moving %d queued", len(growth), moved)
+	return nil
+}
+
+// growthBonus returns what a target's follower growth adds to its priority
+// when it is queued
+func (s *Service) growthBonus(did string) int {
+	s.mu.Lock()
+	perDay := s.growth[did]
+	s.mu.Unlock()
+	return s.growthScore(perDay)
+}
+
+// growthScore returns the weighted score of a relative growth per day
+func (s *Service) growthScore(perDay float64) int {
+	return s.weights.Score(score.Signals{Growth: perDay}, time.Now())
+}
+
+// RunFollowerRefresh observes follower counts now and then every hour so
+// growth can be tracked over time
+func (s *Service) RunFollowerRefresh(session *models.Session) {
+	ticker := time.NewTicker(observationInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RefreshFollowerCounts(session, observationBatch); err != nil && !errors.Is(err, ErrStopped) {
+			s.logger.Error("Failed to refresh follower counts: %v", err)
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
+
+// RisingTargets returns the pending targets growing fastest over the window,
+// relative to their size, at most limit of them (all if limit is 0)
+func (s *Service) RisingTargets(window time.Duration, limit int) ([]stats.Rising, error) {
+	observations, err := s.db.LoadFollowerHistory(s.ctx, "", time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+
+	pending := false
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{Followed: &pending}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	handles := make(map[string]string, len(users))
+	for _, user := range users {
+		handles[user.DID] = user.Handle
+	}
+
+	var rising []stats.Rising
+	for _, r := range stats.RisingTargets(observations) {
+		handle, ok := handles[r.DID]
+		if !ok {
+			continue
+		}
+		r.Handle = handle
+		rising = append(rising, r)
+		if limit > 0 && len(rising) == limit {
+			break
+		}
+	}
+	return rising, nil
+}
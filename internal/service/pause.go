@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// pausePollInterval is how often paused processors check whether they may resume
+const pausePollInterval = time.Minute
+
+// accountDID returns the DID this database operates on: the configured one,
+// or the one bound on first login. It is empty before the first login.
+func (s *Service) accountDID() (string, error) {
+	if s.config.AccountDID != "" {
+		return s.config.AccountDID, nil
+	}
+	return s.db.GetSetting(s.ctx, accountDIDSetting)
+}
+
+// PauseAccount halts all writes for an account. A zero duration pauses until
+// ResumeAccount is called. Source is models.PauseManual or models.PauseAutomatic.
+func (s *Service) PauseAccount(did, reason, source string, duration time.Duration) error {
+	if did == "" {
+		var err error
+		if did, err = s.accountDID(); err != nil {
+			return err
+		}
+		if did == "" {
+			return fmt.Errorf("no account to pause; log in once first")
+		}
+	}
+
+	pause := models.AccountPause{
+		DID:      did,
+		Reason:   reason,
+		Source:   source,
+		PausedAt: time.Now(),
+	}
+	if duration > 0 {
+		pause.Until = pause.PausedAt.Add(duration)
+	}
+	if err := s.db.SavePause(s.ctx, pause); err != nil {
+		return err
+	}
+
+	s.logger.Info("Paused account %s (%s): %s", did, source, reason)
+	return nil
+}
+
+// ResumeAccount lifts a pause. An empty DID resumes this database's account.
+// Follow errors from before the resume no longer count towards an automatic
+// pause.
+func (s *Service) ResumeAccount(did string) error {
+	if did == "" {
+		var err error
+		if did, err = s.accountDID(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.DeletePause(s.ctx, did); err != nil {
+		return err
+	}
+	if err := s.db.SetSetting(s.ctx, errorsResetSetting, time.Now().Format(time.RFC3339)); err != nil {
+		return err
+	}
+
+	s.logger.Info("Resumed account %s", did)
+	return nil
+}
+
+// AccountPause returns the active pause for an account, or nil if it may
+// write. An empty DID checks this database's account.
+func (s *Service) AccountPause(did string) (*models.AccountPause, error) {
+	if did == "" {
+		var err error
+		if did, err = s.accountDID(); err != nil || did == "" {
+			return nil, err
+		}
+	}
+
+	pause, err := s.db.LoadPause(s.ctx, did)
+	if err != nil || !pause.Active(time.Now()) {
+		return nil, err
+	}
+	return pause, nil
+}
+
+// isPaused reports whether writes for the session's account are halted.
+// Lookup errors are treated as paused so writes fail safe.
+func (s *Service) isPaused(session *models.Session) bool {
+	pause, err := s.AccountPause(session.Did)
+	if err != nil {
+		s.logger.Error("Failed to check pause state of %s, treating as paused: %v", session.Handle, err)
+		return true
+	}
+	return pause != nil
+}
+
+// queuePausedSetting holds "true" while the follow queue is paused. It lives
+// in the database so the CLI and UI can pause a running daemon.
+const queuePausedSetting = "queue_paused"
+
+// PauseQueue stops the follow queue processor from taking items. Queued
+// items keep their priorities and retry times, and other jobs keep running.
+func (s *Service) PauseQueue() error {
+	if err := s.db.SetSetting(s.ctx, queuePausedSetting, "true"); err != nil {
+		return err
+	}
+	s.logger.Info("Paused follow queue processing")
+	return nil
+}
+
+// ResumeQueue lets the follow queue processor take items again
+func (s *Service) ResumeQueue() error {
+	if err := s.db.SetSetting(s.ctx, queuePausedSetting, ""); err != nil {
+		return err
+	}
+	s.logger.Info("Resumed follow queue processing")
+	return nil
+}
+
+// QueuePaused reports whether follow queue processing is paused
+func (s *Service) QueuePaused() (bool, error) {
+	value, err := s.db.GetSetting(s.ctx, queuePausedSetting)
+	if err != nil {
+		return false, err
+	}
+	return value == "true", nil
+}
+
+// queuePaused is QueuePaused for the processor, treating lookup errors as
+// paused so follows fail safe
+func (s *Service) queuePaused() bool {
+	paused, err := s.QueuePaused()
+	if err != nil {
+		s.logger.Error("Failed to check whether the queue is paused, treating as paused: %v", err)
+		return true
+	}
+	return paused
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Protect marks a handle or DID as protected from every unfollow and cleanup feature
+func (s *Service) Protect(subject, reason string) error {
+	subject = normalizeSubject(subject)
+	if subject == "" {
+		return fmt.Errorf("protected subject must not be empty")
+	}
+
+	if err := s.db.AddProtected(s.ctx, models.ProtectedAccount{
+		Subject: subject,
+		Reason:  reason,
+		AddedOn: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info("Protected %s", subject)
+	return nil
+}
+
+// Unprotect removes a handle or DID from the protected list
+func (s *Service) Unprotect(subject string) error {
+	subject = normalizeSubject(subject)
+	if err := s.db.RemoveProtected(s.ctx, subject); err != nil {
+		return err
+	}
+
+	s.logger.Info("Removed %s from protected accounts", subject)
+	return nil
+}
+
+// ProtectedAccounts returns all protected accounts
+func (s *Service) ProtectedAccounts() ([]models.ProtectedAccount, error) {
+	return s.db.LoadProtected(s.ctx)
+}
+
+// ImportProtected adds every entry from a list file to the protected list
+func (s *Service) ImportProtected(r io.Reader, defaultReason string) (int, error) {
+	return importList(r, defaultReason, s.Protect)
+}
+
+// IsProtected reports whether a user must never be unfollowed or pruned.
+// Lookup errors are treated as protected so cleanup fails safe.
+func (s *Service) IsProtected(user models.TargetUser) bool {
+	protected, err := s.db.IsProtected(s.ctx, user.DID, user.Handle)
+	if err != nil {
+		s.logger.Error("Failed to check protected list for %s, treating as protected: %v", user.Handle, err)
+		return true
+	}
+	return protected
+}
+
+// keepsMutual reports whether a user must not be unfollowed for being a
+// mutual: flagged at the last reconciliation or following me in the followers
+// snapshot, unless BSKY_UNFOLLOW_MUTUALS lets unfollows through
+func (s *Service) keepsMutual(user models.TargetUser) bool {
+	if s.config.UnfollowMutuals {
+		return false
+	}
+	return user.Mutual || s.isMutual(user)
+}
+
+// isMutual reports whether a user follows me and I follow them. Lookup
+// errors are treated as mutual so an automated unfollow fails safe.
+func (s *Service) isMutual(user models.TargetUser) bool {
+	mutual, err := s.db.IsMutual(s.ctx, user.DID, user.Handle)
+	if err != nil {
+		s.logger.Error("Failed to check mutual status of %s, treating as mutual: %v", user.Handle, err)
+		return true
+	}
+	return mutual
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// pruneInterval is how often the daemon prunes stale users
+const pruneInterval = 24 * time.Hour
+
+// PruneUsers removes users that were never followed and have not been
+// checked within age, archiving them instead when archive is set.
+// Protected accounts are kept. It returns the number of users pruned.
+func (s *Service) PruneUsers(age time.Duration, archive bool) (int, error) {
+	if age <= 0 {
+		return 0, fmt.Errorf("prune age must be positive")
+	}
+
+	count, err := s.db.PruneUsers(s.ctx, time.Now().Add(-age), archive)
+	if err != nil {
+		return 0, err
+	}
+
+	action := "Deleted"
+	if archive {
+		action = "Archived"
+	}
+	s.logger.Info("%s %d stale users not checked for %s", action, count, age)
+	return count, nil
+}
+
+// RunPruning prunes stale users now and then once a day, using the
+// configured age and archive setting
+func (s *Service) RunPruning() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PruneUsers(s.config.PruneAge, s.config.PruneArchive); err != nil {
+			s.logger.Error("Failed to prune stale users: %v", err)
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// lockedSource is a rand.Source64 safe for concurrent use, like the one
+// behind the top-level functions of math/rand. A rand.Rand on top of it is
+// safe to share between the follow queue, the unfollow pacer and the
+// discovery strategies, short of its Read method.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+// newLockedRand returns a rand.Rand safe for concurrent use, seeded with seed
+func newLockedRand(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)})
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/api"
+	"bsky_follower/internal/ratelimit"
+)
+
+// FollowRate returns the state of the adaptive follow rate limit
+func (s *Service) FollowRate() ratelimit.Status {
+	return s.limiter.Status()
+}
+
+// UnfollowRate returns the state of the adaptive unfollow rate limit
+func (s *Service) UnfollowRate() ratelimit.Status {
+	return s.unfollows.Status()
+}
+
+// observeRateLimit feeds the server's reports to the adaptive rate limits:
+// those on repository writes, which follows and unfollows are both spent
+// from, to the follow and unfollow limits, and the others to the lookup limit
+func (s *Service) observeRateLimit(limit api.RateLimit) {
+	limiters := map[string]*ratelimit.Controller{"lookups": s.reads}
+	if limit.IsWrite() {
+		limiters = map[string]*ratelimit.Controller{"follows": s.limiter, "unfollows": s.unfollows}
+	}
+
+	now := time.Now()
+	for spent, limiter := range limiters {
+		before := limiter.Status().Rate
+		if limit.Exceeded {
+			limiter.Throttle(limit.RetryAfter, now)
+		} else {
+			limiter.Observe(ratelimit.Observation{
+				Limit:     limit.Limit,
+				Remaining: limit.Remaining,
+				Reset:     limit.Reset,
+			}, now)
+		}
+
+		if after := limiter.Status(); after.Rate < before {
+			s.logger.Info("Server rate limit pressure on %s (%d of %d points left), slowing %s to %d per hour",
+				limit.Method, limit.Remaining, limit.Limit, spent, after.Rate)
+		}
+	}
+	// A refused request stops requests for a while; a restart must not
+	// forget that
+	if limit.Exceeded {
+		s.saveCheckpoint()
+	}
+}
+
+// awaitRead blocks until the lookup rate limit allows a request, reporting
+// false if stopped while waiting
+func (s *Service) awaitRead() bool {
+	for {
+		now := time.Now()
+		next := s.reads.Reserve(now)
+		if !next.After(now) {
+			return true
+		}
+		if !s.sleep(next.Sub(now)) {
+			return false
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+const (
+	// ratioCheckInterval is how long my follower and following counts are
+	// trusted before the ratio guard fetches them again
+	ratioCheckInterval = 15 * time.Minute
+
+	// ratioUnfollowPrefix names the campaigns the ratio guard creates
+	ratioUnfollowPrefix = "ratio-unfollow-"
+)
+
+// ratioState is the ratio guard's last reading of my profile
+type ratioState struct {
+	checked   time.Time
+	followers int
+	following int
+}
+
+// exceeded reports whether I follow more than limit accounts per follower
+func (r ratioState) exceeded(limit float64) bool {
+	return float64(r.following) > limit*float64(max(r.followers, 1))
+}
+
+// ratio returns how many accounts I follow per follower
+func (r ratioState) ratio() float64 {
+	return float64(r.following) / float64(max(r.followers, 1))
+}
+
+// ratioGuardTripped reports whether following should pause because I follow
+// more than BSKY_MAX_FOLLOW_RATIO accounts per follower. My counts are
+// fetched at most every ratioCheckInterval; if they can't be, the last
+// reading stands. When BSKY_RATIO_UNFOLLOW is set, a tripped guard also
+// queues unfollows to bring the ratio back.
+func (s *Service) ratioGuardTripped(session *models.Session) bool {
+	limit := s.config.MaxFollowRatio
+	if limit <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	state := s.ratio
+	s.mu.Unlock()
+
+	if time.Since(state.checked) >= ratioCheckInterval {
+		profile, err := s.api.GetProfile(session, session.Did)
+		if err != nil {
+			s.logger.Error("Failed to check my follower ratio: %v", err)
+		} else {
+			state = ratioState{checked: time.Now(), followers: profile.FollowersCount, following: profile.FollowsCount}
+			s.mu.Lock()
+			s.ratio = state
+			s.mu.Unlock()
+
+			if state.exceeded(limit) && s.config.RatioUnfollow {
+				if _, err := s.queueRatioUnfollows(session, state); err != nil {
+					s.logger.Error("Failed to queue unfollows for the follower ratio: %v", err)
+				}
+			}
+		}
+	}
+
+	if !state.exceeded(limit) {
+		return false
+	}
+	s.logger.Info("Following %d accounts with %d followers (%.2f per follower, limit %.2f), pausing follows",
+		state.following, state.followers, state.ratio(), limit)
+	return true
+}
+
+// queueRatioUnfollows starts an unfollow campaign for as many of my oldest
+// follows that never followed back as it takes to bring the ratio back to
+// the limit. Protected accounts, accounts already in a campaign and follows
+// made outside the app are left out. Nothing is queued while an earlier
+// ratio campaign is unfinished. It returns the number of accounts queued.
+func (s *Service) queueRatioUnfollows(session *models.Session, state ratioState) (int, error) {
+	campaigns, err := s.db.LoadUnfollowCampaigns(s.ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, campaign := range campaigns {
+		if strings.HasPrefix(campaign.Name, ratioUnfollowPrefix) && campaign.CompletedAt.IsZero() {
+			return 0, nil
+		}
+	}
+
+	excess := state.following - int(math.Floor(s.config.MaxFollowRatio*float64(max(state.followers, 1))))
+	if excess <= 0 {
+		return 0, nil
+	}
+
+	followed, followedBack, unfollowed := true, false, false
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{
+		Followed:     &followed,
+		FollowedBack: &followedBack,
+		Unfollowed:   &unfollowed,
+	}, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].FollowDate.Before(users[j].FollowDate) })
+
+	targeted, err := s.campaignTargets()
+	if err != nil {
+		return 0, err
+	}
+
+	var subjects []string
+	for _, user := range users {
+		if len(subjects) == excess {
+			break
+		}
+		if user.FollowDate.IsZero() || targeted[user.DID] || s.followsMe(user.DID) || s.IsProtected(user) || s.keepsMutual(user) {
+			continue
+		}
+		subjects = append(subjects, user.DID)
+	}
+	if len(subjects) == 0 {
+		return 0, nil
+	}
+
+	if _, _, err := s.StartUnfollowCampaign(session, ratioUnfollowPrefix+time.Now().Format("2006-01-02-1504"), subjects); err != nil {
+		return 0, err
+	}
+	return len(subjects), nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/notify"
+	"bsky_follower/internal/report"
+)
+
+const (
+	// reportInterval is the period each weekly growth report covers
+	reportInterval = 7 * 24 * time.Hour
+
+	// reportCheckInterval is how often the daemon checks whether the weekly
+	// report is due
+	reportCheckInterval = time.Hour
+
+	// reportSentSetting records when the last weekly report was made
+	reportSentSetting = "report_sent_at"
+)
+
+// reportExtensions are the file extensions reports are written with, per format
+var reportExtensions = map[string]string{
+	report.FormatMarkdown: ".md",
+	report.FormatHTML:     ".html",
+}
+
+// GrowthReport collects the growth report for the period up to now
+func (s *Service) GrowthReport(period time.Duration) (*report.Report, error) {
+	now := time.Now()
+	return report.Build(s.ctx, s.db, now.Add(-period), now)
+}
+
+// SendWeeklyReport builds the growth report over the last week, writes it
+// to the report directory in every format when one is configured and sends
+// the Markdown version through every notifier
+func (s *Service) SendWeeklyReport() error {
+	r, err := s.GrowthReport(reportInterval)
+	if err != nil {
+		return err
+	}
+
+	if s.config.ReportDir != "" {
+		for _, format := range report.Formats {
+			path, err := s.writeReport(r, format)
+			if err != nil {
+				return err
+			}
+			s.logger.Info("Wrote the weekly growth report to %s", path)
+		}
+	}
+	if len(s.notifiers) > 0 {
+		var text strings.Builder
+		if err := report.Markdown(&text, r); err != nil {
+			return err
+		}
+		if err := notify.SendAll(s.ctx, s.notifiers, text.String()); err != nil {
+			return err
+		}
+	}
+	return s.db.SetSetting(s.ctx, reportSentSetting, r.To.Format(time.RFC3339))
+}
+
+// writeReport writes a report into the report directory, named after the
+// day it ends on, and returns the file's path
+func (s *Service) writeReport(r *report.Report, format string) (string, error) {
+	if err := os.MkdirAll(s.config.ReportDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create the report directory: %w", err)
+	}
+
+	path := filepath.Join(s.config.ReportDir, "growth-"+r.To.Format("2006-01-02")+reportExtensions[format])
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := report.Render(f, r, format); err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// RunWeeklyReport makes the weekly growth report whenever a week has passed
+// since the last one, checking every reportCheckInterval until Stop. The
+// time of the last report is stored, so restarts don't make extra ones.
+func (s *Service) RunWeeklyReport() {
+	ticker := time.NewTicker(reportCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.due(reportSentSetting, reportInterval) {
+			if err := s.SendWeeklyReport(); err != nil {
+				s.alert("Failed to make the weekly growth report: %v", err)
+			}
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/review"
+)
+
+// ReviewResult counts what applying a review's decisions did
+type ReviewResult struct {
+	Approved int `json:"approved"`
+	Rejected int `json:"rejected"`
+	Blocked  int `json:"blocked"`
+	Unknown  int `json:"unknown"` // handles that aren't stored
+	Mutuals  int `json:"mutuals"` // block decisions refused for mutuals
+}
+
+// Applied returns how many decisions were applied
+func (r ReviewResult) Applied() int {
+	return r.Approved + r.Rejected + r.Blocked
+}
+
+// ApplyReview applies a reviewer's decisions on exported candidates. A
+// rejected candidate stays stored, marked with a models.RejectReview
+// rejection, so discovery never finds it again and it is never queued; a
+// blocked one is blocklisted as well. Either leaves the follow queue. An
+// approval may set a new priority, stored and queued, and lifts an earlier
+// review rejection.
+func (s *Service) ApplyReview(decisions []review.Decision) (ReviewResult, error) {
+	var result ReviewResult
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load users: %w", err)
+	}
+	byHandle := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		byHandle[user.Handle] = user
+	}
+
+	var changed, requeued []models.TargetUser
+	for _, decision := range decisions {
+		user, ok := byHandle[decision.Handle]
+		if !ok {
+			result.Unknown++
+			continue
+		}
+
+		if decision.Approve {
+			result.Approved++
+			dirty := false
+			if decision.Priority > 0 && decision.Priority != user.Priority {
+				s.queue.Reprioritize(func(queued models.TargetUser) bool { return queued.DID == user.DID }, decision.Priority)
+				user.Priority = decision.Priority
+				dirty = true
+			}
+			if models.IsReviewRejection(user.Rejection) {
+				user.Rejection = ""
+				requeued = append(requeued, user)
+				dirty = true
+			}
+			if dirty {
+				changed = append(changed, user)
+			}
+			continue
+		}
+
+		notes := ""
+		if decision.Notes != "" {
+			notes = ": " + decision.Notes
+		}
+		// Only a block decision blocklists; a plain reject just rules the
+		// candidate out
+		if decision.Block {
+			reason := "blocked in review" + notes
+			if err := s.db.AddToBlocklist(s.ctx, models.BlocklistEntry{Subject: user.DID, Reason: reason, AddedOn: time.Now()}); err != nil {
+				if errors.Is(err, db.ErrMutual) {
+					s.logger.Info("Refused to blocklist mutual %s from the review", user.Handle)
+					result.Mutuals++
+					continue
+				}
+				return result, err
+			}
+			s.recordDecision(user, models.ActionBlock, reviewStrategy, reason)
+			result.Blocked++
+		} else {
+			s.recordDecision(user, models.ActionSkip, reviewStrategy, models.RejectReview+notes)
+			result.Rejected++
+		}
+
+		user.Rejection = models.RejectReview + notes
+		changed = append(changed, user)
+		s.queue.Remove(user.DID)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, changed); err != nil {
+		return result, fmt.Errorf("failed to save users: %w", err)
+	}
+	for _, user := range requeued {
+		s.AddToQueue(user, user.Priority)
+	}
+	s.logger.Info("Applied %d review decisions: %d approved, %d rejected, %d blocked",
+		result.Applied(), result.Approved, result.Rejected, result.Blocked)
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bsky_follower/internal/api"
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/review"
+)
+
+// nopLogger discards the service's logging
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Debug(string, ...interface{}) {}
+
+// newTestService creates a service over an empty in-memory store holding
+// users, with its follow queue loaded from them. It never reaches the network.
+func newTestService(t *testing.T, users ...models.TargetUser) *Service {
+	t.Helper()
+	store, err := db.NewMemoryStore(nopLogger{})
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	if err := store.SaveUsers(context.Background(), users); err != nil {
+		t.Fatalf("SaveUsers: %v", err)
+	}
+	s := NewService(&models.Config{}, api.NewClient(time.Second, nopLogger{}), store, nopLogger{})
+	t.Cleanup(func() { s.Close() })
+	if err := s.LoadQueue(); err != nil {
+		t.Fatalf("LoadQueue: %v", err)
+	}
+	return s
+}
+
+// queued reports whether a DID is in the follow queue
+func queued(s *Service, did string) bool {
+	for _, item := range s.Queue().Snapshot() {
+		if item.User.DID == did {
+			return true
+		}
+	}
+	return false
+}
+
+// storedUser loads a user by DID, failing the test if it isn't stored
+func storedUser(t *testing.T, s *Service, did string) models.TargetUser {
+	t.Helper()
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		t.Fatalf("LoadUsers: %v", err)
+	}
+	for _, user := range users {
+		if user.DID == did {
+			return user
+		}
+	}
+	t.Fatalf("%s is no longer stored", did)
+	return models.TargetUser{}
+}
+
+func TestApplyReview(t *testing.T) {
+	now := time.Now()
+	s := newTestService(t,
+		models.TargetUser{DID: "did:plc:approve", Handle: "approve.bsky.social", Priority: 1, SavedOn: now},
+		models.TargetUser{DID: "did:plc:reject", Handle: "reject.bsky.social", Priority: 1, SavedOn: now},
+		models.TargetUser{DID: "did:plc:block", Handle: "block.bsky.social", Priority: 1, SavedOn: now},
+		models.TargetUser{DID: "did:plc:mutual", Handle: "mutual.bsky.social", Followed: true, Mutual: true, SavedOn: now},
+	)
+	if !queued(s, "did:plc:reject") || !queued(s, "did:plc:block") {
+		t.Fatal("the candidates under review were not queued")
+	}
+
+	result, err := s.ApplyReview([]review.Decision{
+		{Handle: "approve.bsky.social", Approve: true, Priority: 4},
+		{Handle: "reject.bsky.social", Notes: "spam"},
+		{Handle: "block.bsky.social", Block: true},
+		{Handle: "mutual.bsky.social", Block: true},
+		{Handle: "unknown.bsky.social", Approve: true},
+	})
+	if err != nil {
+		t.Fatalf("ApplyReview: %v", err)
+	}
+	want := ReviewResult{Approved: 1, Rejected: 1, Blocked: 1, Unknown: 1, Mutuals: 1}
+	if result != want {
+		t.Errorf("ApplyReview = %+v, want %+v", result, want)
+	}
+
+	if got := storedUser(t, s, "did:plc:approve").Priority; got != 4 {
+		t.Errorf("approved priority = %d, want 4", got)
+	}
+	if items := s.Queue().Snapshot(); len(items) != 1 || items[0].User.DID != "did:plc:approve" || items[0].Priority != 4 {
+		t.Errorf("queue = %+v, want only the approved candidate at priority 4", items)
+	}
+
+	// Rejected candidates stay stored, so discovery knows them, and marked
+	if got := storedUser(t, s, "did:plc:reject").Rejection; got != models.RejectReview+": spam" {
+		t.Errorf("rejection = %q, want the review rejection with the notes", got)
+	}
+	if got := storedUser(t, s, "did:plc:block").Rejection; got != models.RejectReview {
+		t.Errorf("rejection = %q, want the review rejection", got)
+	}
+	known, err := s.knownAccounts(&models.Session{Did: "did:plc:me"})
+	if err != nil {
+		t.Fatalf("knownAccounts: %v", err)
+	}
+	if !known["did:plc:reject"] {
+		t.Error("discovery would find the rejected candidate again")
+	}
+	if blocked, _ := s.db.IsBlocked(s.ctx, "did:plc:block", ""); !blocked {
+		t.Error("the blocked candidate was not blocklisted")
+	}
+	if blocked, _ := s.db.IsBlocked(s.ctx, "did:plc:mutual", ""); blocked {
+		t.Error("the mutual was blocklisted")
+	}
+
+	// Reloading the queue, as a restart does, keeps the rejects out
+	if err := s.LoadQueue(); err != nil {
+		t.Fatalf("LoadQueue: %v", err)
+	}
+	if queued(s, "did:plc:reject") || queued(s, "did:plc:block") {
+		t.Error("a rejected candidate was queued again")
+	}
+
+	// A later approval lifts the rejection
+	if _, err := s.ApplyReview([]review.Decision{{Handle: "reject.bsky.social", Approve: true}}); err != nil {
+		t.Fatalf("ApplyReview: %v", err)
+	}
+	if got := storedUser(t, s, "did:plc:reject").Rejection; got != "" {
+		t.Errorf("rejection after approval = %q, want none", got)
+	}
+	if !queued(s, "did:plc:reject") {
+		t.Error("the approved candidate was not queued")
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+const (
+	// secondDegreeInterval is how often the daemon looks through the
+	// followers of my followers
+	secondDegreeInterval = 24 * time.Hour
+	// secondDegreeSample is how many of my followers one run looks at
+	secondDegreeSample = 20
+	// secondDegreeMinOverlap is how many sampled followers of mine an account
+	// must be followed by to be a candidate
+	secondDegreeMinOverlap = 2
+	// secondDegreeBatch bounds how many accounts one daemon run queues
+	secondDegreeBatch = 50
+	// affinityWeight is the most the overlap adds to a candidate's priority,
+	// as much as every profile signal together at the default weights
+	affinityWeight = 5
+)
+
+// secondDegreeStrategy discovers the accounts that many of my followers
+// follow
+type secondDegreeStrategy struct {
+	s          *Service
+	sample     int
+	minOverlap int
+	filter     CandidateFilter
+	base       int
+	limit      int
+	counts     DiscoveryResult // of the last run, before the service's checks
+}
+
+// SecondDegreeStrategy returns a strategy that samples up to sample of my
+// followers, reads the first page of each one's followers and returns the
+// accounts followed by at least minOverlap of them that pass the filter,
+// with the source "second-degree". Accounts with the most overlap come
+// first, and their score, from their profile and the share of the sample
+// they overlap with, is added to the base priority. At most limit are
+// returned (0 for all).
+func (s *Service) SecondDegreeStrategy(sample, minOverlap int, filter CandidateFilter, base, limit int) Strategy {
+	return &secondDegreeStrategy{s: s, sample: sample, minOverlap: max(minOverlap, 1), filter: filter, base: base, limit: limit}
+}
+
+func (d *secondDegreeStrategy) Name() string {
+	return models.SourceSecondDegree
+}
+
+func (d *secondDegreeStrategy) Schedule() time.Duration {
+	return secondDegreeInterval
+}
+
+func (d *secondDegreeStrategy) Discover(ctx context.Context, session *models.Session) ([]models.TargetUser, error) {
+	d.counts = DiscoveryResult{}
+	followers, err := d.s.db.LoadFollowers(d.s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load my followers: %w", err)
+	}
+	if len(followers) == 0 {
+		d.s.logger.Info("No followers stored yet, sync followers first")
+		return nil, nil
+	}
+
+	// The service's rng is safe to share, so the sample needs no lock
+	d.s.rng.Shuffle(len(followers), func(i, j int) { followers[i], followers[j] = followers[j], followers[i] })
+	if d.sample > 0 && len(followers) > d.sample {
+		followers = followers[:d.sample]
+	}
+
+	known, err := d.s.knownAccounts(session)
+	if err != nil {
+		return nil, err
+	}
+	mine := make(map[string]bool, len(followers))
+	for _, follower := range followers {
+		mine[follower.Did] = true
+	}
+
+	overlap := make(map[string]int)
+	for _, follower := range followers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, _, err := d.s.api.GetFollowers(session, follower.Did, "")
+		if err != nil {
+			d.s.logger.Error("Failed to fetch followers of %s: %v", follower.Handle, err)
+			continue
+		}
+		for _, actor := range page {
+			if !mine[actor.Did] {
+				overlap[actor.Did]++
+			}
+		}
+	}
+
+	var dids []string
+	for did, count := range overlap {
+		d.counts.Scanned++
+		if count < d.minOverlap {
+			d.counts.Filtered++
+			continue
+		}
+		if known[did] || d.s.isBlocked(models.TargetUser{DID: did}) {
+			d.counts.Skipped++
+			continue
+		}
+		dids = append(dids, did)
+	}
+	sort.Slice(dids, func(i, j int) bool {
+		if overlap[dids[i]] != overlap[dids[j]] {
+			return overlap[dids[i]] > overlap[dids[j]]
+		}
+		return dids[i] < dids[j]
+	})
+
+	priority := func(profile models.Profile) int {
+		affinity := math.Round(affinityWeight * float64(overlap[profile.Did]) / float64(len(followers)))
+		return d.base + d.s.weights.Score(profileSignals(profile), time.Now()) + int(affinity)
+	}
+	users, err := d.s.profileCandidates(session, dids, d.filter, d.Name(), priority, d.limit, &d.counts)
+	if err != nil {
+		return nil, err
+	}
+	d.s.logger.Info("Found %d accounts followed by at least %d of %d sampled followers", len(users), d.minOverlap, len(followers))
+	return users, nil
+}
+
+// DiscoverSecondDegree runs a second-degree strategy once, saving and queuing
+// up to limit (0 for all) accounts that pass the filter
+func (s *Service) DiscoverSecondDegree(session *models.Session, sample, minOverlap int, filter CandidateFilter, base, limit int) (*DiscoveryResult, error) {
+	strategy := &secondDegreeStrategy{s: s, sample: sample, minOverlap: max(minOverlap, 1), filter: filter, base: base, limit: limit}
+	result, err := s.Discover(s.stop, session, strategy)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.counts.merge(result), nil
+}
@@ -1,35 +1,99 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"bsky_follower/internal/api"
 	"bsky_follower/internal/db"
+	"bsky_follower/internal/dryrun"
+	"bsky_follower/internal/events"
 	"bsky_follower/internal/models"
+	"bsky_follower/internal/notify"
 	"bsky_follower/internal/queue"
+	"bsky_follower/internal/ratelimit"
+	"bsky_follower/internal/schedule"
+	"bsky_follower/internal/score"
+	"bsky_follower/internal/stats"
+	"bsky_follower/internal/targeting"
 )
 
 const (
-	maxFollowsPerHour = 50
-	maxRetries        = 3
-	retryDelay        = 5 * time.Minute
-	followCooldown    = 24 * time.Hour
+	maxRetries = 3
+
+	accountDIDSetting = "account_did"
+
+	// sessionExpiryMargin is how close to expiry a stored session is refreshed
+	sessionExpiryMargin = 5 * time.Minute
+
+	// queueStrategy is recorded for follows made by the queue processor
+	queueStrategy = "queue"
+
+	// blocklistStrategy is recorded for users added to the blocklist
+	blocklistStrategy = "blocklist"
+
+	// manualStrategy is recorded for unfollows made by hand from the UI
+	manualStrategy = "manual"
+
+	// reviewStrategy is recorded for decisions imported from a review
+	reviewStrategy = "review"
 )
 
+// ErrIdentityMismatch is returned when a session belongs to a different account
+// than the one this database and configuration were set up for
+var ErrIdentityMismatch = errors.New("session identity does not match the expected account")
+
 // Service represents the main application service
 type Service struct {
-	config     *models.Config
-	api        *api.Client
-	db         *db.Store
-	queue      *queue.Queue
-	followed   map[string]bool
-	mu         sync.Mutex
-	lastFollow time.Time
-	followCount int
-	followReset time.Time
-	logger     Logger
+	ctx           context.Context // parent of every store call
+	stop          context.Context // cancelled by Stop to end the background loops
+	cancel        context.CancelFunc
+	config        *models.Config
+	api           *api.Client
+	db            db.Store
+	queue         *queue.Queue
+	followed      map[string]bool    // keyed by DID
+	followers     map[string]bool    // accounts that follow me, keyed by DID
+	growth        map[string]float64 // relative follower growth per day of pending targets, guarded by mu
+	mu            sync.Mutex         // guards the maps above and follow counters; the queue locks itself
+	lastFollow    time.Time
+	nextFollow    time.Time             // paced time of the next follow
+	limiter       *ratelimit.Controller // adaptive follow rate limit
+	reads         *ratelimit.Controller // adaptive rate limit of enrichment lookups
+	unfollows     *ratelimit.Controller // adaptive unfollow rate limit
+	nextUnfollow  time.Time             // paced time of the next unfollow
+	inFlight      *models.TargetUser    // follow sent but not yet saved, for the checkpoint
+	checkpointing sync.Mutex            // serializes checkpoint saves
+	dryRun        atomic.Bool
+	simulated     dryrun.Report // dry-run report, guarded by mu
+	strategies    []Strategy    // guarded by mu
+	weights       score.Weights
+	pacer         queue.Pacer
+	unfollowPacer queue.Pacer
+	window        schedule.Window    // guarded by settings
+	targeting     targeting.Chain    // guarded by settings
+	settings      sync.RWMutex       // guards what ApplySetting changes at runtime
+	ratio         ratioState         // guarded by mu
+	session       *models.Session    // session of the follow queue processor, guarded by mu
+	actor         string             // DID of the verified account, recorded on events; guarded by mu
+	queueDeadline time.Time          // when the follow queue processor is due back, guarded by mu
+	sessionCheck  models.HealthCheck // last session health check, guarded by mu
+	sessionAt     time.Time          // when sessionCheck was made, guarded by mu
+	notifiers     []notify.Notifier
+	alerted       map[string]time.Time // last alert sent per message format, guarded by mu
+	limited       map[string]time.Time // end of the last recorded wait per limit, guarded by mu
+	renewing      sync.Mutex           // held while a session is renewed, so jobs don't renew it at once
+	events        *events.Bus
+	unsubscribe   func()     // ends the forwarding of queue events to the bus
+	rng           *rand.Rand // safe for concurrent use, see newLockedRand
+	logger        Logger
 }
 
 // Logger interface for logging
@@ -40,116 +104,896 @@ type Logger interface {
 }
 
 // NewService creates a new service instance
-func NewService(config *models.Config, apiClient *api.Client, dbStore *db.Store, logger Logger) *Service {
-	return &Service{
-		config:     config,
-		api:        apiClient,
-		db:         dbStore,
-		queue:      queue.NewQueue(),
-		followed:   make(map[string]bool),
-		logger:     logger,
-		followReset: time.Now(),
+func NewService(config *models.Config, apiClient *api.Client, dbStore db.Store, logger Logger) *Service {
+	s := &Service{
+		ctx:       context.Background(),
+		config:    config,
+		api:       apiClient,
+		db:        dbStore,
+		followed:  make(map[string]bool),
+		followers: make(map[string]bool),
+		notifiers: notify.FromConfig(config),
+		alerted:   make(map[string]time.Time),
+		limited:   make(map[string]time.Time),
+		events:    events.NewBus(),
+		logger:    logger,
+		limiter:   ratelimit.New(config.FollowsPerHour),
+		reads:     ratelimit.New(config.ReadsPerHour),
+		unfollows: ratelimit.New(config.UnfollowsPerHour),
+		rng:       newLockedRand(time.Now().UnixNano()),
+	}
+	s.stop, s.cancel = context.WithCancel(context.Background())
+	s.dryRun.Store(config.DryRun)
+	// The config was validated when it was loaded
+	s.weights, _ = score.FromMap(config.ScoreWeights)
+	s.window, _ = schedule.ParseWindow(config.ActiveHours, config.ActiveDays)
+	s.targeting = targeting.NewChain(config.Targeting)
+	s.pacer = queue.Pacer{
+		Distribution: config.Pacing,
+		Gap:          config.FollowGap,
+		Jitter:       config.FollowJitter,
+		PauseChance:  config.PauseChance,
+		PauseMin:     config.PauseMin,
+		PauseMax:     config.PauseMax,
+	}
+	s.unfollowPacer = queue.Pacer{
+		Distribution: config.Pacing,
+		Gap:          config.UnfollowGap,
+		Jitter:       config.FollowJitter,
 	}
+	s.registerConfigured()
+	apiClient.OnRateLimit(s.observeRateLimit)
+	s.queue = queue.NewQueue(queue.Options{
+		MaxSize:  config.MaxQueueSize,
+		Weights:  laneWeights(config),
+		Decay:    s.decay(),
+		OnExpire: s.expired,
+	})
+	queued, unsubscribe := s.queue.Subscribe()
+	s.unsubscribe = unsubscribe
+	go s.forwardQueueEvents(queued)
+	return s
 }
 
-// ProcessFollowQueue processes the follow queue
-func (s *Service) ProcessFollowQueue(session *models.Session) {
-	for {
-		if s.queue.Len() == 0 {
-			s.logger.Info("Queue is empty, waiting for new items")
-			time.Sleep(time.Minute)
-			continue
-		}
+// decay returns the configured decay policy for stale queue items
+func (s *Service) decay() queue.Decay {
+	return queue.Decay{After: s.config.DecayAfter, Every: s.config.DecayEvery, Expire: s.config.ExpireAge}
+}
 
-		item := s.queue.Peek()
-		if item == nil {
-			continue
+// expired records a queue item dropped by the decay policy
+func (s *Service) expired(item models.FollowQueueItem) {
+	s.logger.Info("Dropped stale queue item %s (saved %s)", item.User.Handle, item.User.SavedOn.Format("2006-01-02"))
+	s.recordEvent(item.User, models.ActionExpire, queueStrategy, nil)
+}
+
+// Login resumes the stored session for the account if it is still usable,
+// otherwise authenticates with the configured credentials. Either way the
+// session is verified to belong to the expected account and persisted.
+func (s *Service) Login() (*models.Session, error) {
+	if session := s.resumeSession(); session != nil {
+		return session, nil
+	}
+
+	session, err := s.api.Login(s.config.Identifier, s.config.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.VerifyIdentity(session); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SaveSession(s.ctx, session); err != nil {
+		s.logger.Error("Failed to persist session: %v", err)
+	}
+	return session, nil
+}
+
+// resumeSession returns the stored session for the expected account,
+// refreshing it if the access token is about to expire. It returns nil when
+// there is no usable session and a full login is needed.
+func (s *Service) resumeSession() *models.Session {
+	did, err := s.accountDID()
+	if err != nil || did == "" {
+		return nil
+	}
+
+	session, err := s.db.LoadSession(s.ctx, did)
+	if err != nil || session == nil {
+		return nil
+	}
+
+	if time.Until(session.ExpiresAt) < sessionExpiryMargin {
+		refreshed, err := s.api.RefreshSession(session)
+		if err != nil {
+			s.logger.Info("Stored session could not be refreshed, logging in again: %v", err)
+			return nil
+		}
+		session = refreshed
+		if err := s.db.SaveSession(s.ctx, session); err != nil {
+			s.logger.Error("Failed to persist refreshed session: %v", err)
 		}
+	}
 
-		// Check if we need to wait for the next try
-		if time.Now().Before(item.NextTry) {
-			time.Sleep(time.Second)
-			continue
+	if err := s.VerifyIdentity(session); err != nil {
+		s.logger.Error("Stored session failed identity check: %v", err)
+		return nil
+	}
+
+	s.logger.Info("Resumed stored session for %s", session.Handle)
+	return session
+}
+
+// Logout forgets the stored session for an account
+func (s *Service) Logout(session *models.Session) error {
+	return s.db.DeleteSession(s.ctx, session.Did)
+}
+
+// VerifyIdentity checks the session DID against the configured account DID and
+// the DID stored on first login, refusing to operate on a different account
+func (s *Service) VerifyIdentity(session *models.Session) error {
+	if s.config.AccountDID != "" && session.Did != s.config.AccountDID {
+		s.logger.Error("Session DID %s does not match configured DID %s", session.Did, s.config.AccountDID)
+		return fmt.Errorf("%w: logged in as %s (%s), expected %s",
+			ErrIdentityMismatch, session.Handle, session.Did, s.config.AccountDID)
+	}
+
+	stored, err := s.db.GetSetting(s.ctx, accountDIDSetting)
+	if err != nil {
+		return fmt.Errorf("failed to load stored account DID: %w", err)
+	}
+
+	if stored == "" {
+		s.logger.Info("Binding database to account %s (%s)", session.Handle, session.Did)
+		if err := s.db.SetSetting(s.ctx, accountDIDSetting, session.Did); err != nil {
+			return err
 		}
+	} else if stored != session.Did {
+		s.logger.Error("Session DID %s does not match stored DID %s", session.Did, stored)
+		return fmt.Errorf("%w: logged in as %s (%s), database belongs to %s",
+			ErrIdentityMismatch, session.Handle, session.Did, stored)
+	}
 
-		// Check rate limits
-		if s.followCount >= maxFollowsPerHour {
-			if time.Since(s.followReset) < time.Hour {
-				s.logger.Info("Rate limit reached, waiting for reset")
-				time.Sleep(time.Minute)
-				continue
-			}
-			s.followCount = 0
-			s.followReset = time.Now()
+	s.mu.Lock()
+	s.actor = session.Did
+	s.mu.Unlock()
+	return nil
+}
+
+// ProcessFollowQueue processes the follow queue until Stop. Stopping is only
+// checked between items, so a follow is never cut off before it is saved.
+func (s *Service) ProcessFollowQueue(session *models.Session) {
+	s.mu.Lock()
+	s.session = session
+	s.mu.Unlock()
+
+	for !s.stopped() {
+		s.heartbeat(0)
+		if step := s.FollowNext(session); step.Wait > 0 {
+			s.idle(step.Wait)
 		}
+	}
+}
 
-		// Check cooldown
-		if time.Since(s.lastFollow) < followCooldown {
-			s.logger.Info("Cooldown period active, waiting")
-			time.Sleep(time.Minute)
-			continue
+// FollowStep is what one pass over the follow queue did: it followed User,
+// failed to follow User with Err, skipped User for Skipped, or followed
+// nobody for Reason and asks to wait before the next pass
+type FollowStep struct {
+	User    *models.TargetUser
+	Err     error
+	Skipped string // why User was dropped rather than followed, e.g. blocklisted
+	Reason  string
+	Wait    time.Duration
+
+	// Idle is set when the queue won't move on its own soon: it is empty,
+	// nothing is due, it or the account is paused, it is outside the active
+	// hours or the ratio guard holds follows. The pacing, rate limit and caps
+	// only make it wait.
+	Idle bool
+}
+
+// FollowNext makes one pass over the follow queue: it checks every guard in
+// turn and follows the highest priority item that is due if they all allow
+// it. The daemon's processor and the UI's queue runs both go through it, so
+// a follow is never made past a guard.
+func (s *Service) FollowNext(session *models.Session) FollowStep {
+	wait := func(d time.Duration, idle bool, format string, args ...any) FollowStep {
+		return FollowStep{Reason: fmt.Sprintf(format, args...), Wait: d, Idle: idle}
+	}
+
+	if s.queue.Len() == 0 {
+		s.logger.Info("Queue is empty, waiting for new items")
+		return wait(time.Minute, true, "Queue is empty")
+	}
+
+	// Check whether queue processing is paused
+	if s.queuePaused() {
+		s.logger.Info("Follow queue is paused, waiting")
+		return wait(pausePollInterval, true, "Follow queue is paused")
+	}
+
+	item := s.queue.Peek()
+	if item == nil {
+		return wait(0, false, "Queue changed")
+	}
+
+	// Check if we need to wait for the next try
+	if time.Now().Before(item.NextTry) {
+		return wait(time.Second, true, "No items ready to process")
+	}
+
+	// Check whether the account is paused
+	if s.isPaused(session) {
+		s.logger.Info("Account is paused, waiting")
+		return wait(pausePollInterval, true, "Account is paused")
+	}
+
+	// Hold follows while I follow too many accounts per follower
+	if s.ratioGuardTripped(session) {
+		return wait(ratioCheckInterval, true, "Follows are held while I follow too many accounts per follower")
+	}
+
+	// Sleep through quiet hours
+	if next := s.NextActive(time.Now()); next.After(time.Now()) {
+		s.logger.Info("Outside active hours, sleeping until %s", next.Format("Mon 15:04"))
+		return wait(time.Until(next), true, "Outside active hours, follows resume %s", next.Format("Mon 15:04"))
+	}
+
+	// Renew the session before its access token expires
+	if err := s.ensureSession(session); err != nil {
+		s.alert("Failed to renew the session: %v", err)
+		return FollowStep{Err: fmt.Errorf("failed to renew the session: %w", err), Reason: "Session renewal failed", Wait: time.Minute}
+	}
+
+	// Check the adaptive rate limit
+	if next := s.limiter.Next(time.Now()); next.After(time.Now()) {
+		limit := "follows per hour"
+		if !s.limiter.Status().Blocked.IsZero() {
+			limit = "server"
 		}
+		s.logger.Info("Rate limit reached, waiting until %s", next.Format(time.RFC3339))
+		s.rateLimited(limit, next)
+		return wait(time.Until(next), false, "Waiting out the %s rate limit", limit)
+	}
+
+	// Wait for the paced gap after the last follow
+	s.mu.Lock()
+	next := s.nextFollow
+	s.mu.Unlock()
+	if gap := time.Until(next); gap > 0 {
+		s.logger.Info("Next follow in %s", gap.Round(time.Second))
+		return wait(gap, false, "Pacing follows")
+	}
+
+	// Check the daily and weekly caps
+	now := time.Now()
+	reset, err := s.followCapReset(now)
+	if err != nil {
+		s.logger.Error("Failed to check the follow caps: %v", err)
+		return FollowStep{Err: fmt.Errorf("failed to check the follow caps: %w", err), Reason: "Follow cap check failed", Wait: time.Minute}
+	}
+	if reset.After(now) {
+		s.logger.Info("Daily or weekly follow cap reached, waiting until %s", reset.Format(time.RFC3339))
+		s.rateLimited("follows per day or week", reset)
+		return wait(reset.Sub(now), false, "Daily or weekly follow cap reached")
+	}
+
+	// Process the item
+	if s.config.RandomSelection {
+		item = s.queue.PopWeighted(s.rng, time.Now())
+	} else {
+		item = s.queue.PopReady(time.Now())
+	}
+	if item == nil {
+		return wait(0, false, "Queue changed")
+	}
 
-		// Process the item
-		s.mu.Lock()
-		item = s.queue.Pop()
-		s.mu.Unlock()
+	// Hold the candidates of a campaign that made its follows for the day
+	hold, err := s.campaignHold(item.User, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to check the campaign of %s: %v", item.User.Handle, err)
+	}
+	if hold.After(time.Now()) {
+		s.logger.Debug("Campaign of %s is at its daily limit, holding until %s", item.User.Handle, hold.Format(time.RFC3339))
+		item.NextTry = hold
+		s.queue.Requeue(item)
+		return wait(0, false, "Campaign of %s is at its daily limit", item.User.Handle)
+	}
 
-		if err := s.processFollowItem(session, item); err != nil {
-			s.logger.Error("Failed to process follow item", "error", err)
-			if item.Attempts < maxRetries {
-				item.Attempts++
-				item.NextTry = time.Now().Add(retryDelay)
-				s.mu.Lock()
-				s.queue.Push(item.User, item.Priority)
-				s.mu.Unlock()
+	// Hold the lane of a source that spent its share of the budget
+	lane, hold, err := s.budgetHold(item.User, time.Now())
+	if err != nil {
+		s.logger.Error("Failed to check the follow budget of %s: %v", item.User.Handle, err)
+	}
+	if hold.After(time.Now()) {
+		s.logger.Info("The %s lane spent its share of the follow budget, holding it until %s", lane, hold.Format(time.RFC3339))
+		s.queue.Requeue(item)
+		s.queue.Hold(lane, hold)
+		return wait(0, false, "The %s lane spent its share of the follow budget", lane)
+	}
+
+	skipped, err := s.processFollowItem(session, item)
+	if err != nil {
+		s.logger.Error("Failed to process follow item: %v", err)
+		// The rate limiter holds the queue until the server allows
+		// follows again, so the refused follow doesn't count as an attempt
+		var limited *api.RateLimitError
+		if errors.As(err, &limited) {
+			s.queue.Requeue(item)
+			return wait(0, false, "The server refused the follow of %s for now", item.User.Handle)
+		}
+		// The token expired mid-run: renew it and retry the item
+		// without counting an attempt
+		if errors.Is(err, api.ErrExpiredToken) {
+			s.queue.Requeue(item)
+			if err := s.renewSession(session); err != nil {
+				s.alert("Failed to renew the session: %v", err)
+				return FollowStep{Err: fmt.Errorf("failed to renew the session: %w", err), Reason: "Session renewal failed", Wait: time.Minute}
 			}
+			return wait(0, false, "Session renewed")
+		}
+		s.checkErrorSpike(session)
+		if item.Attempts < maxRetries {
+			item.Attempts++
+			item.NextTry = time.Now().Add(s.retryBackoff().Delay(item.Attempts, s.rng))
+			s.queue.Requeue(item)
+		} else {
+			s.alert("Giving up on %s after %d attempts", item.User.Handle, item.Attempts+1)
+			s.queue.DeadLetter(item)
 		}
+		return FollowStep{User: &item.User, Err: err}
 	}
+	// A skip is no follow, so it doesn't count toward the throughput
+	if skipped != "" {
+		return FollowStep{User: &item.User, Skipped: skipped}
+	}
+	s.queue.Done(time.Now())
+	return FollowStep{User: &item.User}
+}
+
+// retryBackoff returns the configured backoff for failed follows
+func (s *Service) retryBackoff() queue.Backoff {
+	return queue.Backoff{Base: s.config.RetryBase, Cap: s.config.RetryCap}
 }
 
-// processFollowItem processes a single follow queue item
-func (s *Service) processFollowItem(session *models.Session, item *models.FollowQueueItem) error {
+// processFollowItem processes a single follow queue item. It returns why
+// the user was skipped instead of followed, empty if they were followed.
+func (s *Service) processFollowItem(session *models.Session, item *models.FollowQueueItem) (string, error) {
 	s.logger.Info("Processing follow for user: %s", item.User.Handle)
 
+	if s.isBlocked(item.User) {
+		s.logger.Info("Skipping blocklisted user: %s", item.User.Handle)
+		s.rejectSimulated(item.User, dryrun.FilterBlocklist, "blocklisted")
+		s.recordDecision(item.User, models.ActionSkip, queueStrategy, "blocklisted")
+		return "blocklisted", nil
+	}
+
+	// The local data can't know about blocks made on the server
+	reason, err := s.blockRejection(session, item.User)
+	if err != nil {
+		return "", fmt.Errorf("failed to check blocks: %w", err)
+	}
+	if reason != "" {
+		return reason, s.skipBlocked(item.User, reason)
+	}
+
+	if s.DryRun() {
+		if err := s.api.FollowUser(session, item.User.DID, true); err != nil {
+			return "", err
+		}
+		s.simulate(item.User, models.ActionFollow, queueStrategy)
+		// The simulated follow spends the pacing and rate limit, but the
+		// account isn't followed, so it stays out of the followed set
+		s.markPaced()
+		return "", nil
+	}
+
 	// Update user in database
 	item.User.LastChecked = time.Now()
-	if err := s.db.SaveUser(item.User); err != nil {
-		return fmt.Errorf("failed to save user: %w", err)
+	if err := s.db.SaveUser(s.ctx, item.User); err != nil {
+		return "", fmt.Errorf("failed to save user: %w", err)
 	}
 
-	// Follow the user
+	// Follow the user. The checkpoint holds the follow until it is saved,
+	// so a crash in between doesn't lose it or send it twice.
+	s.setInFlight(&item.User)
 	if err := s.api.FollowUser(session, item.User.DID, false); err != nil {
-		return fmt.Errorf("failed to follow user: %w", err)
+		s.setInFlight(nil)
+		s.recordEvent(item.User, models.ActionFollow, queueStrategy, err)
+		return "", fmt.Errorf("failed to follow user: %w", err)
 	}
+	s.recordEvent(item.User, models.ActionFollow, queueStrategy, nil)
+
+	s.markFollowed(item.User.DID)
+
+	item.User.Followed = true
+	item.User.FollowDate = time.Now()
+	if err := s.db.SaveUser(s.ctx, item.User); err != nil {
+		return "", err
+	}
+	s.setInFlight(nil)
+	return "", nil
+}
 
-	// Update follow status
+// markFollowed updates the followed set and the follow counters and draws
+// the gap before the next follow
+func (s *Service) markFollowed(did string) {
+	s.mu.Lock()
+	s.followed[did] = true
+	s.mu.Unlock()
+	s.markPaced()
+}
+
+// markPaced updates the follow counters and draws the gap before the next
+// follow, for a follow that was made or simulated
+func (s *Service) markPaced() {
 	s.mu.Lock()
-	s.followed[item.User.Handle] = true
 	s.lastFollow = time.Now()
-	s.followCount++
+	s.nextFollow = s.lastFollow.Add(s.pacer.Next(s.rng))
+	s.limiter.Take(s.lastFollow)
 	s.mu.Unlock()
+}
 
-	item.User.Followed = true
-	item.User.FollowDate = time.Now()
-	return s.db.SaveUser(item.User)
+// recordEvent appends a follow/unfollow attempt to the history. Failures are
+// logged rather than returned so a history write never undoes a follow.
+func (s *Service) recordEvent(user models.TargetUser, action, strategy string, actionErr error) {
+	event := models.FollowEvent{
+		Actor:     s.eventActor(),
+		DID:       user.DID,
+		Handle:    user.Handle,
+		Action:    action,
+		Strategy:  strategy,
+		Result:    models.ResultSuccess,
+		CreatedAt: time.Now(),
+	}
+	if actionErr != nil {
+		event.Result = models.ResultFailed
+		event.Error = actionErr.Error()
+	}
+
+	if err := s.db.RecordEvent(s.ctx, event); err != nil {
+		s.logger.Error("Failed to record %s event for %s: %v", action, user.Handle, err)
+	}
+	s.events.Publish(events.TypeAction, event)
 }
 
 // AddToQueue adds a user to the follow queue
 func (s *Service) AddToQueue(user models.TargetUser, priority int) {
+	if s.isBlocked(user) {
+		s.logger.Debug("User is blocklisted: %s", user.Handle)
+		s.rejectSimulated(user, dryrun.FilterBlocklist, "blocklisted")
+		return
+	}
+
+	if !user.Followed {
+		if until := user.CooldownUntil(s.config.RefollowCooldown); until.After(time.Now()) {
+			s.logger.Debug("User was unfollowed recently, cooling down until %s: %s", until.Format(time.RFC3339), user.Handle)
+			s.rejectSimulated(user, dryrun.FilterCooldown, "unfollowed recently, cooling down until "+until.Format("2006-01-02"))
+			return
+		}
+		if models.IsBlockRejection(user.Rejection) {
+			s.logger.Debug("User is out of reach through a block: %s (%s)", user.Handle, user.Rejection)
+			s.rejectSimulated(user, dryrun.FilterBlock, user.Rejection)
+			return
+		}
+		if models.IsReviewRejection(user.Rejection) {
+			s.logger.Debug("User was rejected in review: %s (%s)", user.Handle, user.Rejection)
+			s.rejectSimulated(user, dryrun.FilterReview, user.Rejection)
+			return
+		}
+		if reason := s.targetingChain().Check(user, time.Now()); reason != user.Rejection {
+			user.Rejection = reason
+			if err := s.db.SaveUser(s.ctx, user); err != nil {
+				s.logger.Error("Failed to save the targeting decision for %s: %v", user.Handle, err)
+			}
+		}
+		if user.Rejection != "" {
+			s.logger.Debug("User rejected by the targeting filters: %s (%s)", user.Handle, user.Rejection)
+			s.rejectSimulated(user, dryrun.FilterTargeting, user.Rejection)
+			return
+		}
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	followed, follower := s.followed[user.DID], s.followers[user.DID]
+	s.mu.Unlock()
 
-	if s.followed[user.Handle] {
+	if followed {
 		s.logger.Debug("User already followed: %s", user.Handle)
 		return
 	}
 
-	s.queue.Push(user, priority)
-	s.logger.Info("Added user to queue: %s (priority: %d)", user.Handle, priority)
+	if s.decay().Expired(user.SavedOn, time.Now()) {
+		s.logger.Debug("User is too stale to queue: %s", user.Handle)
+		return
+	}
+
+	if follower && !s.config.FollowBack {
+		s.logger.Debug("User already follows me, skipping: %s", user.Handle)
+		return
+	}
+
+	priority += s.weights.SourceOffset(s.sourceOffset(user.Source)) + s.growthBonus(user.DID)
+	added := true
+	for _, item := range s.queue.Push(user, priority) {
+		s.logger.Info("Queue is full, evicted %s (priority: %d)", item.User.Handle, item.Priority)
+		s.recordEvent(item.User, models.ActionEvict, queueStrategy, nil)
+		added = added && item.User.DID != user.DID
+	}
+	if added {
+		s.logger.Info("Added user to queue: %s (priority: %d)", user.Handle, priority)
+	}
+}
+
+// sourceOffset returns the configured priority offset for a discovery source.
+// A parameterized source such as "search:golang" matches an exact entry
+// first and then its kind ("search").
+func (s *Service) sourceOffset(source string) int {
+	if source == "" {
+		return 0
+	}
+	if offset, ok := s.config.SourcePriority[source]; ok {
+		return offset
+	}
+	kind, _, _ := strings.Cut(source, ":")
+	return s.config.SourcePriority[kind]
+}
+
+// SyncFollowers fetches every account that follows me and replaces the
+// stored followers snapshot. It returns the number of followers found.
+func (s *Service) SyncFollowers(session *models.Session) (int, error) {
+	var followers []models.Actor
+	cursor := ""
+	for {
+		page, next, err := s.api.GetFollowers(session, session.Did, cursor)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch followers: %w", err)
+		}
+		followers = append(followers, page...)
+		if next == "" || len(page) == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	if err := s.db.SaveFollowers(s.ctx, followers); err != nil {
+		return 0, err
+	}
+
+	s.setFollowers(followers)
+	s.logger.Info("Synced %d followers", len(followers))
+	return len(followers), nil
+}
+
+// loadFollowers reads the stored followers snapshot into memory
+func (s *Service) loadFollowers() error {
+	followers, err := s.db.LoadFollowers(s.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load followers: %w", err)
+	}
+	s.setFollowers(followers)
+	return nil
+}
+
+func (s *Service) setFollowers(followers []models.Actor) {
+	set := make(map[string]bool, len(followers))
+	for _, follower := range followers {
+		set[follower.Did] = true
+	}
+
+	s.mu.Lock()
+	s.followers = set
+	s.mu.Unlock()
+}
+
+// LoadQueue enqueues every stored user that has not been followed yet,
+// skipping accounts that already follow me unless follow-back is enabled
+func (s *Service) LoadQueue() error {
+	if err := s.loadFollowers(); err != nil {
+		return err
+	}
+	if err := s.refreshGrowth(); err != nil {
+		s.logger.Error("Failed to score follower growth: %v", err)
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load users: %w", err)
+	}
+
+	for _, user := range users {
+		if user.Followed {
+			s.mu.Lock()
+			s.followed[user.DID] = true
+			s.mu.Unlock()
+			continue
+		}
+		s.AddToQueue(user, user.Priority)
+	}
+
+	return nil
+}
+
+// RecordDailyStats snapshots today's follower and following counts, queue
+// depth and follows performed. Recording again on the same day replaces the
+// earlier snapshot, so the last one of the day wins.
+func (s *Service) RecordDailyStats(session *models.Session) (*models.DailyStats, error) {
+	profile, err := s.api.GetProfile(session, session.Did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch own profile: %w", err)
+	}
+
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	summary, err := stats.Compute(s.ctx, s.db, day)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := models.DailyStats{
+		Day:              day,
+		Followers:        profile.FollowersCount,
+		Following:        profile.FollowsCount,
+		QueueDepth:       s.QueueLen(),
+		FollowsPerformed: summary.FollowsPerformed,
+		RecordedAt:       now,
+	}
+	if err := s.db.SaveDailyStats(s.ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Recorded daily stats: %d followers, %d following, %d queued, %d follows today",
+		snapshot.Followers, snapshot.Following, snapshot.QueueDepth, snapshot.FollowsPerformed)
+	s.events.Publish(events.TypeStats, snapshot)
+	return &snapshot, nil
+}
+
+// RunDailyStats records a daily stats snapshot now and then at every
+// interval, keeping today's row current until the day rolls over
+func (s *Service) RunDailyStats(session *models.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RecordDailyStats(session); err != nil {
+			s.alert("Failed to record daily stats: %v", err)
+		}
+		if !s.wait(ticker) {
+			return
+		}
+	}
+}
+
+// DailyStats returns the recorded snapshots for days on or after since, oldest first
+func (s *Service) DailyStats(since time.Time) ([]models.DailyStats, error) {
+	return s.db.LoadDailyStats(s.ctx, since)
+}
+
+// CapacityReport estimates how many qualifying candidates each source yields
+// per week, judged by the users saved within the window, and whether the
+// queue will run dry at the rate the follow limits allow
+func (s *Service) CapacityReport(window time.Duration) (*stats.CapacityReport, error) {
+	if err := s.loadFollowers(); err != nil {
+		return nil, err
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	blocklist, err := s.db.LoadBlocklist(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[string]bool, len(blocklist))
+	for _, entry := range blocklist {
+		blocked[entry.Subject] = true
+	}
+
+	s.mu.Lock()
+	followers := s.followers
+	s.mu.Unlock()
+
+	qualifies := func(user models.TargetUser) bool {
+		if blocked[user.DID] || blocked[user.Handle] {
+			return false
+		}
+		return !followers[user.DID] || s.config.FollowBack
+	}
+	return stats.Capacity(users, qualifies, s.weeklyFollowCapacity(), window, time.Now()), nil
+}
+
+// weeklyFollowCapacity is the number of follows the hourly limit, the
+// daily and weekly caps and the expected gap between follows allow in a week
+func (s *Service) weeklyFollowCapacity() float64 {
+	limits := s.followLimits()
+	capacity := math.Inf(1)
+	if limits.PerHour > 0 {
+		capacity = float64(limits.PerHour * 7 * 24)
+	}
+	if limits.Gap > 0 {
+		capacity = min(capacity, float64(schedule.Week/limits.Gap))
+	}
+	if limits.PerDay > 0 {
+		capacity = min(capacity, float64(limits.PerDay*7))
+	}
+	if limits.PerWeek > 0 {
+		capacity = min(capacity, float64(limits.PerWeek))
+	}
+	return capacity
+}
+
+// PreviewSchedule simulates the queue processor's pacing over the current
+// queue and returns the follows it expects to make within the horizon
+func (s *Service) PreviewSchedule(horizon time.Duration) *schedule.Plan {
+	items := s.queue.Snapshot()
+	s.mu.Lock()
+	next := s.nextFollow
+	s.mu.Unlock()
+
+	from := time.Now()
+
+	// Nothing is followed while the account is paused
+	pause, err := s.AccountPause("")
+	if err != nil {
+		s.logger.Error("Failed to check pause state for the schedule preview: %v", err)
+	}
+	if pause != nil {
+		if pause.Until.IsZero() {
+			items = nil
+		} else if pause.Until.After(next) {
+			next = pause.Until
+		}
+	}
+	// No item is ready before the pacing and a timed pause allow a follow
+	for i := range items {
+		if items[i].NextTry.Before(next) {
+			items[i].NextTry = next
+		}
+	}
+
+	recent, err := s.recentFollows(from)
+	if err != nil {
+		s.logger.Error("Failed to load recent follows for the schedule preview: %v", err)
+	}
+	return schedule.Build(items, s.followLimits(), recent, from, horizon)
+}
+
+// Dequeue removes a handle or DID from the follow queue before it is
+// followed, reporting whether it was queued
+func (s *Service) Dequeue(subject string) bool {
+	subject = normalizeSubject(subject)
+
+	removed := false
+	for _, item := range s.queue.Snapshot() {
+		if item.User.DID == subject || strings.EqualFold(item.User.Handle, subject) {
+			removed = s.queue.Remove(item.User.DID) || removed
+		}
+	}
+	if removed {
+		s.logger.Info("Removed %s from the follow queue", subject)
+	}
+	return removed
+}
+
+// BumpQueued raises the priority of a queued user by the given amount, both
+// stored and queued, and reports whether the user was queued
+func (s *Service) BumpQueued(did string, by int) (bool, error) {
+	var user models.TargetUser
+	if !s.queue.Modify(did, func(item *models.FollowQueueItem) {
+		item.Priority += by
+		item.User.Priority += by
+		user = item.User
+	}) {
+		return false, nil
+	}
+	if err := s.db.SaveUser(s.ctx, user); err != nil {
+		return true, err
+	}
+	s.logger.Info("Bumped %s to priority %d", user.Handle, user.Priority)
+	return true, nil
+}
+
+// RetryQueuedNow makes a queued user due right away, skipping what is left
+// of its backoff, and reports whether the user was queued
+func (s *Service) RetryQueuedNow(did string) bool {
+	now := time.Now()
+	return s.queue.Modify(did, func(item *models.FollowQueueItem) {
+		item.NextTry = now
+	})
+}
+
+// Reprioritize sets the priority of every pending user matching the filter,
+// both stored and queued, without rebuilding the queue. It returns the
+// number of users changed.
+func (s *Service) Reprioritize(filter db.UserFilter, priority int) (int, error) {
+	pending := false
+	filter.Followed = &pending
+	users, err := s.db.QueryUsers(s.ctx, filter, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	dids := make(map[string]bool, len(users))
+	var changed []models.TargetUser
+	for _, user := range users {
+		dids[user.DID] = true
+		if user.Priority != priority {
+			user.Priority = priority
+			changed = append(changed, user)
+		}
+	}
+	if err := s.db.SaveUsers(s.ctx, changed); err != nil {
+		return 0, err
+	}
+
+	queued := s.queue.Reprioritize(func(user models.TargetUser) bool { return dids[user.DID] }, priority)
+	s.logger.Info("Reprioritized %d users to %d (%d queued)", len(changed), priority, queued)
+	return len(changed), nil
+}
+
+// NextFollow returns when the paced gap after the last follow, the rate
+// limit and the daily and weekly caps all allow the next follow
+func (s *Service) NextFollow(now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	next := s.nextFollow
+	s.mu.Unlock()
+
+	if limit := s.limiter.Next(now); limit.After(next) {
+		next = limit
+	}
+	reset, err := s.followCapReset(now)
+	if err != nil {
+		return now, err
+	}
+	if reset.After(next) {
+		next = reset
+	}
+	return next, nil
+}
+
+// QueueLen returns the number of items waiting in the follow queue
+func (s *Service) QueueLen() int {
+	return s.queue.Len()
+}
+
+// UpcomingFollows returns copies of the next n queued items in the order they
+// will be taken, building the queue from the store first if it is empty
+func (s *Service) UpcomingFollows(n int) ([]models.FollowQueueItem, error) {
+	if s.QueueLen() == 0 {
+		if err := s.LoadQueue(); err != nil {
+			return nil, err
+		}
+	}
+	return s.queue.PeekN(n), nil
+}
+
+// QueryUsers returns up to limit stored users matching a filter, in the
+// filter's order (0 for all)
+func (s *Service) QueryUsers(filter db.UserFilter, limit int) ([]models.TargetUser, error) {
+	return s.db.QueryUsers(s.ctx, filter, limit, 0)
+}
+
+// QueueStats summarizes the follow queue as of now
+func (s *Service) QueueStats() queue.Stats {
+	return s.queue.Stats(time.Now())
+}
+
+// Queue returns the follow queue, which is safe to share with the UI
+func (s *Service) Queue() *queue.Queue {
+	return s.queue
+}
+
+// FollowedCount returns the size of the in-memory followed set
+func (s *Service) FollowedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.followed)
 }
 
 // Close closes the service and its resources
 func (s *Service) Close() error {
+	s.unsubscribe()
 	return s.db.Close()
-} 
\ No newline at end of file
+}
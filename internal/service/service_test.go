@@ -0,0 +1,26 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+func TestFollowNextSkipsBlocklisted(t *testing.T) {
+	s := newTestService(t, models.TargetUser{DID: "did:plc:spam", Handle: "spam.bsky.social", SavedOn: time.Now()})
+	if err := s.db.AddToBlocklist(s.ctx, models.BlocklistEntry{Subject: "did:plc:spam", Reason: "spam"}); err != nil {
+		t.Fatalf("AddToBlocklist: %v", err)
+	}
+
+	step := s.FollowNext(&models.Session{Did: "did:plc:me", Handle: "me.bsky.social"})
+	if step.User == nil || step.User.DID != "did:plc:spam" || step.Err != nil {
+		t.Fatalf("FollowNext = %+v, want a step on the blocklisted user", step)
+	}
+	if step.Skipped != "blocklisted" {
+		t.Errorf("Skipped = %q, want blocklisted", step.Skipped)
+	}
+	if stats := s.Queue().Stats(time.Now()); stats.PerHour != 0 || stats.Depth != 0 {
+		t.Errorf("queue stats = %+v, want the skip dropped and not counted as processed", stats)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// ensureSession renews the session if its access token expires within
+// sessionExpiryMargin. A token whose expiry can't be read is left to the
+// server to refuse.
+func (s *Service) ensureSession(session *models.Session) error {
+	expiry := session.Expiry()
+	if expiry.IsZero() || time.Until(expiry) >= sessionExpiryMargin {
+		return nil
+	}
+	return s.renewSession(session)
+}
+
+// renewSession gets new tokens for the session in place, so every job
+// sharing it picks them up. The refresh token is tried first; if it is
+// refused too, the configured credentials log in again. An error is only
+// returned when both fail.
+func (s *Service) renewSession(session *models.Session) error {
+	token := session.Bearer()
+	s.renewing.Lock()
+	defer s.renewing.Unlock()
+
+	// Another job may have renewed it while this one waited
+	if session.Bearer() != token {
+		return nil
+	}
+
+	renewed, err := s.api.RefreshSession(session)
+	if err != nil {
+		s.logger.Info("Session could not be refreshed, logging in again: %v", err)
+		renewed, err = s.api.Login(s.config.Identifier, s.config.Password)
+		if err != nil {
+			return fmt.Errorf("failed to log in again: %w", err)
+		}
+	}
+	if renewed.Did != session.Did {
+		return fmt.Errorf("%w: renewed session is for %s (%s), expected %s",
+			ErrIdentityMismatch, renewed.Handle, renewed.Did, session.Did)
+	}
+
+	session.Renew(renewed)
+	if err := s.db.SaveSession(s.ctx, session); err != nil {
+		s.logger.Error("Failed to persist renewed session: %v", err)
+	}
+	s.logger.Info("Renewed session for %s, valid until %s", session.Handle, session.Expiry().Format(time.RFC3339))
+	return nil
+}
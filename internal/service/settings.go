@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/schedule"
+	"bsky_follower/internal/targeting"
+)
+
+// Setting is one configuration value that can be changed while running,
+// named by its environment variable
+type Setting struct {
+	Key   string
+	Label string
+	Value string
+	Bool  bool // true or false, toggled rather than typed
+}
+
+// settingKeys lists the settings ApplySetting changes, in display order
+var settingKeys = []struct {
+	key, label string
+	bool       bool
+}{
+	{"BSKY_FOLLOWS_PER_HOUR", "Follows per hour", false},
+	{"BSKY_FOLLOWS_PER_DAY", "Follows per day", false},
+	{"BSKY_FOLLOWS_PER_WEEK", "Follows per week", false},
+	{"BSKY_UNFOLLOWS_PER_HOUR", "Unfollows per hour", false},
+	{"BSKY_ACTIVE_HOURS", "Active hours", false},
+	{"BSKY_ACTIVE_DAYS", "Active days", false},
+	{"BSKY_TARGET_MIN_FOLLOWERS", "Target min followers", false},
+	{"BSKY_TARGET_MAX_FOLLOWERS", "Target max followers", false},
+	{"BSKY_TARGET_MAX_RATIO", "Target max following ratio", false},
+	{"BSKY_TARGET_REQUIRE_AVATAR", "Target requires avatar", true},
+	{"BSKY_DRY_RUN", "Dry run", true},
+}
+
+// Settings returns the current values of the settings that can be changed
+// while running
+func (s *Service) Settings() []Setting {
+	s.settings.RLock()
+	values := map[string]string{
+		"BSKY_FOLLOWS_PER_HOUR":      strconv.Itoa(s.config.FollowsPerHour),
+		"BSKY_FOLLOWS_PER_DAY":       strconv.Itoa(s.config.FollowsPerDay),
+		"BSKY_FOLLOWS_PER_WEEK":      strconv.Itoa(s.config.FollowsPerWeek),
+		"BSKY_UNFOLLOWS_PER_HOUR":    strconv.Itoa(s.config.UnfollowsPerHour),
+		"BSKY_ACTIVE_HOURS":          s.config.ActiveHours,
+		"BSKY_ACTIVE_DAYS":           s.config.ActiveDays,
+		"BSKY_TARGET_MIN_FOLLOWERS":  strconv.Itoa(s.config.Targeting.MinFollowers),
+		"BSKY_TARGET_MAX_FOLLOWERS":  strconv.Itoa(s.config.Targeting.MaxFollowers),
+		"BSKY_TARGET_MAX_RATIO":      strconv.FormatFloat(s.config.Targeting.MaxRatio, 'g', -1, 64),
+		"BSKY_TARGET_REQUIRE_AVATAR": strconv.FormatBool(s.config.Targeting.RequireAvatar),
+		"BSKY_DRY_RUN":               strconv.FormatBool(s.DryRun()),
+	}
+	s.settings.RUnlock()
+
+	settings := make([]Setting, len(settingKeys))
+	for i, k := range settingKeys {
+		settings[i] = Setting{Key: k.key, Label: k.label, Value: values[k.key], Bool: k.bool}
+	}
+	return settings
+}
+
+// ApplySetting validates a new value for a setting and puts it into effect
+// right away: the rate limits, caps, active hours and targeting filters are
+// used as changed from the next follow on. Candidates already screened keep
+// their decision until they are screened again. The value isn't saved
+// anywhere; that is left to the caller.
+func (s *Service) ApplySetting(key, value string) error {
+	value = strings.TrimSpace(value)
+	count := func() (int, error) {
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s must be a whole number of at least 0, got %q", key, value)
+		}
+		return n, nil
+	}
+
+	s.settings.Lock()
+	defer s.settings.Unlock()
+
+	switch key {
+	case "BSKY_FOLLOWS_PER_HOUR", "BSKY_UNFOLLOWS_PER_HOUR":
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if key == "BSKY_FOLLOWS_PER_HOUR" {
+			s.config.FollowsPerHour = n
+			s.limiter.SetMax(n, time.Now())
+		} else {
+			s.config.UnfollowsPerHour = n
+			s.unfollows.SetMax(n, time.Now())
+		}
+	case "BSKY_FOLLOWS_PER_DAY", "BSKY_FOLLOWS_PER_WEEK":
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if key == "BSKY_FOLLOWS_PER_DAY" {
+			s.config.FollowsPerDay = n
+		} else {
+			s.config.FollowsPerWeek = n
+		}
+	case "BSKY_ACTIVE_HOURS", "BSKY_ACTIVE_DAYS":
+		hours, days := s.config.ActiveHours, s.config.ActiveDays
+		if key == "BSKY_ACTIVE_HOURS" {
+			hours = value
+		} else {
+			days = value
+		}
+		window, err := schedule.ParseWindow(hours, days)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		s.config.ActiveHours, s.config.ActiveDays = hours, days
+		s.window = window
+	case "BSKY_TARGET_MIN_FOLLOWERS", "BSKY_TARGET_MAX_FOLLOWERS":
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if key == "BSKY_TARGET_MIN_FOLLOWERS" {
+			s.config.Targeting.MinFollowers = n
+		} else {
+			s.config.Targeting.MaxFollowers = n
+		}
+		s.targeting = targeting.NewChain(s.config.Targeting)
+	case "BSKY_TARGET_MAX_RATIO":
+		ratio, err := strconv.ParseFloat(value, 64)
+		if value == "" {
+			ratio, err = 0, nil
+		}
+		if err != nil || ratio < 0 {
+			return fmt.Errorf("%s must be a number of at least 0, got %q", key, value)
+		}
+		s.config.Targeting.MaxRatio = ratio
+		s.targeting = targeting.NewChain(s.config.Targeting)
+	case "BSKY_TARGET_REQUIRE_AVATAR", "BSKY_DRY_RUN":
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		if key == "BSKY_DRY_RUN" {
+			s.SetDryRun(on)
+		} else {
+			s.config.Targeting.RequireAvatar = on
+			s.targeting = targeting.NewChain(s.config.Targeting)
+		}
+	default:
+		return fmt.Errorf("%s can't be changed while running", key)
+	}
+
+	s.logger.Info("Set %s to %q", key, value)
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// ErrStopped is returned by long-running work cut short by Stop
+var ErrStopped = errors.New("service is stopping")
+
+// Stop asks the follow queue processor, unfollow campaigns and periodic jobs
+// to return. A follow or unfollow already in flight finishes and is saved
+// first; store calls keep working so the caller can PersistQueue and Close.
+func (s *Service) Stop() {
+	s.cancel()
+}
+
+// stopped reports whether Stop has been called
+func (s *Service) stopped() bool {
+	return s.stop.Err() != nil
+}
+
+// sleep waits for d or until Stop, reporting false if stopped
+func (s *Service) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop.Done():
+		return false
+	}
+}
+
+// wait waits for the ticker's next tick or until Stop, reporting false if stopped
+func (s *Service) wait(ticker *time.Ticker) bool {
+	select {
+	case <-ticker.C:
+		return true
+	case <-s.stop.Done():
+		return false
+	}
+}
+
+// PersistQueue writes each queued user's attempts and next try time back to
+// the database, so retries keep their backoff across a restart, and saves the
+// processor checkpoint. Only those two fields change; the rest of each stored
+// user is left as is. It returns the number of users saved.
+func (s *Service) PersistQueue() (int, error) {
+	s.saveCheckpoint()
+
+	pending := false
+	users, err := s.db.QueryUsers(s.ctx, db.UserFilter{Followed: &pending}, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	byDID := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		byDID[user.DID] = user
+	}
+
+	now := time.Now()
+	var changed []models.TargetUser
+	for _, item := range s.queue.Snapshot() {
+		user, ok := byDID[item.User.DID]
+		if !ok {
+			continue
+		}
+		notBefore := user.NotBefore
+		if item.NextTry.After(now) && item.NextTry.After(notBefore) {
+			notBefore = item.NextTry
+		}
+		if item.Attempts == user.Attempts && notBefore.Equal(user.NotBefore) {
+			continue
+		}
+		user.Attempts = item.Attempts
+		user.NotBefore = notBefore
+		changed = append(changed, user)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, changed); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Strategy is a discovery source. Strategies only find candidates; the
+// service drops accounts it already knows or has blocklisted and saves and
+// queues the rest, so a new source never touches the follow loop.
+type Strategy interface {
+	// Name identifies the strategy in logs and BSKY_STRATEGIES. Strategies
+	// with a parameter are named "<kind>:<parameter>", e.g. "followers-of:alice".
+	Name() string
+	// Discover returns candidate accounts with their profile, source and
+	// priority filled in
+	Discover(ctx context.Context, session *models.Session) ([]models.TargetUser, error)
+	// Schedule is how often the daemon runs the strategy, 0 for on demand only
+	Schedule() time.Duration
+}
+
+// Register adds a strategy for the daemon to run on its schedule
+func (s *Service) Register(strategy Strategy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strategies = append(s.strategies, strategy)
+}
+
+// Strategies returns the registered strategies in registration order
+func (s *Service) Strategies() []Strategy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Strategy(nil), s.strategies...)
+}
+
+// StrategyEnabled reports whether BSKY_STRATEGIES lets a strategy run. An
+// empty list enables every strategy; otherwise a strategy runs if its name
+// or its kind is listed.
+func (s *Service) StrategyEnabled(strategy Strategy) bool {
+	if len(s.config.Strategies) == 0 {
+		return true
+	}
+	name := strategy.Name()
+	kind, _, _ := strings.Cut(name, ":")
+	for _, enabled := range s.config.Strategies {
+		if enabled == name || enabled == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// registerConfigured registers the built-in strategies the configuration
+// asks for
+func (s *Service) registerConfigured() {
+	filter := CandidateFilter{
+		MinFollowers: s.config.MinFollowers,
+		MaxFollowers: s.config.MaxFollowers,
+		MinPosts:     s.config.MinPosts,
+	}
+	for _, handle := range s.config.FollowersOf {
+		s.Register(s.FollowersOfStrategy(handle, filter, DefaultTargetPriority, followersOfBatch))
+	}
+	if len(s.config.Keywords) > 0 {
+		s.Register(s.KeywordStrategy(s.config.Keywords, filter, DefaultTargetPriority, keywordBatch))
+	}
+	if s.config.SecondDegree {
+		s.Register(s.SecondDegreeStrategy(secondDegreeSample, secondDegreeMinOverlap, filter, DefaultTargetPriority, secondDegreeBatch))
+	}
+}
+
+// Discover runs a strategy once and saves the candidates that are new,
+// leaving out stored users, blocklisted accounts and the account itself. Those
+// that pass the targeting filters are queued; the rest are saved with the
+// reason they were rejected.
+func (s *Service) Discover(ctx context.Context, session *models.Session, strategy Strategy) (*DiscoveryResult, error) {
+	users, err := strategy.Discover(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strategy.Name(), err)
+	}
+
+	if err := s.loadFollowers(); err != nil {
+		return nil, err
+	}
+	known, err := s.knownAccounts(session)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiscoveryResult{}
+	var fresh []models.TargetUser
+	for _, user := range users {
+		result.Scanned++
+		if user.DID == "" || known[user.DID] || s.isBlocked(user) {
+			result.Skipped++
+			continue
+		}
+		known[user.DID] = true
+		fresh = append(fresh, user)
+	}
+
+	s.lookUpPosts(session, fresh)
+	rejected, err := s.admit(fresh)
+	if err != nil {
+		return result, err
+	}
+	result.Added = len(fresh) - rejected
+	result.Filtered = rejected
+	s.logger.Info("Strategy %s discovered %d new accounts (%d skipped, %d rejected)", strategy.Name(), result.Added, result.Skipped, result.Filtered)
+	return result, nil
+}
+
+// RunStrategies runs every enabled strategy with a schedule, each now and
+// then on its own schedule, until Stop
+func (s *Service) RunStrategies(session *models.Session) {
+	var wg sync.WaitGroup
+	for _, strategy := range s.Strategies() {
+		if strategy.Schedule() <= 0 {
+			continue
+		}
+		if !s.StrategyEnabled(strategy) {
+			s.logger.Info("Strategy %s is disabled", strategy.Name())
+			continue
+		}
+
+		wg.Add(1)
+		go func(strategy Strategy) {
+			defer wg.Done()
+			ticker := time.NewTicker(strategy.Schedule())
+			defer ticker.Stop()
+
+			for {
+				if _, err := s.Discover(s.stop, session, strategy); err != nil && s.stop.Err() == nil {
+					s.alert("Discovery failed: %v", err)
+				}
+				if !s.wait(ticker) {
+					return
+				}
+			}
+		}(strategy)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/dryrun"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/targeting"
+)
+
+// candidatePosts is how many recent posts are read to judge a candidate's
+// activity and languages
+const candidatePosts = 10
+
+// targetingChain returns the targeting filters as currently configured
+func (s *Service) targetingChain() targeting.Chain {
+	s.settings.RLock()
+	defer s.settings.RUnlock()
+	return s.targeting
+}
+
+// screen runs the targeting filters over candidates about to be saved,
+// recording why each rejected one was rejected, and returns how many were
+func (s *Service) screen(users []models.TargetUser) int {
+	chain := s.targetingChain()
+	now := time.Now()
+	rejected := 0
+	for i := range users {
+		users[i].Rejection = chain.Check(users[i], now)
+		if users[i].Rejection != "" {
+			s.rejectSimulated(users[i], dryrun.FilterTargeting, users[i].Rejection)
+			rejected++
+		}
+	}
+	return rejected
+}
+
+// lookUpPosts fills in the newest post time and post languages of candidates
+// discovery saw no posts of, if the targeting rules judge by them. A failed
+// lookup leaves the candidate as it is; the rules pass what they don't know.
+func (s *Service) lookUpPosts(session *models.Session, users []models.TargetUser) {
+	if !targeting.NeedsPosts(s.config.Targeting) {
+		return
+	}
+	for i := range users {
+		if !users[i].LastPostAt.IsZero() {
+			continue
+		}
+		posts, err := s.api.GetAuthorPosts(session, users[i].DID, candidatePosts)
+		if err != nil {
+			s.logger.Error("Failed to look up the posts of %s: %v", users[i].Handle, err)
+			continue
+		}
+		users[i].ApplyPosts(posts)
+	}
+}
+
+// Rejected returns up to limit (0 for all) pending candidates the targeting
+// filters keep out of the queue, highest priority first
+func (s *Service) Rejected(limit int) ([]models.TargetUser, error) {
+	pending, rejected := false, true
+	return s.db.QueryUsers(s.ctx, db.UserFilter{Followed: &pending, Rejected: &rejected}, limit, 0)
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// DefaultTargetPriority is the priority of imported targets without one
+const DefaultTargetPriority = 1
+
+// TargetSpec is one line of a target file: a handle or DID and an optional
+// priority (0 means the default)
+type TargetSpec struct {
+	Subject  string
+	Priority int
+}
+
+// TargetImportResult counts what ImportTargets did with each line
+type TargetImportResult struct {
+	Added   int
+	Merged  int // known accounts found under a new handle
+	Skipped int // already stored, followed or blocklisted
+	Failed  []string
+}
+
+// ReadTargets reads a target file. Each line is a handle or DID, optionally
+// followed by a comma and a priority. A first line naming a handle column
+// makes it a CSV file, where handle (or did) and priority columns are picked
+// by name and other columns are ignored. Blank lines and lines starting with
+// # are skipped.
+func ReadTargets(r io.Reader) ([]TargetSpec, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read target file: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.Join(lines, "\n"))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target file: %w", err)
+	}
+
+	subjectCol, priorityCol := 0, 1
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	handleCol, hasHandle := columns["handle"]
+	didCol, hasDID := columns["did"]
+	if hasHandle || hasDID {
+		subjectCol = handleCol
+		if !hasHandle {
+			subjectCol = didCol
+		}
+		priorityCol = -1
+		if i, ok := columns["priority"]; ok {
+			priorityCol = i
+		}
+		records = records[1:]
+	}
+
+	var targets []TargetSpec
+	for _, record := range records {
+		if subjectCol >= len(record) {
+			continue
+		}
+		target := TargetSpec{Subject: normalizeSubject(record[subjectCol])}
+		if target.Subject == "" {
+			continue
+		}
+		if priorityCol >= 0 && priorityCol < len(record) {
+			if p := strings.TrimSpace(record[priorityCol]); p != "" {
+				priority, err := strconv.Atoi(p)
+				if err != nil {
+					return nil, fmt.Errorf("invalid priority %q for %s", p, target.Subject)
+				}
+				target.Priority = priority
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// ImportTargets resolves each target, stores it and adds it to the follow
+// queue. Targets that are already stored, followed or blocklisted are
+// skipped; targets that cannot be resolved are reported and skipped. A
+// handle that resolves to a stored DID under a different handle is merged
+// into that user, keeping its follow state, instead of being added again.
+// New targets are not followed before notBefore; zero follows them as soon
+// as the queue allows.
+func (s *Service) ImportTargets(session *models.Session, targets []TargetSpec, defaultPriority int, notBefore time.Time) (*TargetImportResult, error) {
+	if err := s.loadFollowers(); err != nil {
+		return nil, err
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	stored := make(map[string]bool, len(users)*2)
+	byDID := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		stored[user.DID] = true
+		stored[user.Handle] = true
+		byDID[user.DID] = user
+	}
+
+	result := &TargetImportResult{}
+	var pending []TargetSpec
+	listed := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if stored[target.Subject] || listed[target.Subject] {
+			result.Skipped++
+			continue
+		}
+		listed[target.Subject] = true
+		pending = append(pending, target)
+	}
+
+	resolutions := s.resolveTargets(session, pending)
+	var resolved, renamed []models.TargetUser
+	for i, target := range pending {
+		user, err := resolutions[i].user, resolutions[i].err
+		if errors.Is(err, ErrStopped) {
+			return result, err
+		}
+		if err != nil {
+			s.logger.Error("Failed to resolve import target %s: %v", target.Subject, err)
+			result.Failed = append(result.Failed, target.Subject)
+			continue
+		}
+		if existing, ok := byDID[user.DID]; ok && existing.Handle != user.Handle {
+			s.logger.Info("Import target %s is known as %s, merging", user.Handle, existing.Handle)
+			renamed = append(renamed, models.MergeUsers(existing, *user))
+			stored[user.Handle] = true
+			delete(byDID, user.DID)
+			result.Merged++
+			continue
+		}
+		if stored[user.DID] || s.isBlocked(*user) {
+			result.Skipped++
+			continue
+		}
+
+		user.Priority = defaultPriority
+		if target.Priority != 0 {
+			user.Priority = target.Priority
+		}
+		user.NotBefore = notBefore
+		stored[user.DID] = true
+		resolved = append(resolved, *user)
+	}
+
+	if err := s.db.SaveUsers(s.ctx, append(renamed, resolved...)); err != nil {
+		return result, err
+	}
+
+	observations := make([]models.FollowerObservation, len(resolved))
+	for i, user := range resolved {
+		observations[i] = models.FollowerObservation{DID: user.DID, Followers: user.Followers, ObservedAt: user.LastChecked}
+	}
+	if err := s.db.RecordFollowerCounts(s.ctx, observations); err != nil {
+		return result, err
+	}
+	for _, user := range resolved {
+		s.AddToQueue(user, user.Priority)
+	}
+	result.Added = len(resolved)
+
+	s.logger.Info("Imported %d targets (%d merged, %d skipped, %d failed)",
+		result.Added, result.Merged, result.Skipped, len(result.Failed))
+	return result, nil
+}
+
+// ScheduleFollow queues a single account to be followed no earlier than
+// notBefore. A stored account that has not been followed yet is rescheduled;
+// an unknown one is resolved and imported like a target file entry.
+func (s *Service) ScheduleFollow(session *models.Session, subject string, notBefore time.Time) error {
+	subject = normalizeSubject(subject)
+	if subject == "" {
+		return fmt.Errorf("no account given")
+	}
+
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load users: %w", err)
+	}
+	for _, user := range users {
+		if user.DID != subject && !strings.EqualFold(user.Handle, subject) {
+			continue
+		}
+		if user.Followed {
+			return fmt.Errorf("%s is already followed", user.Handle)
+		}
+		if s.isBlocked(user) {
+			return fmt.Errorf("%s is blocklisted", user.Handle)
+		}
+
+		user.NotBefore = notBefore
+		if err := s.db.SaveUser(s.ctx, user); err != nil {
+			return err
+		}
+		s.queue.Remove(user.DID)
+		s.AddToQueue(user, user.Priority)
+		s.logger.Info("Scheduled follow of %s for %s", user.Handle, notBefore.Format(time.RFC3339))
+		return nil
+	}
+
+	result, err := s.ImportTargets(session, []TargetSpec{{Subject: subject}}, DefaultTargetPriority, notBefore)
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("could not resolve %s", subject)
+	}
+	if result.Added == 0 {
+		return fmt.Errorf("%s was not scheduled; it is blocklisted or already stored under another handle", subject)
+	}
+	s.logger.Info("Scheduled follow of %s for %s", subject, notBefore.Format(time.RFC3339))
+	return nil
+}
+
+// resolveTarget looks up the DID and profile of a handle or DID, keeping to
+// the lookup rate limit
+func (s *Service) resolveTarget(session *models.Session, subject string) (*models.TargetUser, error) {
+	profile, err := s.resolveProfile(session, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &models.TargetUser{
+		Handle:      subject,
+		DID:         profile.Did,
+		SavedOn:     now,
+		LastChecked: now,
+		Source:      models.SourceImport,
+	}
+	user.ApplyProfile(*profile)
+	return user, nil
+}
+
+// resolveProfile fetches the profile of a handle or DID, keeping to the
+// lookup rate limit. The profile's DID is always set.
+func (s *Service) resolveProfile(session *models.Session, subject string) (*models.Profile, error) {
+	did := subject
+	if !strings.HasPrefix(subject, "did:") {
+		if !s.awaitRead() {
+			return nil, ErrStopped
+		}
+		resolved, err := s.api.GetDID(session, subject)
+		if err != nil {
+			return nil, err
+		}
+		did = resolved
+	}
+
+	if !s.awaitRead() {
+		return nil, ErrStopped
+	}
+	profile, err := s.api.GetProfile(session, did)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Did == "" {
+		profile.Did = did
+	}
+	return profile, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// ActionFollowBack is the action of timeline entries for accounts that
+// followed me back. Follow-backs are kept on the users rather than in the
+// history, so it never appears in stored events.
+const ActionFollowBack = "follow-back"
+
+// Timeline returns what happened since a time, newest first: the recorded
+// follows, unfollows, skips, evictions and rate-limit waits, and the
+// follow-backs seen, at most limit entries
+func (s *Service) Timeline(since time.Time, limit int) ([]models.FollowEvent, error) {
+	entries, err := s.db.QueryEvents(s.ctx, db.EventFilter{Since: since}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	yes := true
+	backs, err := s.db.QueryUsers(s.ctx, db.UserFilter{FollowedBack: &yes, FollowedBackAfter: since, Sort: db.SortFollowedBack}, limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load follow-backs: %w", err)
+	}
+	for _, user := range backs {
+		entries = append(entries, models.FollowEvent{
+			DID:       user.DID,
+			Handle:    user.Handle,
+			Action:    ActionFollowBack,
+			Result:    models.ResultSuccess,
+			CreatedAt: user.FollowedBackAt,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
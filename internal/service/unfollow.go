@@ -0,0 +1,514 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/api"
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/schedule"
+)
+
+const (
+	// verifyInterval is how long a campaign waits before checking its
+	// unfollows again when only unverified ones are left
+	verifyInterval     = 2 * time.Minute
+	maxUnfollowRetries = 3
+	// maxVerifyRetries is how many verification passes may fail to look up
+	// a target's follow record before it is marked failed
+	maxVerifyRetries = 5
+
+	// campaignPollInterval is how often the daemon looks for new campaigns
+	campaignPollInterval = time.Minute
+
+	// campaignStrategyPrefix prefixes the campaign name in recorded events
+	campaignStrategyPrefix = "campaign:"
+)
+
+// StartUnfollowCampaign creates a campaign to unfollow the given handles or
+// DIDs. Handles are resolved from stored users first and the API second.
+// The campaign is only recorded; RunUnfollowCampaigns does the work.
+func (s *Service) StartUnfollowCampaign(session *models.Session, name string, subjects []string) (*models.UnfollowCampaign, int, error) {
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load users: %w", err)
+	}
+	byHandle := make(map[string]models.TargetUser, len(users))
+	byDID := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		byHandle[user.Handle] = user
+		byDID[user.DID] = user
+	}
+
+	var targets []models.UnfollowTarget
+	for _, subject := range subjects {
+		subject = normalizeSubject(subject)
+		if subject == "" {
+			continue
+		}
+
+		target := models.UnfollowTarget{DID: subject, Handle: subject}
+		switch {
+		case strings.HasPrefix(subject, "did:"):
+			if user, ok := byDID[subject]; ok {
+				target.Handle = user.Handle
+			}
+		case byHandle[subject].DID != "":
+			target.DID = byHandle[subject].DID
+		default:
+			did, err := s.api.GetDID(session, subject)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to resolve %s: %w", subject, err)
+			}
+			target.DID = did
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, 0, fmt.Errorf("unfollow campaign %s has no targets", name)
+	}
+
+	campaign, err := s.db.CreateUnfollowCampaign(s.ctx, name, targets)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.logger.Info("Created unfollow campaign %d (%s) with %d targets", campaign.ID, name, len(targets))
+	return campaign, len(targets), nil
+}
+
+// UnfollowCampaigns returns all campaigns, oldest first
+func (s *Service) UnfollowCampaigns() ([]models.UnfollowCampaign, error) {
+	return s.db.LoadUnfollowCampaigns(s.ctx)
+}
+
+// UnfollowTargets returns a campaign's targets with the given status (all if empty)
+func (s *Service) UnfollowTargets(campaignID int64, status string) ([]models.UnfollowTarget, error) {
+	return s.db.LoadUnfollowTargets(s.ctx, campaignID, status)
+}
+
+// SkipUnfollowTarget marks a pending campaign target skipped, so no run
+// unfollows it. It is a decision about the campaign rather than an unfollow,
+// so it is stored in a dry run too.
+func (s *Service) SkipUnfollowTarget(target models.UnfollowTarget, reason string) error {
+	s.logger.Info("Skipping %s in campaign %d: %s", target.Handle, target.CampaignID, reason)
+	s.recordDecision(models.TargetUser{DID: target.DID, Handle: target.Handle}, models.ActionSkip, manualStrategy, reason)
+	target.Status = models.UnfollowSkipped
+	target.Error = reason
+	return s.updateUnfollowTarget(&target)
+}
+
+// ProcessUnfollowCampaigns runs unfinished campaigns as they appear until
+// Stop. It is the daemon's unfollow queue: it runs interleaved with the
+// follow queue, with its own pacing, hourly rate and daily cap, so a burst of
+// unfollows doesn't hold up follows nor the other way round.
+func (s *Service) ProcessUnfollowCampaigns(session *models.Session) {
+	for {
+		if err := s.RunUnfollowCampaigns(session); err != nil && !errors.Is(err, ErrStopped) {
+			s.alert("Unfollow campaign processing failed: %v", err)
+		}
+		if !s.sleep(campaignPollInterval) {
+			return
+		}
+	}
+}
+
+// RunUnfollowCampaigns runs every unfinished campaign to completion, oldest
+// first after those of the ratio guard
+func (s *Service) RunUnfollowCampaigns(session *models.Session) error {
+	campaigns, err := s.db.LoadUnfollowCampaigns(s.ctx)
+	if err != nil {
+		return err
+	}
+	// Ratio guard campaigns go first, to bring the follower ratio back sooner
+	sort.SliceStable(campaigns, func(i, j int) bool {
+		return strings.HasPrefix(campaigns[i].Name, ratioUnfollowPrefix) && !strings.HasPrefix(campaigns[j].Name, ratioUnfollowPrefix)
+	})
+
+	for _, campaign := range campaigns {
+		if !campaign.CompletedAt.IsZero() {
+			continue
+		}
+		if err := s.RunUnfollowCampaign(session, campaign); err != nil {
+			return fmt.Errorf("campaign %d (%s): %w", campaign.ID, campaign.Name, err)
+		}
+	}
+	return nil
+}
+
+// RunUnfollowCampaign unfollows a campaign's pending targets within the
+// unfollow limits, then verifies each unfollowed record is really gone. Targets whose
+// record is still there go back to pending. Progress is stored per target,
+// so an interrupted campaign picks up where it stopped.
+func (s *Service) RunUnfollowCampaign(session *models.Session, campaign models.UnfollowCampaign) error {
+	users, err := s.db.LoadUsers(s.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load users: %w", err)
+	}
+	byDID := make(map[string]models.TargetUser, len(users))
+	for _, user := range users {
+		byDID[user.DID] = user
+	}
+
+	strategy := campaignStrategyPrefix + campaign.Name
+	if s.DryRun() {
+		return s.simulateCampaign(campaign, byDID, strategy)
+	}
+
+	for {
+		pending, err := s.db.LoadUnfollowTargets(s.ctx, campaign.ID, models.UnfollowPending)
+		if err != nil {
+			return err
+		}
+
+		for _, target := range pending {
+			if !s.waitWhilePaused(session) || !s.waitForUnfollowTurn() {
+				return ErrStopped
+			}
+			if err := s.unfollowTarget(session, &target, byDID, strategy); err != nil {
+				// The unfollow limit was stopped until the server allows
+				// writes again; the next turn waits for it
+				var rateLimited *api.RateLimitError
+				if !errors.As(err, &rateLimited) {
+					return err
+				}
+				s.logger.Info("Unfollow rate limited by the server")
+				break
+			}
+		}
+
+		if err := s.verifyUnfollows(session, campaign.ID); err != nil {
+			return err
+		}
+
+		remaining, err := s.db.LoadUnfollowTargets(s.ctx, campaign.ID, models.UnfollowPending)
+		if err != nil {
+			return err
+		}
+		unverified, err := s.db.LoadUnfollowTargets(s.ctx, campaign.ID, models.UnfollowDone)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 && len(unverified) == 0 {
+			break
+		}
+		if len(remaining) == 0 && !s.sleep(verifyInterval) {
+			return ErrStopped
+		}
+	}
+
+	s.logger.Info("Unfollow campaign %d (%s) complete", campaign.ID, campaign.Name)
+	if err := s.db.CompleteUnfollowCampaign(s.ctx, campaign.ID); err != nil {
+		return err
+	}
+	s.notify(fmt.Sprintf("✅ Unfollow campaign %q is complete", campaign.Name))
+	return nil
+}
+
+// simulateCampaign adds a campaign's pending targets to the dry-run report,
+// leaving out the protected accounts and mutuals a real run would skip. The
+// campaign itself is left untouched so it runs for real once dry-run is off.
+func (s *Service) simulateCampaign(campaign models.UnfollowCampaign, users map[string]models.TargetUser, strategy string) error {
+	pending, err := s.db.LoadUnfollowTargets(s.ctx, campaign.ID, models.UnfollowPending)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range pending {
+		user, ok := users[target.DID]
+		if !ok {
+			user = models.TargetUser{DID: target.DID, Handle: target.Handle}
+		}
+		if s.IsProtected(user) || s.keepsMutual(user) {
+			continue
+		}
+		s.logger.Info("Simulating unfollow for: %s", target.Handle)
+		s.simulate(user, models.ActionUnfollow, strategy)
+	}
+	return nil
+}
+
+// unfollowTarget unfollows a single campaign target and stores the outcome.
+// Only rate limit errors and storage errors are returned; other failures are
+// recorded on the target and retried on a later pass.
+func (s *Service) unfollowTarget(session *models.Session, target *models.UnfollowTarget, users map[string]models.TargetUser, strategy string) error {
+	user, ok := users[target.DID]
+	if !ok {
+		user = models.TargetUser{DID: target.DID, Handle: target.Handle}
+	}
+
+	if s.IsProtected(user) {
+		s.logger.Info("Skipping protected account: %s", target.Handle)
+		s.recordDecision(user, models.ActionSkip, strategy, "protected")
+		target.Status = models.UnfollowSkipped
+		target.Error = "protected"
+		return s.updateUnfollowTarget(target)
+	}
+
+	if s.keepsMutual(user) {
+		return s.skipMutual(user, target, strategy)
+	}
+
+	profile, err := s.api.GetProfile(session, target.DID)
+	if err == nil && profile.Viewer.Following != "" {
+		// The live relationship catches mutuals the followers snapshot missed
+		if profile.Viewer.FollowedBy != "" && !s.config.UnfollowMutuals {
+			return s.skipMutual(user, target, strategy)
+		}
+		err = s.api.UnfollowUser(session, profile.Viewer.Following)
+		s.markUnfollowed()
+		s.recordEvent(user, models.ActionUnfollow, strategy, err)
+	}
+
+	var rateLimited *api.RateLimitError
+	if errors.As(err, &rateLimited) {
+		return err
+	}
+
+	if err != nil {
+		target.Attempts++
+		target.Error = err.Error()
+		if target.Attempts >= maxUnfollowRetries {
+			target.Status = models.UnfollowFailed
+		}
+		s.logger.Error("Failed to unfollow %s (attempt %d): %v", target.Handle, target.Attempts, err)
+		return s.updateUnfollowTarget(target)
+	}
+
+	// No follow record means the account is already unfollowed
+	target.Status = models.UnfollowDone
+	target.Error = ""
+	if err := s.updateUnfollowTarget(target); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.followed, target.DID)
+	s.mu.Unlock()
+
+	if ok && user.Followed {
+		user.Followed = false
+		user.Mutual = false
+		user.UnfollowedAt = time.Now()
+		if err := s.db.SaveUser(s.ctx, user); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+	}
+	return nil
+}
+
+// waitWhilePaused blocks until the session's account is not paused,
+// reporting false if stopped while waiting
+func (s *Service) waitWhilePaused(session *models.Session) bool {
+	for s.isPaused(session) {
+		s.logger.Info("Account is paused, unfollows waiting")
+		if !s.sleep(pausePollInterval) {
+			return false
+		}
+	}
+	return !s.stopped()
+}
+
+// waitForUnfollowTurn blocks until the next unfollow may be made, as told by
+// unfollowTurn. It reports false if stopped while waiting.
+func (s *Service) waitForUnfollowTurn() bool {
+	for !s.stopped() {
+		now := time.Now()
+		next, limit := s.unfollowTurn(now)
+		if !next.After(now) {
+			return true
+		}
+		if limit != "" {
+			s.logger.Info("Unfollow limit reached, waiting until %s", next.Format(time.RFC3339))
+			s.rateLimited(limit, next)
+		}
+		if !s.sleep(next.Sub(now)) {
+			return false
+		}
+	}
+	return false
+}
+
+// unfollowTurn returns when the next unfollow may be made: after the paced
+// gap since the last one, within the hourly rate of BSKY_UNFOLLOWS_PER_HOUR
+// as adapted to the server, and under the daily cap of
+// BSKY_UNFOLLOWS_PER_DAY. These limits are kept apart from the follow
+// limits. It also names the limit that holds the unfollow back, empty if
+// only the pacing does.
+func (s *Service) unfollowTurn(now time.Time) (time.Time, string) {
+	s.mu.Lock()
+	next := s.nextUnfollow
+	s.mu.Unlock()
+
+	limit := ""
+	if hourly := s.unfollows.Next(now); hourly.After(next) {
+		next, limit = hourly, "unfollows per hour"
+		if !s.unfollows.Status().Blocked.IsZero() {
+			limit = "server"
+		}
+	}
+	daily, err := s.unfollowCapReset(now)
+	if err != nil {
+		s.logger.Error("Failed to check the unfollow cap: %v", err)
+		daily = now.Add(time.Minute)
+	}
+	if daily.After(next) {
+		next, limit = daily, "unfollows per day"
+	}
+	return next, limit
+}
+
+// Unfollow unfollows a single account by hand, outside any campaign. It
+// keeps to the same pacing, rate and cap as campaigns, refusing rather than
+// waiting when the next unfollow isn't due, and never unfollows a protected
+// account. Mutuals are refused with db.ErrMutual, as campaigns skip them,
+// unless mutualOK says the user was told and confirmed.
+func (s *Service) Unfollow(session *models.Session, user models.TargetUser, mutualOK bool) error {
+	if s.isPaused(session) {
+		return fmt.Errorf("account is paused; resume it to unfollow")
+	}
+	if s.IsProtected(user) {
+		return fmt.Errorf("%s is protected", user.Handle)
+	}
+	now := time.Now()
+	if next, limit := s.unfollowTurn(now); next.After(now) {
+		if limit == "" {
+			limit = "unfollow pacing"
+		}
+		return fmt.Errorf("held back by the %s until %s", limit, next.Format("15:04:05"))
+	}
+	if !mutualOK && s.keepsMutual(user) {
+		s.logger.Info("Refused to unfollow mutual %s without confirmation", user.Handle)
+		return fmt.Errorf("%s follows you back: %w", user.Handle, db.ErrMutual)
+	}
+
+	if s.DryRun() {
+		s.logger.Info("Simulating unfollow for: %s", user.Handle)
+		s.simulate(user, models.ActionUnfollow, manualStrategy)
+		return nil
+	}
+
+	if !s.awaitRead() {
+		return ErrStopped
+	}
+	profile, err := s.api.GetProfile(session, user.DID)
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", user.Handle, err)
+	}
+	if profile.Viewer.Following == "" {
+		return fmt.Errorf("%s isn't followed", user.Handle)
+	}
+	// The live relationship catches mutuals the followers snapshot missed
+	if !mutualOK && profile.Viewer.FollowedBy != "" && !s.config.UnfollowMutuals {
+		s.logger.Info("Refused to unfollow mutual %s without confirmation", user.Handle)
+		return fmt.Errorf("%s follows you back: %w", user.Handle, db.ErrMutual)
+	}
+
+	err = s.api.UnfollowUser(session, profile.Viewer.Following)
+	s.markUnfollowed()
+	s.recordEvent(user, models.ActionUnfollow, manualStrategy, err)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow %s: %w", user.Handle, err)
+	}
+	s.logger.Info("Unfollowed %s by hand", user.Handle)
+
+	s.mu.Lock()
+	delete(s.followed, user.DID)
+	s.mu.Unlock()
+
+	user.Followed = false
+	user.Mutual = false
+	user.UnfollowedAt = time.Now()
+	if err := s.db.SaveUser(s.ctx, user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+	return nil
+}
+
+// unfollowCapReset returns when the daily unfollow cap next allows an
+// unfollow, which is now if it allows one already. The unfollows come from
+// the history, so the cap holds across restarts.
+func (s *Service) unfollowCapReset(now time.Time) (time.Time, error) {
+	if s.config.UnfollowsPerDay <= 0 {
+		return now, nil
+	}
+	made, err := s.db.LoadEventTimes(s.ctx, models.ActionUnfollow, models.ResultSuccess, now.Add(-schedule.Day))
+	if err != nil {
+		return now, fmt.Errorf("failed to load recent unfollows: %w", err)
+	}
+	return schedule.Limits{PerDay: s.config.UnfollowsPerDay}.CapReset(made, now), nil
+}
+
+// markUnfollowed spends an unfollow from the hourly rate and draws the gap
+// before the next one
+func (s *Service) markUnfollowed() {
+	now := time.Now()
+	s.mu.Lock()
+	s.nextUnfollow = now.Add(s.unfollowPacer.Next(s.rng))
+	s.mu.Unlock()
+	s.unfollows.Take(now)
+	s.saveCheckpoint()
+}
+
+// skipMutual refuses to unfollow a mutual and records the attempt on the
+// target and in the history
+func (s *Service) skipMutual(user models.TargetUser, target *models.UnfollowTarget, strategy string) error {
+	s.logger.Error("Refusing to unfollow mutual %s in campaign %d", target.Handle, target.CampaignID)
+	s.recordDecision(user, models.ActionSkip, strategy, "mutual")
+	target.Status = models.UnfollowSkipped
+	target.Error = "mutual"
+	return s.updateUnfollowTarget(target)
+}
+
+// verifyUnfollows checks that every unfollowed target's follow record is
+// gone, returning targets that are still followed to pending until they run
+// out of attempts. A target whose record can't be looked up is checked again
+// on the next pass, and marked failed after maxVerifyRetries failed lookups,
+// so it can't hold its campaign open for good.
+func (s *Service) verifyUnfollows(session *models.Session, campaignID int64) error {
+	done, err := s.db.LoadUnfollowTargets(s.ctx, campaignID, models.UnfollowDone)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range done {
+		uri, err := s.api.GetFollowURI(session, target.DID)
+		if err != nil {
+			// Leave it for the next verification pass, until it runs out
+			s.logger.Error("Failed to verify unfollow of %s: %v", target.Handle, err)
+			target.Verifications++
+			target.Error = "could not verify the unfollow: " + err.Error()
+			if target.Verifications >= maxVerifyRetries {
+				target.Status = models.UnfollowFailed
+			}
+			if err := s.updateUnfollowTarget(&target); err != nil {
+				return err
+			}
+			continue
+		}
+
+		target.Status = models.UnfollowVerified
+		if uri != "" {
+			s.logger.Info("Follow record for %s still exists after unfollow", target.Handle)
+			target.Attempts++
+			target.Error = "follow record still present after unfollow"
+			target.Status = models.UnfollowPending
+			if target.Attempts >= maxUnfollowRetries {
+				target.Status = models.UnfollowFailed
+			}
+		}
+		if err := s.updateUnfollowTarget(&target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) updateUnfollowTarget(target *models.UnfollowTarget) error {
+	target.UpdatedAt = time.Now()
+	return s.db.UpdateUnfollowTarget(s.ctx, *target)
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// week is the reporting unit of capacity planning
+const week = 7 * 24 * time.Hour
+
+// unknownSource labels users stored without a discovery source
+const unknownSource = "unknown"
+
+// SourceYield is how many qualifying candidates a source found in the window
+type SourceYield struct {
+	Source     string
+	Candidates int
+	PerWeek    float64
+}
+
+// CapacityReport compares the supply of new candidates against the rate the
+// queue consumes them
+type CapacityReport struct {
+	Window         time.Duration
+	Sources        []SourceYield // busiest source first
+	Pending        int           // qualifying candidates waiting to be followed
+	InflowPerWeek  float64       // net-new qualifying candidates per week, all sources
+	FollowsPerWeek float64       // follows the rate limits allow per week
+	RunsDry        bool          // whether pending candidates run out at the current inflow
+	RunsDryIn      time.Duration // time until they do
+}
+
+// Capacity estimates per-source candidate yield from the users saved within
+// the window and how long the pending candidates last at the given follow
+// rate. Only users for which qualifies returns true are counted.
+func Capacity(users []models.TargetUser, qualifies func(models.TargetUser) bool, followsPerWeek float64, window time.Duration, now time.Time) *CapacityReport {
+	report := &CapacityReport{Window: window, FollowsPerWeek: followsPerWeek}
+	weeks := window.Hours() / week.Hours()
+	since := now.Add(-window)
+
+	counts := make(map[string]int)
+	for _, user := range users {
+		if user.Followed || !qualifies(user) {
+			continue
+		}
+		report.Pending++
+
+		if user.SavedOn.Before(since) {
+			continue
+		}
+		source := user.Source
+		if source == "" {
+			source = unknownSource
+		}
+		counts[source]++
+	}
+
+	for source, count := range counts {
+		yield := SourceYield{Source: source, Candidates: count, PerWeek: float64(count) / weeks}
+		report.Sources = append(report.Sources, yield)
+		report.InflowPerWeek += yield.PerWeek
+	}
+	sort.Slice(report.Sources, func(i, j int) bool {
+		if report.Sources[i].Candidates != report.Sources[j].Candidates {
+			return report.Sources[i].Candidates > report.Sources[j].Candidates
+		}
+		return report.Sources[i].Source < report.Sources[j].Source
+	})
+
+	if drain := followsPerWeek - report.InflowPerWeek; drain > 0 {
+		report.RunsDry = true
+		report.RunsDryIn = time.Duration(float64(report.Pending) / drain * float64(week))
+	}
+
+	return report
+}
@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Follow-back analytics groupings
+const (
+	BySource   = "source"   // full discovery source, e.g. "search:golang"
+	ByStrategy = "strategy" // source kind, e.g. "search"
+	ByCampaign = "campaign" // follow campaign that found the account
+)
+
+// Groupings lists the follow-back analytics groupings
+var Groupings = []string{BySource, ByStrategy, ByCampaign}
+
+// FollowBackStats summarizes how the accounts followed from one group
+// responded
+type FollowBackStats struct {
+	Group        string
+	Followed     int           // accounts followed
+	FollowedBack int           // of those, accounts that followed back at some point
+	Lost         int           // of those, accounts that stopped following me again
+	MedianDelay  time.Duration // median time from my follow to the follow back being seen
+}
+
+// Rate returns the share of followed accounts that followed back
+func (f FollowBackStats) Rate() float64 {
+	if f.Followed == 0 {
+		return 0
+	}
+	return float64(f.FollowedBack) / float64(f.Followed)
+}
+
+// Churn returns the share of follow backs that were lost again
+func (f FollowBackStats) Churn() float64 {
+	if f.FollowedBack == 0 {
+		return 0
+	}
+	return float64(f.Lost) / float64(f.FollowedBack)
+}
+
+// Median renders the median time to follow back in days and hours, or hours
+// and minutes when under a day, "-" if there were no follow backs
+func (f FollowBackStats) Median() string {
+	d := f.MedianDelay
+	switch {
+	case f.FollowedBack == 0:
+		return "-"
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+	return d.Round(time.Minute).String()
+}
+
+// FollowBacks computes follow-back statistics per group of the accounts I
+// followed, including those I unfollowed since. Follows with an unknown date,
+// such as those made outside the app, are left out. Groups are ordered by the
+// number of accounts followed, most first.
+func FollowBacks(users []models.TargetUser, group func(models.TargetUser) string) []FollowBackStats {
+	byGroup := make(map[string]*FollowBackStats)
+	delays := make(map[string][]time.Duration)
+	for _, user := range users {
+		if user.FollowDate.IsZero() || (!user.Followed && user.UnfollowedAt.IsZero()) {
+			continue
+		}
+
+		name := group(user)
+		stats, ok := byGroup[name]
+		if !ok {
+			stats = &FollowBackStats{Group: name}
+			byGroup[name] = stats
+		}
+		stats.Followed++
+		if user.FollowedBackAt.IsZero() {
+			continue
+		}
+		stats.FollowedBack++
+		if !user.FollowedBack {
+			stats.Lost++
+		}
+		if delay := user.FollowedBackAt.Sub(user.FollowDate); delay >= 0 {
+			delays[name] = append(delays[name], delay)
+		}
+	}
+
+	result := make([]FollowBackStats, 0, len(byGroup))
+	for name, stats := range byGroup {
+		stats.MedianDelay = median(delays[name])
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Followed != result[j].Followed {
+			return result[i].Followed > result[j].Followed
+		}
+		return result[i].Group < result[j].Group
+	})
+	return result
+}
+
+// median returns the median of durations, 0 if there are none
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	mid := len(durations) / 2
+	if len(durations)%2 == 1 {
+		return durations[mid]
+	}
+	return (durations[mid-1] + durations[mid]) / 2
+}
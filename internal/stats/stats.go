@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+)
+
+// eventLimit bounds how much history a single summary reads
+const eventLimit = 100000
+
+// Summary is a snapshot of activity over a period, exposed to digest templates
+type Summary struct {
+	From time.Time
+	To   time.Time
+
+	// Activity within the period
+	FollowsPerformed   int
+	FollowsFailed      int
+	UnfollowsPerformed int
+	RecentFollows      []string // handles followed in the period, newest first
+
+	// Pipeline state at the time of the summary
+	TotalUsers    int
+	FollowedUsers int
+	PendingUsers  int
+}
+
+// FollowAttempts returns the number of follow attempts in the period
+func (s Summary) FollowAttempts() int {
+	return s.FollowsPerformed + s.FollowsFailed
+}
+
+// SuccessRate returns the fraction of follow attempts that succeeded
+func (s Summary) SuccessRate() float64 {
+	if s.FollowAttempts() == 0 {
+		return 0
+	}
+	return float64(s.FollowsPerformed) / float64(s.FollowAttempts())
+}
+
+// Compute builds a summary of everything that happened since the given time
+func Compute(ctx context.Context, store db.Store, since time.Time) (*Summary, error) {
+	summary := &Summary{
+		From: since,
+		To:   time.Now(),
+	}
+
+	followed := true
+	var err error
+	if summary.TotalUsers, err = store.CountUsers(ctx, db.UserFilter{}); err != nil {
+		return nil, err
+	}
+	if summary.FollowedUsers, err = store.CountUsers(ctx, db.UserFilter{Followed: &followed}); err != nil {
+		return nil, err
+	}
+	summary.PendingUsers = summary.TotalUsers - summary.FollowedUsers
+
+	events, err := store.LoadEvents(ctx, "", eventLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	for _, event := range events {
+		if event.CreatedAt.Before(since) {
+			// Events are newest first
+			break
+		}
+		switch {
+		case event.Action == models.ActionFollow && event.Result == models.ResultSuccess:
+			summary.FollowsPerformed++
+			summary.RecentFollows = append(summary.RecentFollows, event.Handle)
+		case event.Action == models.ActionFollow:
+			summary.FollowsFailed++
+		case event.Action == models.ActionUnfollow && event.Result == models.ResultSuccess:
+			summary.UnfollowsPerformed++
+		}
+	}
+
+	return summary, nil
+}
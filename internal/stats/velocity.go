@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// minVelocitySpan is the shortest history a growth rate is computed from
+const minVelocitySpan = time.Hour
+
+// Velocity returns the follower growth per day across a target's
+// observations, oldest first. It is zero with fewer than two observations or
+// when they span less than an hour.
+func Velocity(observations []models.FollowerObservation) float64 {
+	if len(observations) < 2 {
+		return 0
+	}
+	first, last := observations[0], observations[len(observations)-1]
+	span := last.ObservedAt.Sub(first.ObservedAt)
+	if span < minVelocitySpan {
+		return 0
+	}
+	return float64(last.Followers-first.Followers) / (span.Hours() / 24)
+}
+
+// Rising is a target's follower growth over a window
+type Rising struct {
+	DID          string
+	Handle       string  // filled in by the caller when known
+	Followers    int     // latest observed count
+	PerDay       float64 // followers gained per day
+	GrowthPerDay float64 // PerDay relative to the first observed count
+}
+
+// RisingTargets ranks targets by relative growth per day, given observations
+// ordered by DID and then oldest first. Targets that are not growing are left
+// out.
+func RisingTargets(observations []models.FollowerObservation) []Rising {
+	var rising []Rising
+	for start := 0; start < len(observations); {
+		end := start
+		for end < len(observations) && observations[end].DID == observations[start].DID {
+			end++
+		}
+
+		history := observations[start:end]
+		if perDay := Velocity(history); perDay > 0 {
+			r := Rising{
+				DID:       history[0].DID,
+				Followers: history[len(history)-1].Followers,
+				PerDay:    perDay,
+			}
+			if base := history[0].Followers; base > 0 {
+				r.GrowthPerDay = perDay / float64(base)
+			}
+			rising = append(rising, r)
+		}
+		start = end
+	}
+
+	sort.Slice(rising, func(i, j int) bool {
+		if rising[i].GrowthPerDay != rising[j].GrowthPerDay {
+			return rising[i].GrowthPerDay > rising[j].GrowthPerDay
+		}
+		return rising[i].PerDay > rising[j].PerDay
+	})
+	return rising
+}
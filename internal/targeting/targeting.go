@@ -0,0 +1,193 @@
+package targeting
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+)
+
+// Filter checks one thing about a candidate and returns why it is rejected,
+// or an empty string if it passes
+type Filter func(user models.TargetUser, now time.Time) string
+
+// Chain is an ordered list of filters. The first rejection wins.
+type Chain []Filter
+
+// NewChain builds the filter chain for the configured rules, cheapest checks
+// first
+func NewChain(rules models.TargetingRules) Chain {
+	var chain Chain
+	if rules.MinFollowers > 0 {
+		chain = append(chain, minFollowers(rules.MinFollowers))
+	}
+	if rules.MaxFollowers > 0 {
+		chain = append(chain, maxFollowers(rules.MaxFollowers))
+	}
+	if rules.MaxRatio > 0 {
+		chain = append(chain, maxRatio(rules.MaxRatio))
+	}
+	if rules.MinAge > 0 {
+		chain = append(chain, minAge(rules.MinAge))
+	}
+	if rules.RequireAvatar {
+		chain = append(chain, hasAvatar)
+	}
+	if rules.PostedWithin > 0 {
+		chain = append(chain, postedWithin(rules.PostedWithin))
+	}
+	if len(rules.BioInclude) > 0 {
+		chain = append(chain, bioIncludes(rules.BioInclude))
+	}
+	if len(rules.BioExclude) > 0 {
+		chain = append(chain, bioExcludes(rules.BioExclude))
+	}
+	if len(rules.Langs) > 0 {
+		chain = append(chain, postsIn(rules.Langs))
+	}
+	if len(rules.ExcludeLabels) > 0 {
+		chain = append(chain, withoutLabels(rules.ExcludeLabels))
+	}
+	return chain
+}
+
+// NeedsPosts reports whether the rules judge candidates by their posts, so
+// discovery should look up the recent posts of candidates it has none of
+func NeedsPosts(rules models.TargetingRules) bool {
+	return rules.PostedWithin > 0 || len(rules.Langs) > 0
+}
+
+// Check runs the chain over a candidate and returns the first rejection
+// reason, or an empty string if every filter passes
+func (c Chain) Check(user models.TargetUser, now time.Time) string {
+	for _, filter := range c {
+		if reason := filter(user, now); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+func minFollowers(n int) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		if user.Followers < n {
+			return fmt.Sprintf("fewer than %d followers", n)
+		}
+		return ""
+	}
+}
+
+func maxFollowers(n int) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		if user.Followers > n {
+			return fmt.Sprintf("more than %d followers", n)
+		}
+		return ""
+	}
+}
+
+func maxRatio(limit float64) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		if ratio := float64(user.Following) / float64(max(user.Followers, 1)); ratio > limit {
+			return fmt.Sprintf("follows %.1f accounts per follower, more than %g", ratio, limit)
+		}
+		return ""
+	}
+}
+
+func minAge(age time.Duration) Filter {
+	return func(user models.TargetUser, now time.Time) string {
+		if !user.AccountCreatedAt.IsZero() && now.Sub(user.AccountCreatedAt) < age {
+			return fmt.Sprintf("account younger than %d days", int(age.Hours()/24))
+		}
+		return ""
+	}
+}
+
+func hasAvatar(user models.TargetUser, _ time.Time) string {
+	if user.Avatar == "" {
+		return "no avatar"
+	}
+	return ""
+}
+
+func postedWithin(window time.Duration) Filter {
+	return func(user models.TargetUser, now time.Time) string {
+		if !user.LastPostAt.IsZero() && now.Sub(user.LastPostAt) > window {
+			return fmt.Sprintf("no post in the last %d days", int(window.Hours()/24))
+		}
+		return ""
+	}
+}
+
+func bioIncludes(keywords []string) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		bio := strings.ToLower(user.Description)
+		for _, keyword := range keywords {
+			if strings.Contains(bio, strings.ToLower(keyword)) {
+				return ""
+			}
+		}
+		return "bio mentions none of " + strings.Join(keywords, ", ")
+	}
+}
+
+func bioExcludes(keywords []string) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		bio := strings.ToLower(user.Description)
+		for _, keyword := range keywords {
+			if strings.Contains(bio, strings.ToLower(keyword)) {
+				return "bio mentions " + keyword
+			}
+		}
+		return ""
+	}
+}
+
+func postsIn(langs []string) Filter {
+	return func(user models.TargetUser, _ time.Time) string {
+		if len(user.Langs) == 0 {
+			return ""
+		}
+		for _, lang := range user.Langs {
+			if slices.ContainsFunc(langs, func(want string) bool { return matchesLang(lang, want) }) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("posts in %s, not %s", strings.Join(user.Langs, ", "), strings.Join(langs, ", "))
+	}
+}
+
+// AdultLabels are the adult-content labels the "adult" label rule stands for
+var AdultLabels = []string{"porn", "sexual", "nudity", "graphic-media"}
+
+func withoutLabels(labels []string) Filter {
+	var excluded []string
+	for _, label := range labels {
+		if strings.EqualFold(label, "adult") {
+			excluded = append(excluded, AdultLabels...)
+			continue
+		}
+		excluded = append(excluded, strings.ToLower(label))
+	}
+	return func(user models.TargetUser, _ time.Time) string {
+		for _, label := range user.Labels {
+			if slices.Contains(excluded, strings.ToLower(label)) {
+				return "labelled " + label
+			}
+		}
+		return ""
+	}
+}
+
+// matchesLang reports whether a post language tag such as "en-US" is the
+// wanted language, ignoring case and the region unless one is wanted
+func matchesLang(tag, want string) bool {
+	if strings.EqualFold(tag, want) {
+		return true
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	return strings.EqualFold(base, want)
+}
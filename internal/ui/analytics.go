@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"bsky_follower/internal/service"
+	"bsky_follower/internal/stats"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AnalyticsMsg carries follow-back analytics for one grouping
+type AnalyticsMsg struct {
+	By     string
+	Groups []stats.FollowBackStats
+	Error  error
+}
+
+// analyticsModel is the state of the follow-back analytics screen
+type analyticsModel struct {
+	by     string
+	groups []stats.FollowBackStats
+	loaded bool
+}
+
+func newAnalyticsModel() analyticsModel {
+	return analyticsModel{by: stats.ByStrategy}
+}
+
+// LoadAnalyticsCmd computes follow-back analytics for a grouping
+func LoadAnalyticsCmd(svc *service.Service, by string) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := svc.FollowBackAnalytics(by)
+		return AnalyticsMsg{By: by, Groups: groups, Error: err}
+	}
+}
+
+// updateAnalytics handles key presses on the analytics screen
+func (m Model) updateAnalytics(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a := &m.analytics
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+	case key.Matches(msg, m.keys.Group):
+		next := (slices.Index(stats.Groupings, a.by) + 1) % len(stats.Groupings)
+		a.by = stats.Groupings[next]
+		a.loaded = false
+		return m, LoadAnalyticsCmd(m.service, a.by)
+	case key.Matches(msg, m.keys.Refresh):
+		return m, LoadAnalyticsCmd(m.service, a.by)
+	}
+	return m, nil
+}
+
+// viewAnalytics renders the follow-back analytics screen
+func (m Model) viewAnalytics() string {
+	var sb strings.Builder
+	a := m.analytics
+
+	sb.WriteString(uiTitleStyle.Render("📈 Follow-back Analytics") + "\n")
+	sb.WriteString(uiSubtitleStyle.Render("How the accounts I followed responded, per "+a.by) + "\n\n")
+
+	switch {
+	case !a.loaded:
+		sb.WriteString(uiMenuItemStyle.Render("Loading...") + "\n")
+	case len(a.groups) == 0:
+		sb.WriteString(uiMenuItemStyle.Render("No follows recorded") + "\n")
+	default:
+		header := fmt.Sprintf("%-26s %8s %6s %6s %9s %6s", a.by, "followed", "back", "rate", "median", "churn")
+		sb.WriteString(uiSelectedMenuItemStyle.Render(header) + "\n")
+		for _, group := range a.groups {
+			line := fmt.Sprintf("%-26s %8d %6d %5.1f%% %9s %5.1f%%",
+				truncateLabel(group.Group, 26),
+				group.Followed,
+				group.FollowedBack,
+				group.Rate()*100,
+				group.Median(),
+				group.Churn()*100,
+			)
+			sb.WriteString(uiMenuItemStyle.Render(line) + "\n")
+		}
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	sb.WriteString("\n" + m.helpLine(as(m.keys.Group, "group by source, strategy or campaign"), m.keys.Refresh, m.keys.Back, m.keys.Help, m.keys.Quit))
+	return sb.String()
+}
+
+// truncateLabel shortens a label to at most n characters
+func truncateLabel(label string, n int) string {
+	runes := []rune(label)
+	if len(runes) <= n {
+		return label
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -1,8 +1,8 @@
 package ui
 
 import (
-	"bsky_follower/internal/api"
 	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -13,13 +13,14 @@ type AuthMsg struct {
 	Error   error
 }
 
-// AuthCmd represents an authentication command
-func AuthCmd(client *api.Client, identifier, password string) tea.Cmd {
+// AuthCmd represents an authentication command. The service verifies the
+// session identity so a re-auth can never switch accounts silently.
+func AuthCmd(svc *service.Service) tea.Cmd {
 	return func() tea.Msg {
-		session, err := client.Login(identifier, password)
+		session, err := svc.Login()
 		return AuthMsg{
 			Session: session,
 			Error:   err,
 		}
 	}
-} 
\ No newline at end of file
+}
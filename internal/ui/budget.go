@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// budgetRefreshInterval is how often the footer reloads the follow budget,
+// which also keeps its countdowns ticking
+const budgetRefreshInterval = 5 * time.Second
+
+// BudgetMsg carries what the follow limits leave to spend
+type BudgetMsg struct {
+	Budget service.FollowBudget
+	Error  error
+}
+
+// budgetTickMsg triggers a follow budget refresh
+type budgetTickMsg struct{}
+
+// LoadBudgetCmd loads what the follow limits leave to spend
+func LoadBudgetCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		budget, err := svc.FollowBudget(time.Now())
+		return BudgetMsg{Budget: budget, Error: err}
+	}
+}
+
+// budgetTickCmd schedules the next follow budget refresh
+func budgetTickCmd() tea.Cmd {
+	return tea.Tick(budgetRefreshInterval, func(time.Time) tea.Msg {
+		return budgetTickMsg{}
+	})
+}
+
+// budgetWait names what holds the next follow back, empty if nothing does
+func budgetWait(b service.FollowBudget, active, now time.Time) string {
+	switch {
+	case active.After(now):
+		return "quiet hours"
+	case !b.Next.After(now):
+		return ""
+	case b.Blocked.After(now):
+		return "server refused follows"
+	case b.PerWeek > 0 && b.WeekLeft == 0:
+		return "weekly cap"
+	case b.PerDay > 0 && b.DayLeft == 0:
+		return "daily cap"
+	case b.PerHour > 0 && b.HourLeft == 0:
+		return "hourly rate"
+	}
+	return "pacing"
+}
+
+// viewBudget renders the footer shown under every screen
+func (m Model) viewBudget() string {
+	if m.budget == nil {
+		return uiHelpStyle.Render("Budget: loading...")
+	}
+	now := time.Now()
+	line := budgetLine(*m.budget, m.service.NextActive(now), now)
+	if m.width > 0 {
+		line = truncateLabel(line, m.width)
+	}
+	return uiHelpStyle.Render(line)
+}
+
+// budgetLine sums up the follow budget left, the server's rate limit, and
+// when the next follow may happen given when the active hours next open
+func budgetLine(b service.FollowBudget, active, now time.Time) string {
+	parts := []string{"Budget: no hourly limit"}
+	if b.PerHour > 0 {
+		parts[0] = fmt.Sprintf("Budget: %d/%d this hour", b.HourLeft, b.PerHour)
+	}
+	if b.PerDay > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d today", b.DayLeft, b.PerDay))
+	}
+	if b.PerWeek > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d this week", b.WeekLeft, b.PerWeek))
+	}
+	if server := b.Server; server.Limit > 0 {
+		part := fmt.Sprintf("Server: %d/%d points", server.Remaining, server.Limit)
+		if server.Reset.After(now) {
+			part += ", resets in " + countdown(server.Reset, now)
+		}
+		parts = append(parts, part)
+	}
+
+	next := b.Next
+	if active.After(next) {
+		next = active
+	}
+	part := "Next follow: " + countdown(next, now)
+	if wait := budgetWait(b, active, now); wait != "" {
+		part += " (" + wait + ")"
+	}
+	parts = append(parts, part)
+	return strings.Join(parts, " • ")
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/queue"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmModel is a pending yes/no question asked before a batch action.
+// While it is set, every key press goes to it.
+type confirmModel struct {
+	title   string
+	summary []string
+	accept  func(Model) (Model, tea.Cmd) // what a yes does
+}
+
+// updateConfirm answers the pending question
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	switch {
+	case key.Matches(msg, m.keys.Yes):
+		m.confirm = nil
+		return c.accept(m)
+	case key.Matches(msg, m.keys.No):
+		m.confirm = nil
+		m.status = &StatusMsg{
+			Message: c.title + ": cancelled",
+			Type:    StatusInfo,
+			Time:    time.Now(),
+		}
+	}
+	return m, nil
+}
+
+// viewConfirm renders the pending question as a box
+func (m Model) viewConfirm() string {
+	c := m.confirm
+	var sb strings.Builder
+	sb.WriteString(uiTitleStyle.Render(c.title) + "\n\n")
+	for _, line := range c.summary {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n" + m.helpLine(m.keys.Yes, m.keys.No))
+	return boxStyle.Render(sb.String())
+}
+
+// queueRunConfirm asks before the queue is processed, summing up how many
+// follows are queued, how fast they go out and how long that takes
+func queueRunConfirm(stats queue.Stats, perHour int, dryRun bool) *confirmModel {
+	verb := "follow"
+	if dryRun {
+		verb = "simulate following"
+	}
+	summary := []string{fmt.Sprintf("About to %s up to %d queued users, %d of them due now.", verb, stats.Depth, stats.Ready)}
+	if perHour > 0 {
+		took := time.Duration(float64(stats.Depth) / float64(perHour) * float64(time.Hour)).Round(time.Minute)
+		summary = append(summary, fmt.Sprintf("At ~%d/hour that takes about %s, within the daily and weekly caps.", perHour, took))
+	}
+	summary = append(summary, "Processing stops when nothing is due, a pause is set or you press esc.")
+	return &confirmModel{title: "Process Follow Queue", summary: summary}
+}
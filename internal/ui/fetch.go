@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchLimit bounds how many pending users one fetch refreshes
+const fetchLimit = 500
+
+// FetchProgressMsg reports how many of the users a fetch refreshes are done
+type FetchProgressMsg struct {
+	Done  int
+	Total int
+}
+
+// FetchDoneMsg ends a fetch with the number of users refreshed
+type FetchDoneMsg struct {
+	Refreshed int
+	Error     error
+}
+
+// fetchModel is the state of a fetch running in the background
+type fetchModel struct {
+	active   bool
+	done     int
+	total    int
+	cancel   context.CancelFunc
+	progress <-chan FetchProgressMsg
+	bar      progress.Model
+}
+
+func newFetchModel() fetchModel {
+	return fetchModel{bar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(40))}
+}
+
+// start begins a fetch of the highest priority pending users' profiles,
+// returning the commands that run it and follow its progress
+func (f *fetchModel) start(svc *service.Service, session *models.Session) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan FetchProgressMsg, 1)
+	f.active, f.done, f.total = true, 0, 0
+	f.cancel = cancel
+	f.progress = updates
+	return tea.Batch(FetchUsersCmd(ctx, svc, session, updates), WaitForFetchProgressCmd(updates))
+}
+
+// stop ends the fetch's bookkeeping once it is done
+func (f *fetchModel) stop() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.active = false
+	f.cancel = nil
+	f.progress = nil
+}
+
+// FetchUsersCmd re-fetches and saves the profiles of the highest priority
+// pending users that are due a check, reporting progress on updates and
+// closing it when done
+func FetchUsersCmd(ctx context.Context, svc *service.Service, session *models.Session, updates chan FetchProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		defer close(updates)
+		refreshed, err := svc.RefreshProfiles(ctx, session, fetchLimit, func(done, total int) {
+			// Only the latest progress matters, so a stale update is dropped
+			// rather than holding the fetch up
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- FetchProgressMsg{Done: done, Total: total}
+		})
+		return FetchDoneMsg{Refreshed: refreshed, Error: err}
+	}
+}
+
+// WaitForFetchProgressCmd waits for the next progress update of a fetch. The
+// model issues it again after each update until the fetch closes the channel.
+func WaitForFetchProgressCmd(updates <-chan FetchProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return update
+	}
+}
+
+// fetchResult turns the end of a fetch into a status line
+func fetchResult(msg FetchDoneMsg, total int) StatusMsg {
+	switch {
+	case errors.Is(msg.Error, context.Canceled):
+		return StatusMsg{Message: fmt.Sprintf("Fetch cancelled, %d of %d users refreshed", msg.Refreshed, total), Type: StatusInfo}
+	case msg.Error != nil:
+		return StatusMsg{Message: fmt.Sprintf("Fetch failed after %d users: %v", msg.Refreshed, msg.Error), Type: StatusError}
+	case total == 0:
+		return StatusMsg{Message: "All pending users were checked in the last day", Type: StatusInfo}
+	default:
+		return StatusMsg{Message: fmt.Sprintf("Refreshed and saved %d users", msg.Refreshed), Type: StatusSuccess}
+	}
+}
+
+// view renders the progress of a running fetch after a spinner
+func (f fetchModel) view(spin string) string {
+	var sb strings.Builder
+	percent := 0.0
+	if f.total > 0 {
+		percent = float64(f.done) / float64(f.total)
+	}
+	sb.WriteString(uiMenuItemStyle.Render(fmt.Sprintf("%s Fetching profiles: %d of %d users", spin, f.done, f.total)) + "\n")
+	sb.WriteString(uiMenuItemStyle.Render(f.bar.ViewAs(percent)) + "\n")
+	return sb.String()
+}
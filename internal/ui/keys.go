@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyMap holds the key bindings of every screen. Text inputs always take
+// Enter to accept and esc to cancel, whatever the bindings say, so typing is
+// never mistaken for a command.
+type keyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+	Select    key.Binding
+	Toggle    key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	Help      key.Binding
+	Command   key.Binding
+	Refresh   key.Binding
+	Sort      key.Binding
+	Filter    key.Binding
+	Search    key.Binding
+	Group     key.Binding
+	Level     key.Binding
+	Add       key.Binding
+	Remove    key.Binding
+	Bump      key.Binding
+	Retry     key.Binding
+	Unfollow  key.Binding
+	Blocklist key.Binding
+	Protect   key.Binding
+	FollowNow key.Binding
+	Yes       key.Binding
+	No        key.Binding
+}
+
+// binding builds a key binding whose help shows its keys
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyLabel(keys), desc))
+}
+
+// keyLabel names a binding's keys for the help, e.g. "↑/k"
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			k = "↑"
+		case "down":
+			k = "↓"
+		case " ":
+			k = "space"
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:        binding("up", "up", "k"),
+		Down:      binding("down", "down", "j"),
+		PageUp:    binding("page up", "pgup"),
+		PageDown:  binding("page down", "pgdown"),
+		Top:       binding("top", "home", "g"),
+		Bottom:    binding("bottom", "end", "G"),
+		Select:    binding("select", "enter"),
+		Toggle:    binding("toggle", " "),
+		Back:      binding("back", "esc", "backspace"),
+		Quit:      binding("quit", "q"),
+		Help:      binding("help", "?"),
+		Command:   binding("command", ":"),
+		Refresh:   binding("refresh", "r"),
+		Sort:      binding("sort", "s"),
+		Filter:    binding("filter", "tab"),
+		Search:    binding("search", "/"),
+		Group:     binding("group by", "tab", "g"),
+		Level:     binding("level", "l", "tab"),
+		Add:       binding("add", "a"),
+		Remove:    binding("remove", "d", "x"),
+		Bump:      binding("bump priority", "+", "b"),
+		Retry:     binding("retry now", "r"),
+		Unfollow:  binding("unfollow", "u"),
+		Blocklist: binding("blocklist", "b"),
+		Protect:   binding("protect", "p"),
+		FollowNow: binding("follow now", "f"),
+		Yes:       binding("continue", "y", "Y", "enter"),
+		No:        binding("cancel", "n", "N", "esc", "q"),
+	}
+}
+
+// actions names the bindings for BSKY_KEYS
+func (k *keyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":         &k.Up,
+		"down":       &k.Down,
+		"page_up":    &k.PageUp,
+		"page_down":  &k.PageDown,
+		"top":        &k.Top,
+		"bottom":     &k.Bottom,
+		"select":     &k.Select,
+		"toggle":     &k.Toggle,
+		"back":       &k.Back,
+		"quit":       &k.Quit,
+		"help":       &k.Help,
+		"command":    &k.Command,
+		"refresh":    &k.Refresh,
+		"sort":       &k.Sort,
+		"filter":     &k.Filter,
+		"search":     &k.Search,
+		"group":      &k.Group,
+		"level":      &k.Level,
+		"add":        &k.Add,
+		"remove":     &k.Remove,
+		"bump":       &k.Bump,
+		"retry":      &k.Retry,
+		"unfollow":   &k.Unfollow,
+		"blocklist":  &k.Blocklist,
+		"protect":    &k.Protect,
+		"follow_now": &k.FollowNow,
+		"yes":        &k.Yes,
+		"no":         &k.No,
+	}
+}
+
+// newKeyMap returns the default bindings with the actions in remap bound to
+// their keys instead. Unknown actions are left out and reported.
+func newKeyMap(remap map[string][]string) (keyMap, error) {
+	k := defaultKeyMap()
+	actions := k.actions()
+
+	var unknown []string
+	for name, keys := range remap {
+		b, ok := actions[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keyLabel(keys), b.Help().Desc)
+	}
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(actions))
+		for name := range actions {
+			names = append(names, name)
+		}
+		sort.Strings(unknown)
+		sort.Strings(names)
+		return k, fmt.Errorf("unknown BSKY_KEYS actions %s (known actions: %s)", strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	return k, nil
+}
+
+// as returns a copy of a binding described for one screen, e.g. Remove as
+// "dequeue"
+func as(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// newHelp returns a help view without styles of its own, so it takes the
+// style it is rendered in
+func newHelp() help.Model {
+	h := help.New()
+	plain := lipgloss.NewStyle()
+	h.Styles = help.Styles{
+		Ellipsis:       plain,
+		ShortKey:       plain,
+		ShortDesc:      plain,
+		ShortSeparator: plain,
+		FullKey:        plain.Copy().Bold(true),
+		FullDesc:       plain,
+		FullSeparator:  plain,
+	}
+	return h
+}
+
+// helpLine renders the hint line of a screen from its bindings
+func (m Model) helpLine(bindings ...key.Binding) string {
+	return uiHelpStyle.Render(m.help.ShortHelpView(bindings))
+}
+
+// helpGroups returns the bindings the help overlay lists for the screen
+// showing, then those that work everywhere
+func (m Model) helpGroups() (string, [][]key.Binding) {
+	k := m.keys
+	nav := []key.Binding{k.Up, k.Down}
+	global := []key.Binding{k.Back, k.Help, k.Command, k.Quit}
+
+	switch m.screen {
+	case screenList:
+		return "Account list", [][]key.Binding{nav, {as(k.Add, "add account"), k.Remove}, global}
+	case screenAnalytics:
+		return "Follow-back analytics", [][]key.Binding{{as(k.Group, "group by source, strategy or campaign"), k.Refresh}, global}
+	case screenQueue:
+		return "Follow queue", [][]key.Binding{
+			{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
+			{as(k.Remove, "dequeue"), k.Bump, k.Retry},
+			global,
+		}
+	case screenUsers:
+		if m.users.detail != nil {
+			return "User details", [][]key.Binding{{k.Unfollow, as(k.Blocklist, "add to blocklist"), as(k.Protect, "protect from unfollows")}, global}
+		}
+		return "Users", [][]key.Binding{
+			{k.Up, k.Down, as(k.Select, "details")},
+			{as(k.Sort, "sort by priority, followers, saved or followed back"), as(k.Filter, "followed filter"), as(k.Search, "search handles"), k.Refresh},
+			global,
+		}
+	case screenLogs:
+		return "Logs", [][]key.Binding{
+			{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, as(k.Bottom, "follow newest")},
+			{as(k.Level, "cycle level")},
+			global,
+		}
+	case screenTimeline:
+		return "Activity timeline", [][]key.Binding{{k.Up, k.Down, as(k.Top, "newest"), k.Bottom, k.Refresh}, global}
+	case screenSettings:
+		return "Settings", [][]key.Binding{{k.Up, k.Down, as(k.Select, "edit or toggle"), k.Toggle}, global}
+	}
+	if m.quickAdd.lookup != nil {
+		return "Add account", [][]key.Binding{{as(k.Select, "queue"), k.FollowNow, as(k.No, "dismiss")}}
+	}
+	return "Menu", [][]key.Binding{{k.Up, k.Down, k.Select}, {as(k.Back, "cancel fetch or queue run"), k.Help, k.Command, k.Quit}}
+}
+
+// viewHelp renders the help overlay for the screen showing
+func (m Model) viewHelp() string {
+	title, groups := m.helpGroups()
+	var sb strings.Builder
+	sb.WriteString(uiTitleStyle.Render("⌨ Keys: "+title) + "\n\n")
+	sb.WriteString(m.help.FullHelpView(groups) + "\n\n")
+	sb.WriteString(uiSubtitleStyle.Render("Text inputs take Enter to accept and esc to cancel. Remap keys with BSKY_KEYS.") + "\n")
+	sb.WriteString(uiSubtitleStyle.Render("Errors on the status line stay until the next key press.") + "\n")
+	sb.WriteString("\n" + uiHelpStyle.Render("Any key: Close"))
+	return boxStyle.Render(sb.String())
+}
+
+// typing reports whether a text input has the keyboard, so keys are typed
+// rather than taken as commands
+func (m Model) typing() bool {
+	return m.schedule.active || m.palette.active ||
+		(m.quickAdd.active && m.quickAdd.lookup == nil) ||
+		(m.screen == screenList && m.list.adding) ||
+		(m.screen == screenUsers && m.users.searching) ||
+		(m.screen == screenSettings && m.settings.editing)
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listKind identifies which account list a list screen manages
+type listKind int
+
+const (
+	listBlocklist listKind = iota
+	listProtected
+)
+
+// ListMsg carries the current entries of an account list after a load or change
+type ListMsg struct {
+	Kind    listKind
+	Entries []models.BlocklistEntry
+	Message string
+	Error   error
+}
+
+// listModel is the state of an account list screen
+type listModel struct {
+	kind     listKind
+	title    string
+	subtitle string
+	empty    string
+	entries  []models.BlocklistEntry
+	cursor   int
+	input    textinput.Model
+	adding   bool
+}
+
+func newListModel(kind listKind) listModel {
+	input := textinput.New()
+	input.Placeholder = "handle or DID, optional reason"
+	input.CharLimit = 256
+
+	l := listModel{kind: kind, input: input}
+	switch kind {
+	case listBlocklist:
+		l.title = "🚫 Blocklist"
+		l.subtitle = "Accounts that are never queued or followed"
+		l.empty = "No blocklisted accounts"
+		l.input.Prompt = "Block: "
+	case listProtected:
+		l.title = "🛡 Protected Accounts"
+		l.subtitle = "Accounts that are never unfollowed or pruned"
+		l.empty = "No protected accounts"
+		l.input.Prompt = "Protect: "
+	}
+	return l
+}
+
+// load returns the entries of a list
+func (k listKind) load(svc *service.Service) ([]models.BlocklistEntry, error) {
+	if k == listProtected {
+		return svc.ProtectedAccounts()
+	}
+	return svc.Blocklist()
+}
+
+// LoadListCmd loads an account list from the service
+func LoadListCmd(svc *service.Service, kind listKind) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := kind.load(svc)
+		return ListMsg{Kind: kind, Entries: entries, Error: err}
+	}
+}
+
+// AddToListCmd adds an entry to an account list and reloads it
+func AddToListCmd(svc *service.Service, kind listKind, subject, reason string) tea.Cmd {
+	return func() tea.Msg {
+		add, done := svc.Block, "Blocklisted %s"
+		if kind == listProtected {
+			add, done = svc.Protect, "Protected %s"
+		}
+		if err := add(subject, reason); err != nil {
+			return ListMsg{Kind: kind, Error: err}
+		}
+		entries, err := kind.load(svc)
+		return ListMsg{Kind: kind, Entries: entries, Message: fmt.Sprintf(done, subject), Error: err}
+	}
+}
+
+// RemoveFromListCmd removes an entry from an account list and reloads it
+func RemoveFromListCmd(svc *service.Service, kind listKind, subject string) tea.Cmd {
+	return func() tea.Msg {
+		remove, done := svc.Unblock, "Removed %s from blocklist"
+		if kind == listProtected {
+			remove, done = svc.Unprotect, "Removed %s from protected accounts"
+		}
+		if err := remove(subject); err != nil {
+			return ListMsg{Kind: kind, Error: err}
+		}
+		entries, err := kind.load(svc)
+		return ListMsg{Kind: kind, Entries: entries, Message: fmt.Sprintf(done, subject), Error: err}
+	}
+}
+
+// updateList handles key presses on an account list screen
+func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.list
+
+	if l.adding {
+		switch msg.String() {
+		case "esc":
+			l.adding = false
+			l.input.Blur()
+			l.input.Reset()
+			return m, nil
+		case "enter":
+			subject, reason, _ := strings.Cut(strings.TrimSpace(l.input.Value()), " ")
+			l.adding = false
+			l.input.Blur()
+			l.input.Reset()
+			if subject == "" {
+				return m, nil
+			}
+			return m, AddToListCmd(m.service, l.kind, subject, strings.TrimSpace(reason))
+		}
+		var cmd tea.Cmd
+		l.input, cmd = l.input.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+	case key.Matches(msg, m.keys.Up):
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if l.cursor < len(l.entries)-1 {
+			l.cursor++
+		}
+	case key.Matches(msg, m.keys.Add):
+		l.adding = true
+		return m, l.input.Focus()
+	case key.Matches(msg, m.keys.Remove):
+		if l.cursor < len(l.entries) {
+			return m, RemoveFromListCmd(m.service, l.kind, l.entries[l.cursor].Subject)
+		}
+	}
+	return m, nil
+}
+
+// viewList renders an account list screen
+func (m Model) viewList() string {
+	var sb strings.Builder
+	l := m.list
+
+	sb.WriteString(uiTitleStyle.Render(l.title) + "\n")
+	sb.WriteString(uiSubtitleStyle.Render(l.subtitle) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	if len(l.entries) == 0 {
+		sb.WriteString(uiMenuItemStyle.Render(l.empty) + "\n")
+	}
+	for i, entry := range l.entries {
+		style := uiMenuItemStyle
+		if i == l.cursor {
+			style = uiSelectedMenuItemStyle
+		}
+		line := entry.Subject
+		if entry.Reason != "" {
+			line += " — " + entry.Reason
+		}
+		sb.WriteString(style.Render(line) + "\n")
+	}
+
+	if l.adding {
+		sb.WriteString("\n" + uiMenuItemStyle.Render(l.input.View()) + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	help := m.helpLine(m.keys.Up, m.keys.Down, m.keys.Add, m.keys.Remove, m.keys.Back, m.keys.Help, m.keys.Quit)
+	if l.adding {
+		help = uiHelpStyle.Render("Enter: Save • esc: Cancel")
+	}
+	sb.WriteString("\n" + help)
+	return sb.String()
+}
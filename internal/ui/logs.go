@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/logger"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// logsChrome is how many lines of the log screen sit outside the pane:
+	// title, subtitle, banners, status, help and footer
+	logsChrome = 11
+
+	// logsRefreshInterval is how often the log pane picks up new lines
+	logsRefreshInterval = time.Second
+
+	// logsKept is how many log lines the pane holds
+	logsKept = 1000
+)
+
+// logsTickMsg picks up the lines logged since the last one
+type logsTickMsg struct{}
+
+// logsModel is the state of the log screen
+type logsModel struct {
+	entries  []logger.Entry
+	last     uint64 // sequence number of the newest line read
+	level    string // least severe level shown
+	viewport viewport.Model
+}
+
+func newLogsModel(keys keyMap) logsModel {
+	vp := viewport.New(0, 0)
+	vp.KeyMap.Up, vp.KeyMap.Down = keys.Up, keys.Down
+	return logsModel{level: logger.LevelInfo, viewport: vp}
+}
+
+// logsTickCmd schedules the next pick-up of new log lines
+func logsTickCmd() tea.Cmd {
+	return tea.Tick(logsRefreshInterval, func(time.Time) tea.Msg {
+		return logsTickMsg{}
+	})
+}
+
+// resize fits the log pane to the terminal
+func (l *logsModel) resize(width, height int) {
+	l.viewport.Width = width
+	l.viewport.Height = max(height-logsChrome, 1)
+	l.render(l.viewport.AtBottom())
+}
+
+// refresh appends the lines logged since the last refresh. The pane keeps
+// following the newest line unless it was scrolled up.
+func (l *logsModel) refresh() {
+	entries := logger.Recent(l.last)
+	if len(entries) == 0 {
+		return
+	}
+	l.last = entries[len(entries)-1].Seq
+	l.entries = append(l.entries, entries...)
+	if len(l.entries) > logsKept {
+		l.entries = slices.Clone(l.entries[len(l.entries)-logsKept:])
+	}
+	l.render(l.viewport.AtBottom())
+}
+
+// render fills the pane with the lines at or above the level, scrolling to
+// the newest when tail is set
+func (l *logsModel) render(tail bool) {
+	var lines []string
+	for _, entry := range l.entries {
+		if entry.AtLeast(l.level) {
+			lines = append(lines, logLine(entry))
+		}
+	}
+	l.viewport.SetContent(strings.Join(lines, "\n"))
+	if tail {
+		l.viewport.GotoBottom()
+	}
+}
+
+// logLine renders one log line, colored by level
+func logLine(entry logger.Entry) string {
+	line := fmt.Sprintf("%s %-5s %s", entry.Time.Format("15:04:05"), entry.Level, entry.Message)
+	switch entry.Level {
+	case logger.LevelError:
+		return uiLogErrorStyle.Render(line)
+	case logger.LevelDebug:
+		return uiLogDebugStyle.Render(line)
+	}
+	return line
+}
+
+// updateLogs handles key presses on the log screen
+func (m Model) updateLogs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.logs
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+		return m, nil
+	case key.Matches(msg, m.keys.Level):
+		next := (slices.Index(logger.Levels, l.level) + 1) % len(logger.Levels)
+		l.level = logger.Levels[next]
+		l.render(true)
+		return m, nil
+	case key.Matches(msg, m.keys.Top):
+		l.viewport.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		l.viewport.GotoBottom()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	l.viewport, cmd = l.viewport.Update(msg)
+	return m, cmd
+}
+
+// viewLogs renders the log screen
+func (m Model) viewLogs() string {
+	var sb strings.Builder
+	l := m.logs
+
+	sb.WriteString(uiTitleStyle.Render("📜 Logs") + "\n")
+	subtitle := fmt.Sprintf("The latest %s lines and above", l.level)
+	if !l.viewport.AtBottom() {
+		subtitle += fmt.Sprintf(" • %.0f%%, scrolled up", l.viewport.ScrollPercent()*100)
+	}
+	sb.WriteString(uiSubtitleStyle.Render(subtitle) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	if len(l.entries) == 0 {
+		sb.WriteString(uiMenuItemStyle.Render("Nothing logged yet") + "\n")
+	} else {
+		sb.WriteString(l.viewport.View() + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	sb.WriteString("\n" + m.helpLine(m.keys.Up, m.keys.Down, as(m.keys.Bottom, "follow newest"), m.keys.Level, m.keys.Back, m.keys.Help, m.keys.Quit))
+	return sb.String()
+}
@@ -8,42 +8,166 @@ import (
 	"bsky_follower/internal/api"
 	"bsky_follower/internal/logger"
 	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+	"bsky_follower/internal/service"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// upcomingCount is how many upcoming follows the menu lists
+const upcomingCount = 5
+
+// screen identifies which view the model is showing
+type screen int
+
+const (
+	screenMenu screen = iota
+	screenList
+	screenAnalytics
+	screenQueue
+	screenUsers
+	screenLogs
+	screenSettings
+	screenTimeline
+)
+
+// Main menu entries
+const (
+	menuAuth = iota
+	menuFetchUsers
+	menuProcessQueue
+	menuScheduleFollow
+	menuQuickAdd
+	menuBlocklist
+	menuProtected
+	menuPause
+	menuQueuePause
+	menuDryRun
+	menuAnalytics
+	menuQueueView
+	menuUsers
+	menuLogs
+	menuSettings
+	menuTimeline
+	menuCount
+)
+
 type Model struct {
-	ready bool
-	width int
-	height int
+	ready         bool
+	width         int
+	height        int
 	authenticated bool
-	session *models.Session
-	menuIndex int
-	client *api.Client
-	config *models.Config
-	status *StatusMsg
-	queue *models.FollowQueue
+	session       *models.Session
+	menuIndex     int
+	client        *api.Client
+	config        *models.Config
+	status        *StatusMsg
+	queue         *queue.Queue
+	service       *service.Service
+	screen        screen
+	list          listModel
+	pause         *models.AccountPause
+	queuePaused   bool
+	budget        *service.FollowBudget
+	schedule      scheduleModel
+	quickAdd      quickAddModel
+	palette       paletteModel
+	analytics     analyticsModel
+	queueView     queueViewModel
+	users         usersModel
+	logs          logsModel
+	settings      settingsModel
+	timeline      timelineModel
+	fetch         fetchModel
+	queueRun      queueRunModel
+	spinner       spinner.Model
+	confirm       *confirmModel
+	keys          keyMap
+	help          help.Model
+	showHelp      bool
+	queueEvents   <-chan queue.Event
+	lastEvent     *queue.Event
 }
 
-func NewModel(config *models.Config) Model {
+func NewModel(config *models.Config, svc *service.Service) Model {
+	events, _ := svc.Queue().Subscribe()
+	keys, err := newKeyMap(config.Keys)
+	var status *StatusMsg
+	if err != nil {
+		status = &StatusMsg{
+			Message: err.Error(),
+			Type:    StatusError,
+			Time:    time.Now(),
+		}
+	}
 	return Model{
-		menuIndex: 0,
-		config: config,
-		client: api.NewClient(config.Timeout, logger.GetAPILogger()),
-		queue: &models.FollowQueue{},
+		menuIndex:   0,
+		config:      config,
+		service:     svc,
+		client:      api.NewClient(config.Timeout, logger.GetAPILogger()),
+		queue:       svc.Queue(),
+		list:        newListModel(listBlocklist),
+		schedule:    newScheduleModel(),
+		quickAdd:    newQuickAddModel(),
+		palette:     newPaletteModel(),
+		analytics:   newAnalyticsModel(),
+		queueView:   newQueueViewModel(),
+		users:       newUsersModel(keys),
+		logs:        newLogsModel(keys),
+		settings:    newSettingsModel(),
+		timeline:    newTimelineModel(keys),
+		fetch:       newFetchModel(),
+		spinner:     spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(uiStatusStyle.Copy().UnsetPaddingLeft())),
+		keys:        keys,
+		help:        newHelp(),
+		status:      status,
+		queueEvents: events,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(LoadPauseCmd(m.service), LoadQueuePauseCmd(m.service), pauseTickCmd(), LoadBudgetCmd(m.service), budgetTickCmd(), WaitForQueueEventCmd(m.queueEvents))
 }
 
+// Update handles a message, then keeps the status line: a key press
+// acknowledges an error shown, and a new success or info message is
+// dismissed after a few seconds, unless it reports the progress of a fetch
+// or queue run still going
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case statusExpireMsg:
+		if m.status == msg.status && !m.busy() {
+			m.status = nil
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.status != nil && m.status.Type == StatusError {
+			m.status = nil
+		}
+	}
+
+	shown := m.status
+	next, cmd := m.update(msg)
+	if m, ok := next.(Model); ok && m.status != shown && m.status != nil && m.status.Type != StatusError {
+		return m, tea.Batch(cmd, expireStatusCmd(m.status))
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
+		m.queueView.resize(msg.Width, msg.Height)
+		m.users.resize(msg.Height)
+		m.logs.resize(msg.Width, msg.Height)
+		m.timeline.resize(msg.Width, msg.Height)
+		m.help.Width = msg.Width
 		return m, nil
 
 	case AuthMsg:
@@ -65,6 +189,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case QueueMsg:
+		m.queueRun.stop()
 		if msg.Error != nil {
 			m.status = &StatusMsg{
 				Message: fmt.Sprintf("Queue processing failed: %v", msg.Error),
@@ -84,57 +209,498 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = &msg
 		return m, nil
 
+	case LookUpMsg:
+		if !m.quickAdd.looking {
+			return m, nil
+		}
+		m.quickAdd.looking = false
+		if msg.Error != nil {
+			m.quickAdd.close()
+			m.status = &StatusMsg{
+				Message: msg.Error.Error(),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.quickAdd.lookup = msg.Lookup
+		return m, nil
+
+	case QueueEventMsg:
+		m.lastEvent = &msg.Event
+		if m.screen == screenQueue {
+			return m, tea.Batch(LoadQueueViewCmd(m.service), WaitForQueueEventCmd(m.queueEvents))
+		}
+		return m, WaitForQueueEventCmd(m.queueEvents)
+
+	case QueueViewMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Queue update failed: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+		} else if msg.Message != "" {
+			m.status = &StatusMsg{
+				Message: msg.Message,
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+		}
+		m.queueView.setItems(msg.Items)
+		return m, nil
+
+	case UsersMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Failed to load users: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if msg.Query == m.users.query {
+			m.users.setUsers(msg.Users)
+		}
+		return m, nil
+
+	case UnfollowMutualMsg:
+		m.confirm = unfollowConfirm(msg.User, true, m.service.DryRun())
+		return m, nil
+
+	case UserActionMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: msg.Error.Error(),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.status = &StatusMsg{
+			Message: msg.Message,
+			Type:    StatusSuccess,
+			Time:    time.Now(),
+		}
+		if m.screen == screenUsers {
+			return m, LoadUsersCmd(m.service, m.users.query)
+		}
+		return m, nil
+
+	case QueueProgressMsg:
+		if !m.queueRun.active {
+			return m, nil
+		}
+		status := msg.Status
+		m.status = &status
+		return m, WaitForQueueProgressCmd(m.queueRun.progress)
+
+	case spinner.TickMsg:
+		if !m.busy() {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case FetchProgressMsg:
+		if !m.fetch.active {
+			return m, nil
+		}
+		m.fetch.done, m.fetch.total = msg.Done, msg.Total
+		return m, WaitForFetchProgressCmd(m.fetch.progress)
+
+	case FetchDoneMsg:
+		status := fetchResult(msg, m.fetch.total)
+		status.Time = time.Now()
+		m.status = &status
+		m.fetch.stop()
+		return m, nil
+
+	case SettingsMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Settings update failed: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+		} else if msg.Message != "" {
+			m.status = &StatusMsg{
+				Message: msg.Message,
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+		}
+		m.settings.settings = msg.Settings
+		return m, nil
+
+	case TimelineMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Failed to load the timeline: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.timeline.setEntries(msg.Entries)
+		return m, nil
+
+	case timelineTickMsg:
+		if m.screen == screenTimeline {
+			return m, tea.Batch(LoadTimelineCmd(m.service), timelineTickCmd())
+		}
+		return m, nil
+
+	case logsTickMsg:
+		if m.screen == screenLogs {
+			m.logs.refresh()
+			return m, logsTickCmd()
+		}
+		return m, nil
+
+	case queueViewTickMsg:
+		if m.screen == screenQueue {
+			return m, queueViewTickCmd()
+		}
+		return m, nil
+
+	case PauseMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Pause state update failed: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.pause = msg.Pause
+		if msg.Message != "" {
+			m.status = &StatusMsg{
+				Message: msg.Message,
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+		}
+		return m, nil
+
+	case QueuePauseMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Queue pause update failed: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.queuePaused = msg.Paused
+		if msg.Message != "" {
+			m.status = &StatusMsg{
+				Message: msg.Message,
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+		}
+		return m, nil
+
+	case pauseTickMsg:
+		return m, tea.Batch(LoadPauseCmd(m.service), LoadQueuePauseCmd(m.service), pauseTickCmd())
+
+	case BudgetMsg:
+		// A failed load keeps the last budget shown; the next tick retries
+		if msg.Error == nil {
+			m.budget = &msg.Budget
+		}
+		return m, nil
+
+	case budgetTickMsg:
+		return m, tea.Batch(LoadBudgetCmd(m.service), budgetTickCmd())
+
+	case ListMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("List update failed: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if msg.Kind != m.list.kind {
+			return m, nil
+		}
+		m.list.entries = msg.Entries
+		if m.list.cursor >= len(msg.Entries) {
+			m.list.cursor = max(len(msg.Entries)-1, 0)
+		}
+		if msg.Message != "" {
+			m.status = &StatusMsg{
+				Message: msg.Message,
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+		}
+		return m, nil
+
+	case AnalyticsMsg:
+		if msg.Error != nil {
+			m.status = &StatusMsg{
+				Message: fmt.Sprintf("Failed to load analytics: %v", msg.Error),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if msg.By == m.analytics.by {
+			m.analytics.groups = msg.Groups
+			m.analytics.loaded = true
+		}
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
-		case "up", "k":
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		if m.palette.active {
+			return m.updatePalette(msg)
+		}
+		if !m.typing() && key.Matches(msg, m.keys.Help) {
+			m.showHelp = true
+			return m, nil
+		}
+		if !m.typing() && key.Matches(msg, m.keys.Command) {
+			m.palette.active = true
+			return m, m.palette.input.Focus()
+		}
+		quit := key.Matches(msg, m.keys.Quit)
+		if m.screen == screenList && (m.list.adding || !quit) {
+			return m.updateList(msg)
+		}
+		if m.screen == screenAnalytics && !quit {
+			return m.updateAnalytics(msg)
+		}
+		if m.screen == screenQueue && !quit {
+			return m.updateQueueView(msg)
+		}
+		if m.screen == screenUsers && (m.users.searching || !quit) {
+			return m.updateUsers(msg)
+		}
+		if m.screen == screenSettings && (m.settings.editing || !quit) {
+			return m.updateSettings(msg)
+		}
+		if m.screen == screenLogs && !quit {
+			return m.updateLogs(msg)
+		}
+		if m.screen == screenTimeline && !quit {
+			return m.updateTimeline(msg)
+		}
+		if m.schedule.active {
+			return m.updateSchedule(msg)
+		}
+		if m.quickAdd.active {
+			return m.updateQuickAdd(msg)
+		}
+
+		switch {
+		case quit:
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Back):
+			if m.fetch.active && m.fetch.cancel != nil {
+				m.fetch.cancel()
+			}
+			if m.queueRun.active && m.queueRun.cancel != nil {
+				m.queueRun.cancel()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Up):
 			if m.menuIndex > 0 {
 				m.menuIndex--
 			}
 			return m, nil
-		case "down", "j":
-			if m.menuIndex < 2 {
+		case key.Matches(msg, m.keys.Down):
+			if m.menuIndex < menuCount-1 {
 				m.menuIndex++
 			}
 			return m, nil
-		case "enter":
-			switch m.menuIndex {
-			case 0: // Authenticate/Logout
-				if m.authenticated {
-					m.authenticated = false
-					m.session = nil
-					m.status = &StatusMsg{
-						Message: "Successfully logged out",
-						Type:    StatusSuccess,
-						Time:    time.Now(),
-					}
-					return m, nil
-				}
-				return m, AuthCmd(m.client, m.config.Identifier, m.config.Password)
-			case 1: // Fetch Users
-				if !m.authenticated {
-					m.status = &StatusMsg{
-						Message: "Please authenticate first",
-						Type:    StatusError,
-						Time:    time.Now(),
-					}
-					return m, nil
+		case key.Matches(msg, m.keys.Select):
+			return m.selectMenuItem(m.menuIndex)
+		}
+	}
+	return m, nil
+}
+
+// selectMenuItem runs a menu item, as Enter does on the menu
+func (m Model) selectMenuItem(item int) (tea.Model, tea.Cmd) {
+	switch item {
+	case menuAuth:
+		if m.authenticated {
+			if err := m.service.Logout(m.session); err != nil {
+				m.status = &StatusMsg{
+					Message: fmt.Sprintf("Failed to clear stored session: %v", err),
+					Type:    StatusError,
+					Time:    time.Now(),
 				}
-				// TODO: Implement fetch users
 				return m, nil
-			case 2: // Process Queue
-				if !m.authenticated {
-					m.status = &StatusMsg{
-						Message: "Please authenticate first",
-						Type:    StatusError,
-						Time:    time.Now(),
-					}
-					return m, nil
-				}
-				return m, QueueCmd(m.client, m.session, m.queue)
 			}
+			m.authenticated = false
+			m.session = nil
+			m.status = &StatusMsg{
+				Message: "Successfully logged out",
+				Type:    StatusSuccess,
+				Time:    time.Now(),
+			}
+			return m, nil
 		}
+		return m, AuthCmd(m.service)
+	case menuFetchUsers:
+		if !m.authenticated {
+			m.status = &StatusMsg{
+				Message: "Please authenticate first",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if m.fetch.active {
+			m.status = &StatusMsg{
+				Message: "A fetch is already running, esc cancels it",
+				Type:    StatusInfo,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.status = nil
+		tick := m.spinnerCmd()
+		return m, tea.Batch(m.fetch.start(m.service, m.session), tick)
+	case menuProcessQueue:
+		if !m.authenticated {
+			m.status = &StatusMsg{
+				Message: "Please authenticate first",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if m.pause != nil {
+			m.status = &StatusMsg{
+				Message: "Account is paused; resume it to process the queue",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if m.queuePaused {
+			m.status = &StatusMsg{
+				Message: "Follow queue is paused; resume it to process the queue",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		if m.queueRun.active {
+			m.status = &StatusMsg{
+				Message: "The queue is already being processed, esc stops it",
+				Type:    StatusInfo,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		perHour, _ := m.service.HourlyRates()
+		m.confirm = queueRunConfirm(m.queue.Stats(time.Now()), perHour, m.service.DryRun())
+		m.confirm.accept = func(m Model) (Model, tea.Cmd) {
+			m.status = &StatusMsg{
+				Message: "Processing the follow queue",
+				Type:    StatusInfo,
+				Time:    time.Now(),
+			}
+			tick := m.spinnerCmd()
+			return m, tea.Batch(m.queueRun.start(m.service, m.session), tick)
+		}
+		return m, nil
+	case menuScheduleFollow:
+		if !m.authenticated {
+			m.status = &StatusMsg{
+				Message: "Please authenticate first",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.schedule.active = true
+		return m, m.schedule.input.Focus()
+	case menuQuickAdd:
+		if !m.authenticated {
+			m.status = &StatusMsg{
+				Message: "Please authenticate first",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.quickAdd.active = true
+		return m, m.quickAdd.input.Focus()
+	case menuBlocklist:
+		m.screen = screenList
+		m.list = newListModel(listBlocklist)
+		m.status = nil
+		return m, LoadListCmd(m.service, listBlocklist)
+	case menuProtected:
+		m.screen = screenList
+		m.list = newListModel(listProtected)
+		m.status = nil
+		return m, LoadListCmd(m.service, listProtected)
+	case menuPause:
+		return m, TogglePauseCmd(m.service, m.pause != nil)
+	case menuQueuePause:
+		return m, ToggleQueuePauseCmd(m.service, m.queuePaused)
+	case menuDryRun:
+		m.service.SetDryRun(!m.service.DryRun())
+		message := "Dry run off; follows are real again"
+		if m.service.DryRun() {
+			message = "Dry run on; follows are only simulated"
+		}
+		m.status = &StatusMsg{
+			Message: message,
+			Type:    StatusInfo,
+			Time:    time.Now(),
+		}
+		return m, nil
+	case menuAnalytics:
+		m.screen = screenAnalytics
+		m.analytics.loaded = false
+		m.status = nil
+		return m, LoadAnalyticsCmd(m.service, m.analytics.by)
+	case menuQueueView:
+		m.screen = screenQueue
+		m.queueView.loaded = false
+		m.status = nil
+		return m, tea.Batch(LoadQueueViewCmd(m.service), queueViewTickCmd())
+	case menuUsers:
+		m.screen = screenUsers
+		m.users.loaded = false
+		m.users.detail = nil
+		m.status = nil
+		return m, LoadUsersCmd(m.service, m.users.query)
+	case menuSettings:
+		m.screen = screenSettings
+		m.status = nil
+		return m, LoadSettingsCmd(m.service)
+	case menuTimeline:
+		m.screen = screenTimeline
+		m.timeline.loaded = false
+		m.status = nil
+		return m, tea.Batch(LoadTimelineCmd(m.service), timelineTickCmd())
+	case menuLogs:
+		m.screen = screenLogs
+		m.status = nil
+		m.logs.refresh()
+		return m, logsTickCmd()
 	}
 	return m, nil
 }
@@ -143,6 +709,39 @@ func (m Model) View() string {
 	if !m.ready {
 		return "Initializing..."
 	}
+	if m.showHelp {
+		return m.viewHelp()
+	}
+	// The command bar takes the footer's place while open
+	if m.palette.active {
+		return m.viewScreen() + "\n" + m.palette.view()
+	}
+	return m.viewScreen() + "\n" + m.viewBudget()
+}
+
+// viewScreen renders the screen showing, without the footer
+func (m Model) viewScreen() string {
+	if m.screen == screenList {
+		return m.viewList()
+	}
+	if m.screen == screenAnalytics {
+		return m.viewAnalytics()
+	}
+	if m.screen == screenQueue {
+		return m.viewQueueView()
+	}
+	if m.screen == screenUsers {
+		return m.viewUsers()
+	}
+	if m.screen == screenLogs {
+		return m.viewLogs()
+	}
+	if m.screen == screenSettings {
+		return m.viewSettings()
+	}
+	if m.screen == screenTimeline {
+		return m.viewTimeline()
+	}
 
 	var b strings.Builder
 
@@ -154,15 +753,51 @@ func (m Model) View() string {
 	subtitle := uiSubtitleStyle.Render("Automated follower management for Bluesky")
 	b.WriteString(subtitle + "\n\n")
 
+	// Pause state
+	if banner := pauseBanner(m.pause); banner != "" {
+		b.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		b.WriteString(banner + "\n")
+	}
+	if banner := dryRunBanner(m.service); banner != "" {
+		b.WriteString(banner + "\n")
+	}
+	if banner := quietHoursBanner(m.service, time.Now()); banner != "" {
+		b.WriteString(banner + "\n")
+	}
+
 	// Menu
 	menuItems := []string{
 		"Authenticate to BlueSky",
 		"Fetch and Save Top Users",
 		"Process Follow Queue",
+		"Schedule Follow",
+		"Add Account",
+		"Manage Blocklist",
+		"Manage Protected Accounts",
+		"Pause Account",
+		"Pause Follow Queue",
+		"Enable Dry Run",
+		"Follow-back Analytics",
+		"View Follow Queue",
+		"Browse Users",
+		"View Logs",
+		"Settings",
+		"Activity Timeline",
 	}
 
 	if m.authenticated {
-		menuItems[0] = fmt.Sprintf("Logout from BlueSky (%s)", m.session.Handle)
+		menuItems[menuAuth] = fmt.Sprintf("Logout from BlueSky (%s)", m.session.Handle)
+	}
+	if m.pause != nil {
+		menuItems[menuPause] = "Resume Account"
+	}
+	if m.queuePaused {
+		menuItems[menuQueuePause] = "Resume Follow Queue"
+	}
+	if m.service.DryRun() {
+		menuItems[menuDryRun] = "Disable Dry Run"
 	}
 
 	for i, item := range menuItems {
@@ -170,16 +805,32 @@ func (m Model) View() string {
 		if i == m.menuIndex {
 			style = uiSelectedMenuItemStyle
 		}
-		if !m.authenticated && i > 0 {
+		if !m.authenticated && (i == menuFetchUsers || i == menuProcessQueue || i == menuScheduleFollow || i == menuQuickAdd) {
 			style = uiDisabledMenuItemStyle
 		}
 		b.WriteString(style.Render(item) + "\n")
 	}
+	if m.schedule.active {
+		b.WriteString("\n" + uiMenuItemStyle.Render(m.schedule.input.View()) + "\n")
+	}
+	if m.quickAdd.active {
+		k := m.keys
+		b.WriteString("\n" + m.quickAdd.view(m.helpLine(as(k.Select, "queue"), k.FollowNow, as(k.No, "dismiss"))))
+	}
+	if m.fetch.active {
+		b.WriteString("\n" + m.fetch.view(m.spinner.View()))
+	}
+	if m.confirm != nil {
+		b.WriteString(m.viewConfirm() + "\n")
+	}
 
 	// Status
 	b.WriteString("\n")
 	if m.status != nil {
 		status := uiStatusStyle.Render(FormatStatus(*m.status))
+		if m.busy() {
+			status = uiStatusStyle.Render(m.spinner.View() + " " + FormatStatus(*m.status))
+		}
 		b.WriteString(status + "\n")
 	} else if m.authenticated {
 		status := uiStatusStyle.Render(fmt.Sprintf("Authenticated as: %s", m.session.Handle))
@@ -189,15 +840,47 @@ func (m Model) View() string {
 		b.WriteString(status + "\n")
 	}
 
+	// Session age
+	if m.authenticated && !m.session.CreatedAt.IsZero() {
+		age := time.Since(m.session.CreatedAt).Round(time.Minute)
+		b.WriteString(uiStatusStyle.Render(fmt.Sprintf("Session age: %s", age)) + "\n")
+	}
+
 	// Queue status
 	if m.queue != nil {
-		queueStatus := uiStatusStyle.Render(fmt.Sprintf("Queue size: %d", m.queue.Len()))
-		b.WriteString(queueStatus + "\n")
+		for _, line := range queueStatsLines(m.queue.Stats(time.Now()), m.lastEvent, time.Now()) {
+			b.WriteString(uiStatusStyle.Render(line) + "\n")
+		}
+		for _, line := range upcomingLines(m.queue.PeekN(upcomingCount), time.Now()) {
+			b.WriteString(uiStatusStyle.Render(line) + "\n")
+		}
 	}
 
 	// Help
-	help := uiHelpStyle.Render("↑/↓: Navigate • Enter: Select • q: Quit")
+	k := m.keys
+	help := m.helpLine(k.Up, k.Down, k.Select, k.Help, k.Command, k.Quit)
+	if m.schedule.active {
+		help = uiHelpStyle.Render("Enter: Schedule • esc: Cancel")
+	} else if m.quickAdd.active && m.quickAdd.lookup == nil {
+		help = uiHelpStyle.Render("Enter: Look up • esc: Cancel")
+	} else if m.busy() {
+		help = m.helpLine(k.Up, k.Down, k.Select, as(k.Back, "cancel"), k.Help, k.Command, k.Quit)
+	}
 	b.WriteString("\n" + help)
 
 	return b.String()
-} 
\ No newline at end of file
+}
+
+// busy reports whether a fetch or queue run is going on in the background
+func (m Model) busy() bool {
+	return m.fetch.active || m.queueRun.active
+}
+
+// spinnerCmd starts the spinner unless a background operation already keeps
+// it turning
+func (m Model) spinnerCmd() tea.Cmd {
+	if m.busy() {
+		return nil
+	}
+	return m.spinner.Tick
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/review"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteMenuItems are the palette commands that run a menu item as it is
+var paletteMenuItems = map[string]int{
+	"auth":      menuAuth,
+	"fetch":     menuFetchUsers,
+	"process":   menuProcessQueue,
+	"schedule":  menuScheduleFollow,
+	"add":       menuQuickAdd,
+	"blocklist": menuBlocklist,
+	"protected": menuProtected,
+	"analytics": menuAnalytics,
+	"users":     menuUsers,
+	"logs":      menuLogs,
+	"settings":  menuSettings,
+	"timeline":  menuTimeline,
+}
+
+// paletteCommands lists what the command bar takes, for its hints and errors
+var paletteCommands = []string{
+	"add", "analytics", "auth", "blocklist", "dry-run on|off",
+	"export csv|json [file]", "fetch",
+	"filter followed=true|false|back [sort=...] [search=...]",
+	"follow <handle>", "logs", "menu", "pause", "pause queue", "process",
+	"protected", "queue", "queue <handle>", "quit", "resume", "resume queue",
+	"schedule", "settings", "timeline", "users",
+}
+
+// paletteModel is the state of the command bar, which ':' opens on any
+// screen in place of the footer
+type paletteModel struct {
+	input  textinput.Model
+	active bool
+}
+
+func newPaletteModel() paletteModel {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "command, e.g. follow alice.bsky.social"
+	input.CharLimit = 256
+	return paletteModel{input: input}
+}
+
+// close hides the command bar and clears it
+func (p *paletteModel) close() {
+	p.active = false
+	p.input.Blur()
+	p.input.Reset()
+}
+
+// ManualFollowCmd looks up an account and follows it right away, or queues
+// it when now is false, like the add account input without the profile card
+func ManualFollowCmd(svc *service.Service, session *models.Session, subject string, now bool) tea.Cmd {
+	return func() tea.Msg {
+		lookup, err := svc.LookUpAccount(session, subject)
+		if err != nil {
+			return StatusMsg{Message: err.Error(), Type: StatusError, Time: time.Now()}
+		}
+		return AddAccountCmd(svc, session, *lookup, now)()
+	}
+}
+
+// ExportCandidatesCmd writes the pending candidates to a file for review, as
+// the export-candidates command does
+func ExportCandidatesCmd(svc *service.Service, path, format string) tea.Cmd {
+	return func() tea.Msg {
+		fail := func(err error) tea.Msg {
+			return StatusMsg{Message: fmt.Sprintf("Export failed: %v", err), Type: StatusError, Time: time.Now()}
+		}
+		pending := false
+		users, err := svc.QueryUsers(db.UserFilter{Followed: &pending}, 0)
+		if err != nil {
+			return fail(err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return fail(err)
+		}
+		defer f.Close()
+		if err := review.ExportCandidates(f, users, format); err != nil {
+			return fail(err)
+		}
+		return StatusMsg{Message: fmt.Sprintf("Exported %d candidates to %s", len(users), path), Type: StatusSuccess, Time: time.Now()}
+	}
+}
+
+// updatePalette handles key presses while the command bar is open
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.palette
+	switch msg.String() {
+	case "esc":
+		p.close()
+		return m, nil
+	case "enter":
+		line := strings.TrimSpace(p.input.Value())
+		p.close()
+		if line == "" {
+			return m, nil
+		}
+		return m.runPaletteCommand(line)
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return m, cmd
+}
+
+// runPaletteCommand runs a command typed into the command bar
+func (m Model) runPaletteCommand(line string) (tea.Model, tea.Cmd) {
+	fields := strings.Fields(line)
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	fail := func(format string, a ...any) (tea.Model, tea.Cmd) {
+		m.status = &StatusMsg{Message: fmt.Sprintf(format, a...), Type: StatusError, Time: time.Now()}
+		return m, nil
+	}
+	needsAuth := func() bool {
+		if !m.authenticated {
+			m.status = &StatusMsg{Message: "Please authenticate first", Type: StatusError, Time: time.Now()}
+		}
+		return !m.authenticated
+	}
+	// "queue" alone opens the queue screen, with a handle it queues one
+	if name == "queue" && len(args) == 0 {
+		m.screen = screenMenu
+		m.menuIndex = menuQueueView
+		return m.selectMenuItem(menuQueueView)
+	}
+
+	switch name {
+	case "follow", "queue":
+		if len(args) != 1 {
+			return fail("Usage: %s <handle>", name)
+		}
+		if needsAuth() {
+			return m, nil
+		}
+		return m, ManualFollowCmd(m.service, m.session, args[0], name == "follow")
+	case "pause", "resume":
+		// The toggles resume when told the account or queue is paused
+		resume := name == "resume"
+		switch {
+		case len(args) == 1 && args[0] == "queue":
+			if m.queuePaused != resume {
+				return fail("Follow queue is already %sd", name)
+			}
+			return m, ToggleQueuePauseCmd(m.service, resume)
+		case len(args) == 0:
+			if (m.pause != nil) != resume {
+				return fail("Account is already %sd", name)
+			}
+			return m, TogglePauseCmd(m.service, resume)
+		}
+		return fail("Usage: %s [queue]", name)
+	case "dry-run":
+		if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+			return fail("Usage: dry-run on|off")
+		}
+		if m.service.DryRun() == (args[0] == "on") {
+			return fail("Dry run is already %s", args[0])
+		}
+		return m.selectMenuItem(menuDryRun)
+	case "export":
+		if len(args) == 0 || len(args) > 2 || (args[0] != review.FormatCSV && args[0] != review.FormatJSON) {
+			return fail("Usage: export csv|json [file]")
+		}
+		path := "candidates." + args[0]
+		if len(args) == 2 {
+			path = args[1]
+		}
+		return m, ExportCandidatesCmd(m.service, path, args[0])
+	case "filter":
+		return m.runPaletteFilter(args)
+	case "menu":
+		m.screen = screenMenu
+		return m, nil
+	case "quit":
+		return m, tea.Quit
+	}
+
+	item, ok := paletteMenuItems[name]
+	if !ok || len(args) > 0 {
+		return fail("Unknown command %q; commands: %s", name, strings.Join(paletteCommands, ", "))
+	}
+	if item == menuAuth && m.authenticated {
+		return fail("Already authenticated as %s; log out from the menu", m.session.Handle)
+	}
+	m.screen = screenMenu
+	m.menuIndex = item
+	return m.selectMenuItem(item)
+}
+
+// runPaletteFilter opens the users screen narrowed by key=value terms:
+// followed=true|false|back|all, sort=<order> and search=<text>. The order
+// stays as it was unless given; the rest is cleared.
+func (m Model) runPaletteFilter(args []string) (tea.Model, tea.Cmd) {
+	query := usersQuery{sort: m.users.query.sort, state: usersAll}
+	for _, arg := range args {
+		k, v, _ := strings.Cut(arg, "=")
+		switch strings.ToLower(k) {
+		case "followed":
+			switch strings.ToLower(v) {
+			case "true", "yes":
+				query.state = usersFollowed
+			case "false", "no":
+				query.state = usersNotFollowed
+			case "back":
+				query.state = usersFollowedBack
+			case "all":
+				query.state = usersAll
+			default:
+				m.status = &StatusMsg{Message: fmt.Sprintf("Unknown followed filter %q: use true, false, back or all", v), Type: StatusError, Time: time.Now()}
+				return m, nil
+			}
+		case "sort":
+			order := strings.ReplaceAll(strings.ToLower(v), "_", " ")
+			if !slices.Contains(db.UserSorts, order) {
+				m.status = &StatusMsg{Message: fmt.Sprintf("Unknown sort %q: use %s", v, strings.Join(db.UserSorts, ", ")), Type: StatusError, Time: time.Now()}
+				return m, nil
+			}
+			query.sort = order
+		case "search":
+			query.search = v
+		default:
+			m.status = &StatusMsg{Message: fmt.Sprintf("Unknown filter %q: use followed=, sort= or search=", arg), Type: StatusError, Time: time.Now()}
+			return m, nil
+		}
+	}
+
+	m.users.query = query
+	m.users.search.SetValue(query.search)
+	m.screen = screenUsers
+	m.users.loaded = false
+	m.users.detail = nil
+	m.status = nil
+	return m, LoadUsersCmd(m.service, query)
+}
+
+// view renders the command bar with the commands matching what is typed
+func (p paletteModel) view() string {
+	line := p.input.View()
+	if typed := strings.ToLower(strings.TrimSpace(p.input.Value())); typed != "" && !strings.Contains(typed, " ") {
+		var matches []string
+		for _, command := range paletteCommands {
+			if strings.HasPrefix(command, typed) {
+				matches = append(matches, command)
+			}
+		}
+		if len(matches) > 0 {
+			line += "  " + uiHelpStyle.Render(strings.Join(matches, " • "))
+		}
+	}
+	return line
+}
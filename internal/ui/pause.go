@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pauseRefreshInterval is how often the UI rechecks the account's pause state,
+// which a safety trigger in the daemon may change at any time
+const pauseRefreshInterval = 30 * time.Second
+
+// PauseMsg carries the account's current pause state
+type PauseMsg struct {
+	Pause   *models.AccountPause
+	Message string
+	Error   error
+}
+
+// QueuePauseMsg carries whether follow queue processing is paused
+type QueuePauseMsg struct {
+	Paused  bool
+	Message string
+	Error   error
+}
+
+// pauseTickMsg triggers a pause state refresh
+type pauseTickMsg struct{}
+
+// LoadPauseCmd loads the account's pause state
+func LoadPauseCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		pause, err := svc.AccountPause("")
+		return PauseMsg{Pause: pause, Error: err}
+	}
+}
+
+// LoadQueuePauseCmd loads whether follow queue processing is paused
+func LoadQueuePauseCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		paused, err := svc.QueuePaused()
+		return QueuePauseMsg{Paused: paused, Error: err}
+	}
+}
+
+// ToggleQueuePauseCmd pauses follow queue processing, or resumes it if paused
+func ToggleQueuePauseCmd(svc *service.Service, paused bool) tea.Cmd {
+	return func() tea.Msg {
+		if paused {
+			if err := svc.ResumeQueue(); err != nil {
+				return QueuePauseMsg{Paused: true, Error: err}
+			}
+			return QueuePauseMsg{Message: "Follow queue resumed"}
+		}
+		if err := svc.PauseQueue(); err != nil {
+			return QueuePauseMsg{Error: err}
+		}
+		return QueuePauseMsg{Paused: true, Message: "Follow queue paused; queued items are kept"}
+	}
+}
+
+// pauseTickCmd schedules the next pause state refresh
+func pauseTickCmd() tea.Cmd {
+	return tea.Tick(pauseRefreshInterval, func(time.Time) tea.Msg {
+		return pauseTickMsg{}
+	})
+}
+
+// TogglePauseCmd pauses the account until resumed, or resumes it if paused
+func TogglePauseCmd(svc *service.Service, paused bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		message := "Account resumed"
+		if paused {
+			err = svc.ResumeAccount("")
+		} else {
+			err = svc.PauseAccount("", "paused from the UI", models.PauseManual, 0)
+			message = "Account paused; no follows or unfollows until resumed"
+		}
+		if err != nil {
+			return PauseMsg{Error: err}
+		}
+
+		pause, err := svc.AccountPause("")
+		return PauseMsg{Pause: pause, Message: message, Error: err}
+	}
+}
+
+// pauseBanner describes an active pause for the top of every screen
+func pauseBanner(pause *models.AccountPause) string {
+	if pause == nil {
+		return ""
+	}
+
+	until := "until resumed"
+	if !pause.Until.IsZero() {
+		until = "until " + pause.Until.Local().Format("Jan 2 15:04")
+	}
+	return uiPausedStyle.Render(fmt.Sprintf("⏸ Account paused (%s) %s: %s", pause.Source, until, pause.Reason)) + "\n"
+}
+
+// queuePauseBanner notes a paused follow queue at the top of every screen
+func queuePauseBanner(paused bool) string {
+	if !paused {
+		return ""
+	}
+	return uiPausedStyle.Render("⏸ Follow queue paused") + "\n"
+}
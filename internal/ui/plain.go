@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"bsky_follower/internal/service"
+)
+
+// plainLabels name the status types in plain output, which keeps to ASCII
+// for dumb terminals and log files
+var plainLabels = map[StatusType]string{
+	StatusInfo:    "INFO ",
+	StatusSuccess: "OK   ",
+	StatusError:   "ERROR",
+}
+
+// RunPlain runs what the UI is mostly used for without it, writing one line
+// per step to out. It authenticates, fetches and saves the top pending users
+// and processes the follow queue until nothing is ready, either pause is set,
+// the ratio guard holds follows, the active hours end or ctx is cancelled.
+// Follows go through the service like the queue processor's, so the
+// blocklist, caps, holds and retry limit all apply. It is meant for cron, CI and
+// terminals the UI can't draw on. Errors that end the run are returned
+// rather than written.
+func RunPlain(ctx context.Context, svc *service.Service, out io.Writer) error {
+	report := func(message string, kind StatusType) {
+		fmt.Fprintf(out, "%s %s %s\n", time.Now().Format("2006-01-02 15:04:05"), plainLabels[kind], message)
+	}
+
+	if svc.DryRun() {
+		report("Dry run: follows are only simulated", StatusInfo)
+	}
+	if pause, err := svc.AccountPause(""); err == nil && pause != nil {
+		report("Account is paused; resume it to follow", StatusInfo)
+	}
+
+	session, err := svc.Login()
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	report(fmt.Sprintf("Successfully authenticated as %s", session.Handle), StatusSuccess)
+
+	report(fmt.Sprintf("Fetching the profiles of up to %d pending users", fetchLimit), StatusInfo)
+	total := 0
+	refreshed, err := svc.RefreshProfiles(ctx, session, fetchLimit, func(done, all int) {
+		total = all
+		report(fmt.Sprintf("Fetched %d of %d profiles", done, all), StatusInfo)
+	})
+	result := fetchResult(FetchDoneMsg{Refreshed: refreshed, Error: err}, total)
+	report(result.Message, result.Type)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if svc.QueueLen() == 0 {
+		if err := svc.LoadQueue(); err != nil {
+			return fmt.Errorf("failed to load the follow queue: %w", err)
+		}
+	}
+	stats := svc.Queue().Stats(time.Now())
+	report(fmt.Sprintf("Processing the follow queue: %d queued, %d ready", stats.Depth, stats.Ready), StatusInfo)
+
+	done := runQueue(ctx, svc, session, report)
+	if done.Error != nil {
+		return fmt.Errorf("queue processing failed: %w", done.Error)
+	}
+	report(done.Message, StatusInfo)
+
+	now := time.Now()
+	if budget, err := svc.FollowBudget(now); err == nil {
+		report(budgetLine(budget, svc.NextActive(now), now), StatusInfo)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"bsky_follower/internal/models"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/paginator"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// previewChrome is how many lines of the preview sit outside the rows:
+// title, summary, counts, page and help
+const previewChrome = 9
+
+// PreviewRow is one account a batch operation is about to affect
+type PreviewRow struct {
+	Subject string // handle or DID
+	Detail  string // what happens to it, e.g. its priority or campaign
+}
+
+// previewModel is a paginated list of the accounts a batch operation
+// affects, each accepted or denied. It runs as a program of its own, ahead
+// of the operation.
+type previewModel struct {
+	title    string
+	summary  string
+	rows     []PreviewRow
+	accepted []bool
+	cursor   int
+	pager    paginator.Model
+	keys     keyMap
+	help     help.Model
+	ok       bool
+}
+
+// Preview lists the accounts a batch operation is about to affect, all
+// accepted at first, and lets the user deny some of them before going ahead.
+// It returns which rows were accepted, and ok false if the user cancelled.
+func Preview(config *models.Config, title, summary string, rows []PreviewRow) (accepted []bool, ok bool, err error) {
+	keys, err := newKeyMap(config.Keys)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pager := paginator.New()
+	pager.KeyMap = paginator.KeyMap{PrevPage: keys.PageUp, NextPage: keys.PageDown}
+	pager.ArabicFormat = "Page %d of %d"
+	m := previewModel{
+		title:    title,
+		summary:  summary,
+		rows:     rows,
+		accepted: make([]bool, len(rows)),
+		pager:    pager,
+		keys:     keys,
+		help:     newHelp(),
+	}
+	for i := range m.accepted {
+		m.accepted[i] = true
+	}
+	// Show 20 rows a page until the terminal's size arrives
+	m.resize(previewChrome + 20)
+
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, false, err
+	}
+	m = final.(previewModel)
+	return m.accepted, m.ok, nil
+}
+
+func (m previewModel) Init() tea.Cmd {
+	return nil
+}
+
+// resize fits a page of rows to the terminal, keeping the cursor's row in
+// view
+func (m *previewModel) resize(height int) {
+	m.pager.PerPage = max(height-previewChrome, 1)
+	m.pager.SetTotalPages(len(m.rows))
+	m.pager.Page = m.cursor / m.pager.PerPage
+}
+
+// move puts the cursor on a row, turning the page to it
+func (m *previewModel) move(row int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	m.cursor = min(max(row, 0), len(m.rows)-1)
+	m.pager.Page = m.cursor / m.pager.PerPage
+}
+
+func (m previewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Height)
+		m.help.Width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		k := m.keys
+		switch {
+		case msg.String() == "ctrl+c" || key.Matches(msg, k.No):
+			return m, tea.Quit
+		case key.Matches(msg, k.Select):
+			m.ok = true
+			return m, tea.Quit
+		case key.Matches(msg, k.Up):
+			m.move(m.cursor - 1)
+		case key.Matches(msg, k.Down):
+			m.move(m.cursor + 1)
+		case key.Matches(msg, k.Top):
+			m.move(0)
+		case key.Matches(msg, k.Bottom):
+			m.move(len(m.rows) - 1)
+		case key.Matches(msg, k.Toggle):
+			if len(m.rows) > 0 {
+				m.accepted[m.cursor] = !m.accepted[m.cursor]
+			}
+		case key.Matches(msg, k.Add), key.Matches(msg, k.Remove):
+			accept := key.Matches(msg, k.Add)
+			for i := range m.accepted {
+				m.accepted[i] = accept
+			}
+		default:
+			// Paging keeps the cursor at the same place on the page
+			offset := m.cursor - m.pager.Page*m.pager.PerPage
+			m.pager, _ = m.pager.Update(msg)
+			start, end := m.pager.GetSliceBounds(len(m.rows))
+			if end > start {
+				m.cursor = min(start+offset, end-1)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m previewModel) View() string {
+	var sb strings.Builder
+	k := m.keys
+
+	sb.WriteString(uiTitleStyle.Render("📋 "+m.title) + "\n")
+	sb.WriteString(uiSubtitleStyle.Render(m.summary) + "\n\n")
+
+	count := 0
+	for _, accepted := range m.accepted {
+		if accepted {
+			count++
+		}
+	}
+	sb.WriteString(uiMenuItemStyle.Render(fmt.Sprintf("%d of %d accepted, %d denied", count, len(m.rows), len(m.rows)-count)) + "\n")
+
+	start, end := m.pager.GetSliceBounds(len(m.rows))
+	width := 0
+	for _, row := range m.rows[start:end] {
+		width = max(width, len([]rune(row.Subject)))
+	}
+	for i := start; i < end; i++ {
+		row := m.rows[i]
+		mark := uiTimelineFollowBackStyle.Render("✓ accept")
+		if !m.accepted[i] {
+			mark = uiLogErrorStyle.Render("✗ deny  ")
+		}
+		pointer, text := "  ", fmt.Sprintf("%-*s  %s", width, row.Subject, row.Detail)
+		if i == m.cursor {
+			selected := uiSelectedMenuItemStyle.Copy().UnsetPaddingLeft()
+			pointer, text = selected.Render("> "), selected.Render(text)
+		}
+		sb.WriteString(uiMenuItemStyle.Render(pointer+mark+"  "+text) + "\n")
+	}
+
+	sb.WriteString("\n" + uiMenuItemStyle.Render(m.pager.View()) + "\n")
+	sb.WriteString("\n" + uiHelpStyle.Render(m.help.ShortHelpView([]key.Binding{
+		k.Up, k.Down, k.PageUp, k.PageDown, k.Toggle,
+		as(k.Add, "accept all"), as(k.Remove, "deny all"),
+		as(k.Select, "go ahead"), as(k.No, "cancel"),
+	})))
+	return sb.String()
+}
@@ -1,11 +1,15 @@
 package ui
 
 import (
-	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
-	"bsky_follower/internal/api"
 	"bsky_follower/internal/models"
+	"bsky_follower/internal/queue"
+	"bsky_follower/internal/service"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -16,43 +20,213 @@ type QueueMsg struct {
 	Error   error
 }
 
-// QueueCmd represents a command to process the follow queue
-func QueueCmd(client *api.Client, session *models.Session, queue *models.FollowQueue) tea.Cmd {
+// QueueEventMsg carries a change to the follow queue
+type QueueEventMsg struct {
+	Event queue.Event
+}
+
+// WaitForQueueEventCmd waits for the next queue event. The model issues it
+// again after each event, so the view follows the queue live.
+func WaitForQueueEventCmd(events <-chan queue.Event) tea.Cmd {
 	return func() tea.Msg {
-		if queue.Len() == 0 {
-			return QueueMsg{
-				Message: "Queue is empty",
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return QueueEventMsg{Event: event}
+	}
+}
+
+// QueueProgressMsg streams what a queue run is doing
+type QueueProgressMsg struct {
+	Status StatusMsg
+}
+
+// queueRunModel is the state of a queue run in the background
+type queueRunModel struct {
+	active   bool
+	cancel   context.CancelFunc
+	progress <-chan QueueProgressMsg
+}
+
+// start begins processing the queue, returning the commands that run it and
+// stream its progress
+func (r *queueRunModel) start(svc *service.Service, session *models.Session) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan QueueProgressMsg, 1)
+	r.active = true
+	r.cancel = cancel
+	r.progress = updates
+	return tea.Batch(ProcessQueueCmd(ctx, svc, session, updates), WaitForQueueProgressCmd(updates))
+}
+
+// stop ends the run's bookkeeping once it is done
+func (r *queueRunModel) stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.active = false
+	r.cancel = nil
+	r.progress = nil
+}
+
+// ProcessQueueCmd runs the queue in the background, streaming the latest
+// status of the run on updates, which is closed when the run ends
+func ProcessQueueCmd(ctx context.Context, svc *service.Service, session *models.Session, updates chan QueueProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		defer close(updates)
+		return runQueue(ctx, svc, session, func(message string, kind StatusType) {
+			// Only the latest status is shown, so a stale one is dropped
+			// rather than holding the run up
+			select {
+			case <-updates:
+			default:
 			}
+			updates <- QueueProgressMsg{Status: StatusMsg{Message: message, Type: kind, Time: time.Now()}}
+		})
+	}
+}
+
+// runQueue follows queued users one after another through the service, so
+// each follow passes the same guards as the queue processor's, waiting out the
+// pacing, rate limit and caps between follows, until the queue has nothing
+// ready, either pause is set, the ratio guard holds follows, the active hours
+// end or ctx is cancelled. Each follow and wait is reported, and the run's
+// summary returned.
+func runQueue(ctx context.Context, svc *service.Service, session *models.Session, report func(message string, kind StatusType)) QueueMsg {
+	followed, skipped, failed := 0, 0, 0
+	summary := func(reason string) QueueMsg {
+		return QueueMsg{Message: fmt.Sprintf("%s: %d followed, %d skipped, %d failed", reason, followed, skipped, failed)}
+	}
+	for {
+		if ctx.Err() != nil {
+			return summary("Queue run cancelled")
 		}
 
-		// Get the highest priority item
-		item := heap.Pop(queue).(*models.FollowQueueItem)
-		if item.NextTry.After(time.Now()) {
-			// Put the item back in the queue
-			heap.Push(queue, item)
-			return QueueMsg{
-				Message: "No items ready to process",
+		step := svc.FollowNext(session)
+		switch {
+		case step.User != nil && step.Err != nil:
+			failed++
+			report(fmt.Sprintf("Failed to follow %s: %v", step.User.Handle, step.Err), StatusError)
+			continue
+		case step.User != nil && step.Skipped != "":
+			skipped++
+			report(fmt.Sprintf("Skipped %s: %s", step.User.Handle, step.Skipped), StatusInfo)
+			continue
+		case step.User != nil:
+			followed++
+			if svc.DryRun() {
+				report(fmt.Sprintf("Dry run: would have followed %s", step.User.Handle), StatusSuccess)
+			} else {
+				report(fmt.Sprintf("Followed %s", step.User.Handle), StatusSuccess)
+			}
+			continue
+		case step.Err != nil:
+			return QueueMsg{Message: step.Reason, Error: step.Err}
+		case step.Idle:
+			if followed+skipped+failed == 0 {
+				return QueueMsg{Message: step.Reason}
 			}
+			return summary(step.Reason)
 		}
 
-		// Try to follow the user
-		err := client.FollowUser(session, item.User.DID, false)
-		if err != nil {
-			// Increment attempts and update next try time
-			item.Attempts++
-			item.NextTry = time.Now().Add(time.Duration(item.Attempts) * 5 * time.Minute)
-			heap.Push(queue, item)
-			return QueueMsg{
-				Message: "Failed to follow user",
-				Error:   err,
+		if step.Wait > 0 {
+			report(fmt.Sprintf("Next follow in %s (%s)", step.Wait.Round(time.Second), step.Reason), StatusInfo)
+			select {
+			case <-ctx.Done():
+				return summary("Queue run cancelled")
+			case <-time.After(step.Wait):
 			}
 		}
+	}
+}
+
+// WaitForQueueProgressCmd waits for the next status of a queue run. The model
+// issues it again after each status until the run closes the channel.
+func WaitForQueueProgressCmd(updates <-chan QueueProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return update
+	}
+}
 
-		// Update user status
-		item.User.Followed = true
-		item.User.FollowDate = time.Now()
-		return QueueMsg{
-			Message: "Successfully followed user",
+// queueStatsLines renders the queue dashboard shown under the menu
+func queueStatsLines(stats queue.Stats, last *queue.Event, now time.Time) []string {
+	lines := []string{fmt.Sprintf("Queue size: %d (%d ready) • %d/hour • %d dead letters",
+		stats.Depth, stats.Ready, stats.PerHour, stats.DeadLetters)}
+	if stats.Depth == 0 {
+		if last != nil {
+			lines = append(lines, fmt.Sprintf("Last change: %s %s at %s", last.Item.User.Handle, last.Kind, last.At.Format("15:04:05")))
 		}
+		return lines
+	}
+
+	priorities := make([]int, 0, len(stats.Bands))
+	for priority := range stats.Bands {
+		priorities = append(priorities, priority)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+	bands := make([]string, len(priorities))
+	for i, priority := range priorities {
+		bands[i] = fmt.Sprintf("p%d: %d", priority, stats.Bands[priority])
+	}
+	lines = append(lines, "Priority bands: "+strings.Join(bands, ", "))
+
+	if len(stats.Lanes) > 1 {
+		names := make([]string, 0, len(stats.Lanes))
+		for name := range stats.Lanes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		lanes := make([]string, len(names))
+		for i, name := range names {
+			lanes[i] = fmt.Sprintf("%s: %d", name, stats.Lanes[name])
+		}
+		lines = append(lines, "Lanes: "+strings.Join(lanes, ", "))
+	}
+
+	if last != nil {
+		lines = append(lines, fmt.Sprintf("Last change: %s %s at %s", last.Item.User.Handle, last.Kind, last.At.Format("15:04:05")))
+	}
+	if stats.OldestNextTry.After(now) {
+		lines = append(lines, fmt.Sprintf("Next item due in %s", stats.OldestNextTry.Sub(now).Round(time.Minute)))
 	}
-} 
\ No newline at end of file
+	return lines
+}
+
+// upcomingLines renders the next queued follows in the order they will be taken
+func upcomingLines(items []models.FollowQueueItem, now time.Time) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	lines := []string{"Up next:"}
+	for i, item := range items {
+		line := fmt.Sprintf("  %d. %s (priority %d)", i+1, item.User.Handle, item.Priority)
+		if item.NextTry.After(now) {
+			line += " from " + item.NextTry.Format("Mon 15:04")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// dryRunBanner notes dry-run mode and the size of its report at the top of
+// the menu
+func dryRunBanner(svc *service.Service) string {
+	if !svc.DryRun() {
+		return ""
+	}
+	return uiPausedStyle.Render(fmt.Sprintf("🧪 Dry run: %d simulated actions, nothing is sent", len(svc.Simulated().Actions))) + "\n"
+}
+
+// quietHoursBanner shows when follows resume while outside the active hours
+func quietHoursBanner(svc *service.Service, now time.Time) string {
+	next := svc.NextActive(now)
+	if !next.After(now) {
+		return ""
+	}
+	return uiPausedStyle.Render(fmt.Sprintf("🌙 Quiet hours: follows resume %s", next.Format("Mon 15:04"))) + "\n"
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// queueViewChrome is how many lines of the queue screen sit outside the
+	// scrolling list: title, subtitle, banners, header, status, help and footer
+	queueViewChrome = 13
+
+	// queueBumpStep is how much one bump raises an item's priority
+	queueBumpStep = 1
+
+	// queueViewRefreshInterval is how often the next-try countdowns update
+	queueViewRefreshInterval = time.Second
+)
+
+// QueueViewMsg carries the pending queue items, in the order they will be
+// taken, after a load or change
+type QueueViewMsg struct {
+	Items   []models.FollowQueueItem
+	Message string
+	Error   error
+}
+
+// queueViewTickMsg refreshes the countdowns on the queue screen
+type queueViewTickMsg struct{}
+
+// queueViewModel is the state of the queue screen
+type queueViewModel struct {
+	items    []models.FollowQueueItem
+	cursor   int
+	loaded   bool
+	viewport viewport.Model
+}
+
+func newQueueViewModel() queueViewModel {
+	return queueViewModel{viewport: viewport.New(0, 0)}
+}
+
+// LoadQueueViewCmd loads every pending queue item
+func LoadQueueViewCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		return QueueViewMsg{Items: svc.Queue().Snapshot()}
+	}
+}
+
+// DequeueCmd removes an item from the queue and reloads the queue screen
+func DequeueCmd(svc *service.Service, item models.FollowQueueItem) tea.Cmd {
+	return func() tea.Msg {
+		message := fmt.Sprintf("Removed %s from the queue", item.User.Handle)
+		if !svc.Dequeue(item.User.DID) {
+			message = fmt.Sprintf("%s is no longer queued", item.User.Handle)
+		}
+		return QueueViewMsg{Items: svc.Queue().Snapshot(), Message: message}
+	}
+}
+
+// BumpQueuedCmd raises an item's priority and reloads the queue screen
+func BumpQueuedCmd(svc *service.Service, item models.FollowQueueItem) tea.Cmd {
+	return func() tea.Msg {
+		message := fmt.Sprintf("Bumped %s to priority %d", item.User.Handle, item.Priority+queueBumpStep)
+		queued, err := svc.BumpQueued(item.User.DID, queueBumpStep)
+		if !queued {
+			message = fmt.Sprintf("%s is no longer queued", item.User.Handle)
+		}
+		return QueueViewMsg{Items: svc.Queue().Snapshot(), Message: message, Error: err}
+	}
+}
+
+// RetryQueuedNowCmd makes an item due right away and reloads the queue screen
+func RetryQueuedNowCmd(svc *service.Service, item models.FollowQueueItem) tea.Cmd {
+	return func() tea.Msg {
+		message := fmt.Sprintf("%s will be retried next", item.User.Handle)
+		if !svc.RetryQueuedNow(item.User.DID) {
+			message = fmt.Sprintf("%s is no longer queued", item.User.Handle)
+		}
+		return QueueViewMsg{Items: svc.Queue().Snapshot(), Message: message}
+	}
+}
+
+// queueViewTickCmd schedules the next countdown refresh
+func queueViewTickCmd() tea.Cmd {
+	return tea.Tick(queueViewRefreshInterval, func(time.Time) tea.Msg {
+		return queueViewTickMsg{}
+	})
+}
+
+// resize fits the scrolling list to the terminal
+func (q *queueViewModel) resize(width, height int) {
+	q.viewport.Width = width
+	q.viewport.Height = max(height-queueViewChrome, 1)
+	q.sync(time.Now())
+}
+
+// setItems replaces the listed items, keeping the cursor in range
+func (q *queueViewModel) setItems(items []models.FollowQueueItem) {
+	q.items = items
+	q.loaded = true
+	if q.cursor >= len(items) {
+		q.cursor = max(len(items)-1, 0)
+	}
+	q.sync(time.Now())
+}
+
+// sync renders the items into the viewport and scrolls it so the cursor
+// stays in sight
+func (q *queueViewModel) sync(now time.Time) {
+	q.viewport.SetContent(strings.Join(queueViewLines(q.items, q.cursor, now), "\n"))
+	switch {
+	case q.cursor < q.viewport.YOffset:
+		q.viewport.SetYOffset(q.cursor)
+	case q.cursor >= q.viewport.YOffset+q.viewport.Height:
+		q.viewport.SetYOffset(q.cursor - q.viewport.Height + 1)
+	}
+}
+
+// selected returns the item under the cursor
+func (q *queueViewModel) selected() (models.FollowQueueItem, bool) {
+	if q.cursor >= len(q.items) {
+		return models.FollowQueueItem{}, false
+	}
+	return q.items[q.cursor], true
+}
+
+// updateQueueView handles key presses on the queue screen
+func (m Model) updateQueueView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := &m.queueView
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		if q.cursor > 0 {
+			q.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if q.cursor < len(q.items)-1 {
+			q.cursor++
+		}
+	case key.Matches(msg, m.keys.PageUp):
+		q.cursor = max(q.cursor-q.viewport.Height, 0)
+	case key.Matches(msg, m.keys.PageDown):
+		q.cursor = max(min(q.cursor+q.viewport.Height, len(q.items)-1), 0)
+	case key.Matches(msg, m.keys.Top):
+		q.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		q.cursor = max(len(q.items)-1, 0)
+	case key.Matches(msg, m.keys.Remove):
+		if item, ok := q.selected(); ok {
+			return m, DequeueCmd(m.service, item)
+		}
+	case key.Matches(msg, m.keys.Bump):
+		if item, ok := q.selected(); ok {
+			return m, BumpQueuedCmd(m.service, item)
+		}
+	case key.Matches(msg, m.keys.Retry):
+		if item, ok := q.selected(); ok {
+			return m, RetryQueuedNowCmd(m.service, item)
+		}
+	}
+	q.sync(time.Now())
+	return m, nil
+}
+
+// viewQueueView renders the queue screen
+func (m Model) viewQueueView() string {
+	var sb strings.Builder
+	q := m.queueView
+
+	sb.WriteString(uiTitleStyle.Render("📋 Follow Queue") + "\n")
+	sb.WriteString(uiSubtitleStyle.Render(fmt.Sprintf("%d pending follows, in the order they will be taken", len(q.items))) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	switch {
+	case !q.loaded:
+		sb.WriteString(uiMenuItemStyle.Render("Loading...") + "\n")
+	case len(q.items) == 0:
+		sb.WriteString(uiMenuItemStyle.Render("The queue is empty") + "\n")
+	default:
+		header := fmt.Sprintf("%-32s %8s %8s %10s", "handle", "priority", "attempts", "next try")
+		sb.WriteString(uiSelectedMenuItemStyle.Render(header) + "\n")
+		q.sync(time.Now())
+		sb.WriteString(q.viewport.View() + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	sb.WriteString("\n" + m.helpLine(m.keys.Up, m.keys.Down, as(m.keys.Remove, "dequeue"), m.keys.Bump, m.keys.Retry, m.keys.Back, m.keys.Help, m.keys.Quit))
+	return sb.String()
+}
+
+// queueViewLines renders one line per queue item, highlighting the cursor
+func queueViewLines(items []models.FollowQueueItem, cursor int, now time.Time) []string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		style := uiMenuItemStyle
+		if i == cursor {
+			style = uiSelectedMenuItemStyle
+		}
+		handle := item.User.Handle
+		if handle == "" {
+			handle = item.User.DID
+		}
+		line := fmt.Sprintf("%-32s %8d %8d %10s", truncateLabel(handle, 32), item.Priority, item.Attempts, countdown(item.NextTry, now))
+		lines[i] = style.Render(line)
+	}
+	return lines
+}
+
+// countdown renders how long until a time, or "ready" once it has passed
+func countdown(at, now time.Time) string {
+	if !at.After(now) {
+		return "ready"
+	}
+	left := at.Sub(now).Round(time.Second)
+	if left >= time.Hour {
+		return left.Round(time.Minute).String()
+	}
+	return left.String()
+}
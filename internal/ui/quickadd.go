@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickAddBioLength bounds how much of a bio the profile card shows
+const quickAddBioLength = 160
+
+// LookUpMsg carries an account looked up to be added by hand
+type LookUpMsg struct {
+	Lookup *service.AccountLookup
+	Error  error
+}
+
+// quickAddModel is the state of the add account input on the menu. It
+// takes a handle, then shows the profile until it is queued, followed or
+// dismissed.
+type quickAddModel struct {
+	input   textinput.Model
+	active  bool
+	looking bool
+	lookup  *service.AccountLookup
+}
+
+func newQuickAddModel() quickAddModel {
+	input := textinput.New()
+	input.Prompt = "Add: "
+	input.Placeholder = "handle or DID"
+	input.CharLimit = 256
+	return quickAddModel{input: input}
+}
+
+// close hides the input and forgets the account shown
+func (q *quickAddModel) close() {
+	q.active, q.looking, q.lookup = false, false, nil
+	q.input.Blur()
+	q.input.Reset()
+}
+
+// LookUpCmd resolves a handle or DID and fetches its profile
+func LookUpCmd(svc *service.Service, session *models.Session, subject string) tea.Cmd {
+	return func() tea.Msg {
+		lookup, err := svc.LookUpAccount(session, subject)
+		return LookUpMsg{Lookup: lookup, Error: err}
+	}
+}
+
+// AddAccountCmd queues a looked-up account, or follows it right away
+func AddAccountCmd(svc *service.Service, session *models.Session, lookup service.AccountLookup, now bool) tea.Cmd {
+	return func() tea.Msg {
+		verb := "queue"
+		if now {
+			verb = "follow"
+		}
+		if err := svc.AddAccount(session, lookup, now); err != nil {
+			return StatusMsg{
+				Message: fmt.Sprintf("Failed to %s %s: %v", verb, lookup.User.Handle, err),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+		}
+		message := fmt.Sprintf("Queued %s", lookup.User.Handle)
+		switch {
+		case now && svc.DryRun():
+			message = fmt.Sprintf("Simulated following %s", lookup.User.Handle)
+		case now:
+			message = fmt.Sprintf("Followed %s", lookup.User.Handle)
+		}
+		return StatusMsg{
+			Message: message,
+			Type:    StatusSuccess,
+			Time:    time.Now(),
+		}
+	}
+}
+
+// updateQuickAdd handles key presses while the add account input or profile
+// card is open
+func (m Model) updateQuickAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := &m.quickAdd
+
+	if q.lookup != nil {
+		lookup := *q.lookup
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			q.close()
+			return m, AddAccountCmd(m.service, m.session, lookup, false)
+		case key.Matches(msg, m.keys.No):
+			q.close()
+		case key.Matches(msg, m.keys.FollowNow):
+			q.close()
+			return m, AddAccountCmd(m.service, m.session, lookup, true)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		q.close()
+		return m, nil
+	case "enter":
+		subject := strings.TrimSpace(q.input.Value())
+		if subject == "" || q.looking {
+			return m, nil
+		}
+		q.looking = true
+		q.input.Blur()
+		return m, LookUpCmd(m.service, m.session, subject)
+	}
+	if q.looking {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	q.input, cmd = q.input.Update(msg)
+	return m, cmd
+}
+
+// view renders the input, or the profile card of the account looked up with
+// the hint line given
+func (q quickAddModel) view(help string) string {
+	if q.lookup == nil {
+		line := q.input.View()
+		if q.looking {
+			line += "  looking up…"
+		}
+		return uiMenuItemStyle.Render(line) + "\n"
+	}
+
+	user := q.lookup.User
+	var sb strings.Builder
+	title := "@" + user.Handle
+	if user.DisplayName != "" {
+		title = user.DisplayName + " " + title
+	}
+	sb.WriteString(uiTitleStyle.Render(title) + "\n")
+	sb.WriteString(fmt.Sprintf("%d followers • %d following • %d posts\n", user.Followers, user.Following, user.PostsCount))
+	if bio := strings.Join(strings.Fields(user.Description), " "); bio != "" {
+		if len([]rune(bio)) > quickAddBioLength {
+			bio = string([]rune(bio)[:quickAddBioLength]) + "…"
+		}
+		sb.WriteString(uiSubtitleStyle.Render(bio) + "\n")
+	}
+
+	var notes []string
+	if q.lookup.Following {
+		notes = append(notes, "already followed")
+	}
+	if q.lookup.FollowsMe {
+		notes = append(notes, "follows you")
+	}
+	if q.lookup.Blocked != "" {
+		notes = append(notes, q.lookup.Blocked)
+	}
+	switch {
+	case q.lookup.Stored && user.Source != "":
+		notes = append(notes, "stored, found by "+user.Source)
+	case q.lookup.Stored:
+		notes = append(notes, "stored")
+	}
+	if len(notes) > 0 {
+		sb.WriteString(uiStatusStyle.Copy().UnsetPaddingLeft().Render(strings.Join(notes, " • ")) + "\n")
+	}
+
+	sb.WriteString("\n" + help)
+	return boxStyle.Render(sb.String()) + "\n"
+}
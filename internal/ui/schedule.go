@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/schedule"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scheduleModel is the state of the schedule follow input on the menu
+type scheduleModel struct {
+	input  textinput.Model
+	active bool
+}
+
+func newScheduleModel() scheduleModel {
+	input := textinput.New()
+	input.Prompt = "Schedule: "
+	input.Placeholder = "handle or DID, then +12h, 09:00 or 2006-01-02 09:00"
+	input.CharLimit = 256
+	return scheduleModel{input: input}
+}
+
+// ScheduleFollowCmd queues an account to be followed no earlier than notBefore
+func ScheduleFollowCmd(svc *service.Service, session *models.Session, subject string, notBefore time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.ScheduleFollow(session, subject, notBefore); err != nil {
+			return StatusMsg{
+				Message: fmt.Sprintf("Failed to schedule follow: %v", err),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+		}
+		return StatusMsg{
+			Message: fmt.Sprintf("Scheduled %s for %s", subject, notBefore.Format("Mon 2006-01-02 15:04")),
+			Type:    StatusSuccess,
+			Time:    time.Now(),
+		}
+	}
+}
+
+// updateSchedule handles key presses while the schedule input is open
+func (m Model) updateSchedule(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.schedule
+
+	switch msg.String() {
+	case "esc":
+		s.active = false
+		s.input.Blur()
+		s.input.Reset()
+		return m, nil
+	case "enter":
+		subject, at, _ := strings.Cut(strings.TrimSpace(s.input.Value()), " ")
+		s.active = false
+		s.input.Blur()
+		s.input.Reset()
+		if subject == "" {
+			return m, nil
+		}
+		notBefore, err := schedule.ParseTime(at, time.Now())
+		if err != nil {
+			m.status = &StatusMsg{
+				Message: err.Error(),
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		return m, ScheduleFollowCmd(m.service, m.session, subject, notBefore)
+	}
+
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	return m, cmd
+}
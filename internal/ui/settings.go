@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bsky_follower/internal/config"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SettingsMsg carries the current settings after a load or change
+type SettingsMsg struct {
+	Settings []service.Setting
+	Message  string
+	Error    error
+}
+
+// settingsModel is the state of the settings screen
+type settingsModel struct {
+	settings []service.Setting
+	cursor   int
+	input    textinput.Model
+	editing  bool
+}
+
+func newSettingsModel() settingsModel {
+	input := textinput.New()
+	input.CharLimit = 64
+	return settingsModel{input: input}
+}
+
+// LoadSettingsCmd loads the current settings
+func LoadSettingsCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		return SettingsMsg{Settings: svc.Settings()}
+	}
+}
+
+// SaveSettingCmd puts a setting into effect and saves it to the env file,
+// unless the run is ephemeral, then reloads the settings
+func SaveSettingCmd(svc *service.Service, ephemeral bool, key, value string) tea.Cmd {
+	value = strings.TrimSpace(value)
+	return func() tea.Msg {
+		if err := svc.ApplySetting(key, value); err != nil {
+			return SettingsMsg{Settings: svc.Settings(), Error: err}
+		}
+		message := fmt.Sprintf("Set %s to %q", key, value)
+		if ephemeral {
+			message += " for this run"
+		} else if err := config.SaveSetting(config.EnvFile, key, value); err != nil {
+			return SettingsMsg{Settings: svc.Settings(), Message: message, Error: err}
+		} else {
+			message += " and saved it to " + config.EnvFile
+		}
+		return SettingsMsg{Settings: svc.Settings(), Message: message}
+	}
+}
+
+// updateSettings handles key presses on the settings screen
+func (m Model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	st := &m.settings
+
+	if st.editing {
+		switch msg.String() {
+		case "esc":
+			st.editing = false
+			st.input.Blur()
+			return m, nil
+		case "enter":
+			st.editing = false
+			st.input.Blur()
+			setting := st.settings[st.cursor]
+			return m, SaveSettingCmd(m.service, m.config.Ephemeral, setting.Key, st.input.Value())
+		}
+		var cmd tea.Cmd
+		st.input, cmd = st.input.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+	case key.Matches(msg, m.keys.Up):
+		if st.cursor > 0 {
+			st.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if st.cursor < len(st.settings)-1 {
+			st.cursor++
+		}
+	case key.Matches(msg, m.keys.Select, m.keys.Toggle):
+		if st.cursor >= len(st.settings) {
+			return m, nil
+		}
+		setting := st.settings[st.cursor]
+		if setting.Bool {
+			on, _ := strconv.ParseBool(setting.Value)
+			return m, SaveSettingCmd(m.service, m.config.Ephemeral, setting.Key, strconv.FormatBool(!on))
+		}
+		st.editing = true
+		st.input.Prompt = setting.Label + ": "
+		st.input.SetValue(setting.Value)
+		st.input.CursorEnd()
+		return m, st.input.Focus()
+	}
+	return m, nil
+}
+
+// viewSettings renders the settings screen
+func (m Model) viewSettings() string {
+	var sb strings.Builder
+	st := m.settings
+
+	sb.WriteString(uiTitleStyle.Render("⚙ Settings") + "\n")
+	subtitle := "Changes apply right away and are saved to " + config.EnvFile
+	if m.config.Ephemeral {
+		subtitle = "Changes apply right away, for this run only"
+	}
+	sb.WriteString(uiSubtitleStyle.Render(subtitle) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	for i, setting := range st.settings {
+		style := uiMenuItemStyle
+		if i == st.cursor {
+			style = uiSelectedMenuItemStyle
+		}
+		value := setting.Value
+		if value == "" {
+			value = "(not set)"
+		}
+		sb.WriteString(style.Render(fmt.Sprintf("%-28s %-16s %s", setting.Label, value, setting.Key)) + "\n")
+	}
+
+	if st.editing {
+		sb.WriteString("\n" + uiMenuItemStyle.Render(st.input.View()) + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	help := m.helpLine(m.keys.Up, m.keys.Down, as(m.keys.Select, "edit or toggle"), m.keys.Back, m.keys.Help, m.keys.Quit)
+	if st.editing {
+		help = uiHelpStyle.Render("Enter: Save • esc: Cancel")
+	}
+	sb.WriteString("\n" + help)
+	return sb.String()
+}
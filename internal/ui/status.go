@@ -23,6 +23,23 @@ const (
 	StatusError
 )
 
+// toastDuration is how long a success or info message shows before it is
+// dismissed. Errors stay until a key is pressed.
+const toastDuration = 4 * time.Second
+
+// statusExpireMsg dismisses a status message if it still shows
+type statusExpireMsg struct {
+	status *StatusMsg
+}
+
+// expireStatusCmd dismisses a status message once it has shown for
+// toastDuration
+func expireStatusCmd(status *StatusMsg) tea.Cmd {
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return statusExpireMsg{status: status}
+	})
+}
+
 // StatusCmd represents a command to show a status message
 func StatusCmd(message string, statusType StatusType) tea.Cmd {
 	return func() tea.Msg {
@@ -34,9 +34,26 @@ var (
 		PaddingLeft(2).
 		Foreground(lipgloss.Color("#00FF00"))
 
+	uiPausedStyle = lipgloss.NewStyle().
+		PaddingLeft(2).
+		Bold(true).
+		Foreground(lipgloss.Color("#FFA500"))
+
 	uiHelpStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#A9A9A9"))
 
+	uiLogErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F5F"))
+
+	uiLogDebugStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#808080"))
+
+	uiTimelineFollowBackStyle = lipgloss.NewStyle().
+		Foreground(special)
+
+	uiTimelineRateLimitStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFA500"))
+
 	infoStyle = lipgloss.NewStyle().
 		Foreground(subtle).
 		PaddingLeft(2).
@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// timelineChrome is how many lines of the timeline screen sit outside
+	// the feed: title, subtitle, banners, status, help and footer
+	timelineChrome = 11
+
+	// timelineRefreshInterval is how often the timeline reloads, picking up
+	// what a daemon sharing the database did meanwhile
+	timelineRefreshInterval = 5 * time.Second
+
+	// timelineWindow is how far back the timeline goes
+	timelineWindow = 7 * 24 * time.Hour
+
+	// timelineLimit bounds how many entries the timeline shows
+	timelineLimit = 300
+)
+
+// TimelineMsg carries the latest timeline entries, newest first
+type TimelineMsg struct {
+	Entries []models.FollowEvent
+	Error   error
+}
+
+// timelineTickMsg reloads the timeline
+type timelineTickMsg struct{}
+
+// timelineModel is the state of the timeline screen
+type timelineModel struct {
+	entries  []models.FollowEvent
+	loaded   bool
+	viewport viewport.Model
+}
+
+func newTimelineModel(keys keyMap) timelineModel {
+	vp := viewport.New(0, 0)
+	vp.KeyMap.Up, vp.KeyMap.Down = keys.Up, keys.Down
+	return timelineModel{viewport: vp}
+}
+
+// LoadTimelineCmd loads what happened in the timeline window
+func LoadTimelineCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := svc.Timeline(time.Now().Add(-timelineWindow), timelineLimit)
+		return TimelineMsg{Entries: entries, Error: err}
+	}
+}
+
+// timelineTickCmd schedules the next reload of the timeline
+func timelineTickCmd() tea.Cmd {
+	return tea.Tick(timelineRefreshInterval, func(time.Time) tea.Msg {
+		return timelineTickMsg{}
+	})
+}
+
+// resize fits the feed to the terminal
+func (t *timelineModel) resize(width, height int) {
+	t.viewport.Width = width
+	t.viewport.Height = max(height-timelineChrome, 1)
+}
+
+// setEntries fills the feed. A reload keeps the scroll position unless the
+// feed showed the newest entries, which it then keeps showing.
+func (t *timelineModel) setEntries(entries []models.FollowEvent) {
+	top := t.viewport.AtTop()
+	t.entries = entries
+	t.loaded = true
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = timelineLine(entry)
+	}
+	t.viewport.SetContent(strings.Join(lines, "\n"))
+	if top {
+		t.viewport.GotoTop()
+	}
+}
+
+// timelineLine renders one timeline entry as a sentence
+func timelineLine(entry models.FollowEvent) string {
+	who := entry.Handle
+	if who == "" {
+		who = entry.DID
+	}
+	failed := entry.Result == models.ResultFailed
+
+	var text string
+	switch entry.Action {
+	case service.ActionFollowBack:
+		text = fmt.Sprintf("%s followed back", who)
+	case models.ActionFollow:
+		text = fmt.Sprintf("Followed %s", who)
+		if failed {
+			text = fmt.Sprintf("Failed to follow %s: %s", who, entry.Error)
+		}
+	case models.ActionUnfollow:
+		text = fmt.Sprintf("Unfollowed %s", who)
+		if failed {
+			text = fmt.Sprintf("Failed to unfollow %s: %s", who, entry.Error)
+		}
+	case models.ActionSkip:
+		text = fmt.Sprintf("Skipped %s: %s", who, entry.Error)
+	case models.ActionBlock:
+		text = fmt.Sprintf("Blocklisted %s", who)
+	case models.ActionEvict:
+		text = fmt.Sprintf("Evicted %s from the full queue", who)
+	case models.ActionExpire:
+		text = fmt.Sprintf("Dropped %s from the queue as stale", who)
+	case models.ActionRateLimit:
+		text = fmt.Sprintf("Paused on the %s limit, %s", entry.Strategy, entry.Error)
+		failed = false
+	default:
+		text = fmt.Sprintf("%s %s", entry.Action, who)
+	}
+	if entry.Result == models.ResultSimulated {
+		text += " (dry run)"
+	}
+	if entry.Strategy != "" && entry.Action != models.ActionRateLimit {
+		text += " • " + entry.Strategy
+	}
+
+	line := fmt.Sprintf("%s  %s", entry.CreatedAt.Local().Format("Mon 01-02 15:04"), text)
+	switch {
+	case failed:
+		return uiLogErrorStyle.Render(line)
+	case entry.Action == models.ActionRateLimit:
+		return uiTimelineRateLimitStyle.Render(line)
+	case entry.Action == service.ActionFollowBack:
+		return uiTimelineFollowBackStyle.Render(line)
+	}
+	return line
+}
+
+// updateTimeline handles key presses on the timeline screen
+func (m Model) updateTimeline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := &m.timeline
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+		return m, nil
+	case key.Matches(msg, m.keys.Refresh):
+		return m, LoadTimelineCmd(m.service)
+	case key.Matches(msg, m.keys.Top):
+		t.viewport.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		t.viewport.GotoBottom()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	t.viewport, cmd = t.viewport.Update(msg)
+	return m, cmd
+}
+
+// viewTimeline renders the timeline screen
+func (m Model) viewTimeline() string {
+	var sb strings.Builder
+	t := m.timeline
+
+	sb.WriteString(uiTitleStyle.Render("🕑 Activity Timeline") + "\n")
+	subtitle := fmt.Sprintf("What happened in the last %d days, newest first", int(timelineWindow/(24*time.Hour)))
+	if !t.viewport.AtTop() {
+		subtitle += fmt.Sprintf(" • %.0f%%, scrolled down", t.viewport.ScrollPercent()*100)
+	}
+	sb.WriteString(uiSubtitleStyle.Render(subtitle) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	switch {
+	case !t.loaded:
+		sb.WriteString(uiMenuItemStyle.Render("Loading...") + "\n")
+	case len(t.entries) == 0:
+		sb.WriteString(uiMenuItemStyle.Render("Nothing happened yet") + "\n")
+	default:
+		sb.WriteString(t.viewport.View() + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	sb.WriteString("\n" + m.helpLine(m.keys.Up, m.keys.Down, as(m.keys.Top, "newest"), m.keys.Refresh, m.keys.Back, m.keys.Help, m.keys.Quit))
+	return sb.String()
+}
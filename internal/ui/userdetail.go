@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// userActionReason is recorded for users blocklisted or protected from the
+// user detail view
+const userActionReason = "added from the users screen"
+
+// UserActionMsg ends an action taken on a user from the detail view
+type UserActionMsg struct {
+	Message string
+	Error   error
+}
+
+// UnfollowMutualMsg reports an unfollow refused because the user turned out
+// to follow back, so it can be confirmed as a mutual
+type UnfollowMutualMsg struct {
+	User models.TargetUser
+}
+
+// UnfollowUserCmd unfollows a user by hand. Mutuals are only unfollowed when
+// mutualOK says the user confirmed it knowing they follow back.
+func UnfollowUserCmd(svc *service.Service, session *models.Session, user models.TargetUser, mutualOK bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.Unfollow(session, user, mutualOK); err != nil {
+			if !mutualOK && errors.Is(err, db.ErrMutual) {
+				return UnfollowMutualMsg{User: user}
+			}
+			return UserActionMsg{Error: fmt.Errorf("failed to unfollow %s: %w", user.Handle, err)}
+		}
+		if svc.DryRun() {
+			return UserActionMsg{Message: fmt.Sprintf("Simulated unfollowing %s", user.Handle)}
+		}
+		return UserActionMsg{Message: fmt.Sprintf("Unfollowed %s", user.Handle)}
+	}
+}
+
+// BlockUserCmd adds a user to the blocklist
+func BlockUserCmd(svc *service.Service, user models.TargetUser) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.Block(userSubject(user), userActionReason); err != nil {
+			return UserActionMsg{Error: fmt.Errorf("failed to blocklist %s: %w", user.Handle, err)}
+		}
+		return UserActionMsg{Message: fmt.Sprintf("Added %s to the blocklist", user.Handle)}
+	}
+}
+
+// ProtectUserCmd adds a user to the protected accounts
+func ProtectUserCmd(svc *service.Service, user models.TargetUser) tea.Cmd {
+	return func() tea.Msg {
+		if err := svc.Protect(userSubject(user), userActionReason); err != nil {
+			return UserActionMsg{Error: fmt.Errorf("failed to protect %s: %w", user.Handle, err)}
+		}
+		return UserActionMsg{Message: fmt.Sprintf("Protected %s from unfollows", user.Handle)}
+	}
+}
+
+// userSubject names a user for the blocklist and protected accounts, by
+// handle where known
+func userSubject(user models.TargetUser) string {
+	if user.Handle != "" {
+		return user.Handle
+	}
+	return user.DID
+}
+
+// updateUserDetail handles key presses on a user's detail view
+func (m Model) updateUserDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	user := *m.users.detail
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.users.detail = nil
+	case key.Matches(msg, m.keys.Unfollow):
+		if !m.authenticated {
+			m.status = &StatusMsg{
+				Message: "Please authenticate first",
+				Type:    StatusError,
+				Time:    time.Now(),
+			}
+			return m, nil
+		}
+		m.confirm = unfollowConfirm(user, user.Mutual || user.FollowedBack, m.service.DryRun())
+	case key.Matches(msg, m.keys.Blocklist):
+		m.users.detail = nil
+		return m, BlockUserCmd(m.service, user)
+	case key.Matches(msg, m.keys.Protect):
+		m.users.detail = nil
+		return m, ProtectUserCmd(m.service, user)
+	}
+	return m, nil
+}
+
+// unfollowConfirm asks before unfollowing a user by hand. A mutual is named
+// as one, and accepting unfollows it anyway.
+func unfollowConfirm(user models.TargetUser, mutual, dryRun bool) *confirmModel {
+	verb := "Unfollow"
+	if dryRun {
+		verb = "Simulate unfollowing"
+	}
+	summary := []string{fmt.Sprintf("%s %s?", verb, user.Handle)}
+	if mutual {
+		summary = append(summary, "They follow you back; accepting unfollows them anyway.")
+	}
+	summary = append(summary, "The unfollow keeps to the unfollow pacing, rate and cap.")
+	return &confirmModel{
+		title:   "Unfollow",
+		summary: summary,
+		accept: func(m Model) (Model, tea.Cmd) {
+			m.users.detail = nil
+			return m, UnfollowUserCmd(m.service, m.session, user, mutual)
+		},
+	}
+}
+
+// viewUserDetail renders what is stored about a user
+func (m Model) viewUserDetail() string {
+	var sb strings.Builder
+	user := *m.users.detail
+
+	title := "@" + user.Handle
+	if user.DisplayName != "" {
+		title = user.DisplayName + " " + title
+	}
+	sb.WriteString(uiTitleStyle.Render("👤 "+title) + "\n")
+	sb.WriteString(uiSubtitleStyle.Render(user.DID) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	rows := [][2]string{
+		{"Status", userStatus(user)},
+		{"Profile", fmt.Sprintf("%d followers • %d following • %d posts", user.Followers, user.Following, user.PostsCount)},
+		{"Source", user.Source},
+		{"Priority", fmt.Sprintf("%d", user.Priority)},
+		{"Saved", date(user.SavedOn)},
+		{"Last checked", date(user.LastChecked)},
+		{"Followed", date(user.FollowDate)},
+		{"Followed back", date(user.FollowedBackAt)},
+		{"Unfollowed", date(user.UnfollowedAt)},
+	}
+	if user.Rejection != "" {
+		rows = append(rows, [2]string{"Rejected", user.Rejection})
+	}
+	for _, row := range rows {
+		sb.WriteString(uiMenuItemStyle.Render(fmt.Sprintf("%-14s %s", row[0], row[1])) + "\n")
+	}
+	if bio := strings.Join(strings.Fields(user.Description), " "); bio != "" {
+		sb.WriteString("\n" + uiMenuItemStyle.Render(truncateLabel(bio, 200)) + "\n")
+	}
+
+	if m.confirm != nil {
+		sb.WriteString("\n" + m.viewConfirm() + "\n")
+	}
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	sb.WriteString("\n" + m.helpLine(m.keys.Unfollow, m.keys.Blocklist, m.keys.Protect, m.keys.Back, m.keys.Help, m.keys.Quit))
+	return sb.String()
+}
@@ -0,0 +1,270 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bsky_follower/internal/db"
+	"bsky_follower/internal/models"
+	"bsky_follower/internal/service"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// usersLimit bounds how many users the table loads at once
+	usersLimit = 500
+
+	// usersChrome is how many lines of the users screen sit outside the
+	// table: title, subtitle, banners, search, status, help and footer
+	usersChrome = 13
+)
+
+// Follow states the users screen can be narrowed to
+const (
+	usersAll          = "all"
+	usersFollowed     = "followed"
+	usersNotFollowed  = "not followed"
+	usersFollowedBack = "followed back"
+)
+
+// usersStates lists the follow states in the order tab cycles through them
+var usersStates = []string{usersAll, usersFollowed, usersNotFollowed, usersFollowedBack}
+
+// usersQuery is what the users table shows: an order, a follow state and a
+// handle search
+type usersQuery struct {
+	sort   string
+	state  string
+	search string
+}
+
+// filter returns the store filter for a query
+func (q usersQuery) filter() db.UserFilter {
+	yes, no := true, false
+	filter := db.UserFilter{Sort: q.sort, HandleContains: q.search}
+	switch q.state {
+	case usersFollowed:
+		filter.Followed = &yes
+	case usersNotFollowed:
+		filter.Followed = &no
+	case usersFollowedBack:
+		filter.FollowedBack = &yes
+	}
+	return filter
+}
+
+// UsersMsg carries the stored users matching a query
+type UsersMsg struct {
+	Query usersQuery
+	Users []models.TargetUser
+	Error error
+}
+
+// usersModel is the state of the users screen
+type usersModel struct {
+	query     usersQuery
+	users     []models.TargetUser
+	loaded    bool
+	table     table.Model
+	search    textinput.Model
+	searching bool
+	detail    *models.TargetUser // user whose detail view is open, nil for the table
+}
+
+func newUsersModel(keys keyMap) usersModel {
+	search := textinput.New()
+	search.Prompt = "Search: "
+	search.Placeholder = "part of a handle"
+	search.CharLimit = 256
+
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Handle", Width: 32},
+			{Title: "Followers", Width: 9},
+			{Title: "Priority", Width: 8},
+			{Title: "Saved", Width: 10},
+			{Title: "Status", Width: 13},
+		}),
+		table.WithFocused(true),
+	)
+	styles := table.DefaultStyles()
+	styles.Selected = styles.Selected.Foreground(uiSelectedMenuItemStyle.GetForeground())
+	t.SetStyles(styles)
+	t.KeyMap.LineUp, t.KeyMap.LineDown = keys.Up, keys.Down
+
+	return usersModel{
+		query:  usersQuery{sort: db.SortPriority, state: usersAll},
+		table:  t,
+		search: search,
+	}
+}
+
+// LoadUsersCmd loads the stored users matching a query
+func LoadUsersCmd(svc *service.Service, query usersQuery) tea.Cmd {
+	return func() tea.Msg {
+		users, err := svc.QueryUsers(query.filter(), usersLimit)
+		return UsersMsg{Query: query, Users: users, Error: err}
+	}
+}
+
+// resize fits the table to the terminal
+func (u *usersModel) resize(height int) {
+	u.table.SetHeight(max(height-usersChrome, 1))
+}
+
+// setUsers fills the table with loaded users
+func (u *usersModel) setUsers(users []models.TargetUser) {
+	u.users = users
+	u.loaded = true
+	rows := make([]table.Row, len(users))
+	for i, user := range users {
+		handle := user.Handle
+		if handle == "" {
+			handle = user.DID
+		}
+		rows[i] = table.Row{
+			truncateLabel(handle, 32),
+			strconv.Itoa(user.Followers),
+			strconv.Itoa(user.Priority),
+			user.SavedOn.Format("2006-01-02"),
+			userStatus(user),
+		}
+	}
+	u.table.SetRows(rows)
+	if u.table.Cursor() >= len(rows) {
+		u.table.SetCursor(max(len(rows)-1, 0))
+	}
+}
+
+// userStatus sums up where I stand with a user
+func userStatus(user models.TargetUser) string {
+	switch {
+	case user.Mutual:
+		return "mutual"
+	case user.FollowedBack:
+		return "followed back"
+	case user.Followed:
+		return "followed"
+	case !user.UnfollowedAt.IsZero():
+		return "unfollowed"
+	case user.Rejection != "":
+		return "rejected"
+	default:
+		return "pending"
+	}
+}
+
+// updateUsers handles key presses on the users screen. While searching,
+// every keystroke narrows the table to the handles containing the search.
+func (m Model) updateUsers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	u := &m.users
+
+	if u.detail != nil {
+		return m.updateUserDetail(msg)
+	}
+
+	if u.searching {
+		switch msg.String() {
+		case "esc":
+			u.searching = false
+			u.search.Blur()
+			u.search.Reset()
+			u.query.search = ""
+			return m, LoadUsersCmd(m.service, u.query)
+		case "enter":
+			u.searching = false
+			u.search.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		u.search, cmd = u.search.Update(msg)
+		if search := strings.TrimSpace(u.search.Value()); search != u.query.search {
+			u.query.search = search
+			return m, tea.Batch(cmd, LoadUsersCmd(m.service, u.query))
+		}
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMenu
+		return m, nil
+	case key.Matches(msg, m.keys.Search):
+		u.searching = true
+		return m, u.search.Focus()
+	case key.Matches(msg, m.keys.Sort):
+		next := (slices.Index(db.UserSorts, u.query.sort) + 1) % len(db.UserSorts)
+		u.query.sort = db.UserSorts[next]
+		return m, LoadUsersCmd(m.service, u.query)
+	case key.Matches(msg, m.keys.Filter):
+		next := (slices.Index(usersStates, u.query.state) + 1) % len(usersStates)
+		u.query.state = usersStates[next]
+		return m, LoadUsersCmd(m.service, u.query)
+	case key.Matches(msg, m.keys.Refresh):
+		return m, LoadUsersCmd(m.service, u.query)
+	case key.Matches(msg, m.keys.Select):
+		if cursor := u.table.Cursor(); cursor < len(u.users) {
+			user := u.users[cursor]
+			u.detail = &user
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	u.table, cmd = u.table.Update(msg)
+	return m, cmd
+}
+
+// viewUsers renders the users screen
+func (m Model) viewUsers() string {
+	if m.users.detail != nil {
+		return m.viewUserDetail()
+	}
+
+	var sb strings.Builder
+	u := m.users
+
+	sb.WriteString(uiTitleStyle.Render("👥 Users") + "\n")
+	subtitle := fmt.Sprintf("%d users, %s, by %s", len(u.users), u.query.state, u.query.sort)
+	if len(u.users) == usersLimit {
+		subtitle = fmt.Sprintf("First %d users, %s, by %s", usersLimit, u.query.state, u.query.sort)
+	}
+	sb.WriteString(uiSubtitleStyle.Render(subtitle) + "\n\n")
+	if banner := pauseBanner(m.pause); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+	if banner := queuePauseBanner(m.queuePaused); banner != "" {
+		sb.WriteString(banner + "\n")
+	}
+
+	if u.searching || u.query.search != "" {
+		sb.WriteString(uiMenuItemStyle.Render(u.search.View()) + "\n")
+	}
+
+	switch {
+	case !u.loaded:
+		sb.WriteString(uiMenuItemStyle.Render("Loading...") + "\n")
+	case len(u.users) == 0:
+		sb.WriteString(uiMenuItemStyle.Render("No users match") + "\n")
+	default:
+		sb.WriteString(u.table.View() + "\n")
+	}
+
+	if m.status != nil {
+		sb.WriteString("\n" + uiStatusStyle.Render(FormatStatus(*m.status)) + "\n")
+	}
+
+	k := m.keys
+	help := m.helpLine(k.Up, k.Down, as(k.Select, "details"), k.Sort, as(k.Filter, "followed filter"), k.Search, k.Refresh, k.Back, k.Help, k.Quit)
+	if u.searching {
+		help = uiHelpStyle.Render("Type to search • Enter: Keep • esc: Clear")
+	}
+	sb.WriteString("\n" + help)
+	return sb.String()
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"bsky_follower/internal/config"
 	"bsky_follower/internal/ui"
@@ -18,8 +21,63 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Global flags come before the command
+	args := os.Args[1:]
+	noTUI := false
+flags:
+	for len(args) > 0 {
+		switch args[0] {
+		case "--ephemeral", "-ephemeral":
+			cfg.Ephemeral = true
+		case "--dry-run", "-dry-run":
+			cfg.DryRun = true
+		case "--no-tui", "-no-tui":
+			noTUI = true
+		default:
+			break flags
+		}
+		args = args[1:]
+	}
+
+	// Run a one-off command instead of the UI if one was given
+	if len(args) > 0 {
+		if err := runCommand(cfg, args[0], args[1:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Initialize the service
+	svc, err := newService(cfg)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer svc.Close()
+
+	// Without a terminal to draw on, run the same operations with plain output
+	if noTUI || !isTerminal(os.Stdout) || os.Getenv("TERM") == "dumb" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		// A second signal kills the process as usual
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+		err := ui.RunPlain(ctx, svc, os.Stdout)
+		if err == nil {
+			err = printDryRun(svc)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize UI
-	model := ui.NewModel(cfg)
+	model := ui.NewModel(cfg, svc)
 	program := tea.NewProgram(model)
 
 	// Run the program
@@ -28,3 +86,9 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}